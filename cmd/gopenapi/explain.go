@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/runpod/gopenapi"
+	"github.com/runpod/gopenapi/cmd/gopenapi/parser"
+)
+
+func explainCommand() {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
+	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
+	operationId := fs.String("operation", "", "operationId of the operation to describe (required, e.g., 'getUserById')")
+	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	help := fs.Bool("help", false, "Show help information")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Describe a single operation of an OpenAPI spec in Go code
+
+Usage:
+  gopenapi explain [flags]
+
+Flags:
+  -spec string
+        Go file containing the OpenAPI spec (required)
+  -var string
+        Variable name containing the spec (required, e.g., 'ExampleSpec')
+  -operation string
+        operationId of the operation to describe (required, e.g., 'getUserById')
+  -path string
+        Working directory for package resolution (defaults to current directory)
+  -help
+        Show this help message
+
+Prints the method, path, parameters, request and response schemas, and the
+security requirements of the operation.
+
+Examples:
+  gopenapi explain -spec examples/spec/spec.go -var ExampleSpec -operation getUserById
+`)
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *help {
+		fs.Usage()
+		return
+	}
+
+	if *specFile == "" || *specVar == "" || *operationId == "" {
+		fmt.Fprintf(os.Stderr, "Error: -spec, -var and -operation flags are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Use current directory if path not specified
+	workingDir := *path
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get current directory: %v", err)
+		}
+	}
+
+	spec, err := parser.ParseSpecFromFileWithPath(*specFile, *specVar, workingDir)
+	if err != nil {
+		log.Fatalf("Failed to parse spec from file: %v", err)
+	}
+
+	if err := explainOperation(os.Stdout, &spec, *operationId); err != nil {
+		log.Fatalf("Failed to explain operation: %v", err)
+	}
+}
+
+// explainMethods are the methods of a path item in the order of the OpenAPI document
+var explainMethods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// explainOperation writes a readable description of the operation with the
+// given operationId to w. Schemas are printed as they appear in the OpenAPI
+// JSON generated from the spec.
+func explainOperation(w io.Writer, spec *gopenapi.Spec, operationId string) error {
+	data, err := parser.SpecToOpenAPIJSON(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert spec to OpenAPI JSON: %w", err)
+	}
+	var doc struct {
+		Paths    map[string]map[string]map[string]any `json:"paths"`
+		Security []any                                `json:"security"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to decode OpenAPI JSON: %w", err)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		for _, method := range explainMethods {
+			operation, ok := doc.Paths[path][method]
+			if !ok || operation["operationId"] != operationId {
+				continue
+			}
+			security, inherited := doc.Security, true
+			if operationSecurity, ok := operation["security"].([]any); ok {
+				security, inherited = operationSecurity, false
+			}
+			writeOperation(w, strings.ToUpper(method), path, operation, security, inherited)
+			return nil
+		}
+	}
+	return fmt.Errorf("operation %q not found", operationId)
+}
+
+// writeOperation writes the sections of an operation decoded from the OpenAPI JSON
+func writeOperation(w io.Writer, method, path string, operation map[string]any, security []any, inherited bool) {
+	fmt.Fprintf(w, "%s %s\n", method, path)
+	if summary, ok := operation["summary"].(string); ok {
+		fmt.Fprintf(w, "  %s\n", summary)
+	}
+	if description, ok := operation["description"].(string); ok {
+		fmt.Fprintf(w, "  %s\n", description)
+	}
+
+	fmt.Fprintf(w, "\nParameters:\n")
+	parameters, _ := operation["parameters"].([]any)
+	if len(parameters) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	for _, p := range parameters {
+		param, _ := p.(map[string]any)
+		schema, _ := param["schema"].(map[string]any)
+		required := "optional"
+		if param["required"] == true {
+			required = "required"
+		}
+		fmt.Fprintf(w, "  %s (%s, %s, %s)", param["name"], param["in"], schemaTypeName(schema), required)
+		if description, _ := param["description"].(string); description != "" {
+			fmt.Fprintf(w, " - %s", description)
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\nRequest body:\n")
+	if requestBody, ok := operation["requestBody"].(map[string]any); ok {
+		if requestBody["required"] == true {
+			fmt.Fprintf(w, "  required\n")
+		}
+		content, _ := requestBody["content"].(map[string]any)
+		writeContent(w, content)
+	} else {
+		fmt.Fprintf(w, "  none\n")
+	}
+
+	fmt.Fprintf(w, "\nResponses:\n")
+	responses, _ := operation["responses"].(map[string]any)
+	statuses := make([]string, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		response, _ := responses[status].(map[string]any)
+		fmt.Fprintf(w, "  %s %s\n", status, response["description"])
+		content, _ := response["content"].(map[string]any)
+		writeContent(w, content)
+	}
+
+	fmt.Fprintf(w, "\nSecurity:\n")
+	var schemes []string
+	for _, requirement := range security {
+		names, _ := requirement.(map[string]any)
+		for name := range names {
+			schemes = append(schemes, name)
+		}
+	}
+	sort.Strings(schemes)
+	switch {
+	case len(schemes) == 0:
+		fmt.Fprintf(w, "  none (public)\n")
+	case inherited:
+		fmt.Fprintf(w, "  %s (inherited from the spec)\n", strings.Join(schemes, ", "))
+	default:
+		fmt.Fprintf(w, "  %s\n", strings.Join(schemes, ", "))
+	}
+}
+
+// writeContent writes the schema of each media type as indented JSON
+func writeContent(w io.Writer, content map[string]any) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		media, _ := content[mediaType].(map[string]any)
+		schema, err := json.MarshalIndent(media["schema"], "      ", "  ")
+		if err != nil {
+			schema = []byte("?")
+		}
+		fmt.Fprintf(w, "    %s: %s\n", mediaType, schema)
+	}
+}
+
+// schemaTypeName summarizes a decoded schema as a short type name, e.g.
+// "string (uuid)", "array of integer" or a referenced component's name
+func schemaTypeName(schema map[string]any) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+	typeName, _ := schema["type"].(string)
+	if typeName == "" {
+		return "any"
+	}
+	if typeName == "array" {
+		items, _ := schema["items"].(map[string]any)
+		return "array of " + schemaTypeName(items)
+	}
+	if format, ok := schema["format"].(string); ok {
+		return fmt.Sprintf("%s (%s)", typeName, format)
+	}
+	return typeName
+}
@@ -1,16 +1,26 @@
 package generator
 
 import (
+	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
+	"go/format"
 	"io"
+	"math"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/runpod/gopenapi"
 )
@@ -18,31 +28,265 @@ import (
 //go:embed templates/*.tpl
 var templateFS embed.FS
 
+// EnumStyle selects how schema enums are rendered in generated TypeScript
+type EnumStyle string
+
+const (
+	// EnumStyleUnion renders enums as string literal unions (type Status = "a" | "b")
+	EnumStyleUnion EnumStyle = "union"
+	// EnumStyleEnum renders enums as TypeScript enums (enum Status { A = "a" })
+	EnumStyleEnum EnumStyle = "enum"
+)
+
+// Options configures client generation
+type Options struct {
+	// PackageName is the package name used for generated code
+	PackageName string
+	// TypeScriptEnumStyle selects union or enum output for schema enums; defaults to EnumStyleUnion
+	TypeScriptEnumStyle EnumStyle
+	// SplitModels moves the Go request and response model structs out of client.go
+	// into models.go in the same package
+	SplitModels bool
+	// TagClients groups Go client methods into sub-clients by the first tag of
+	// each operation, e.g. client.Users().GetUser; untagged operations stay on Client
+	TagClients bool
+	// PythonAsync generates an asyncio Python client, AsyncClient, built on aiohttp
+	// instead of the synchronous requests-based Client
+	PythonAsync bool
+	// AllowDuplicateIds suffixes operations whose operationIds generate the same
+	// method name with 2, 3, ... in path and method order; by default such specs
+	// are rejected
+	AllowDuplicateIds bool
+	// ModelsPackage is the import path of a separate package the Go model structs
+	// are generated into, e.g. "example.com/api/client/models". The package is
+	// named after the last path element and written to that subdirectory of the
+	// output directory. Implies SplitModels.
+	ModelsPackage string
+	// NoContext generates Go client methods without a ctx context.Context
+	// parameter, sending requests with context.Background()
+	NoContext bool
+	// ValidatorTags adds go-playground/validator `validate` tags derived from
+	// the schema constraints to the fields of generated Go structs, e.g.
+	// validate:"required,min=1" for a required parameter with a minimum of 1
+	ValidatorTags bool
+	// QueryStructs passes the query parameters of Go client methods as a
+	// separate <Operation>Query argument, e.g.
+	// client.ListUsers(ctx, &ListUsersQuery{Limit: 10}, nil), instead of the
+	// Query field of the options, so that queries can be built and reused on their own
+	QueryStructs bool
+	// TypeName names the struct elements of top-level array and map bodies,
+	// e.g. User for []User, when their reflect.Type has no name, as for types
+	// built by the parser from spec source. Defaults to reflect.Type.Name.
+	TypeName func(reflect.Type) string
+}
+
 type TemplateData struct {
 	PackageName string
 	ClientName  string // For non-Go languages, this will be "Api" instead of package name
 	Operations  []OperationData
+	Options     Options
+	// DefaultBaseURL is the first absolute server URL in the spec, empty when there is none
+	DefaultBaseURL string
+	// HasAPIKeyAuth and HasBearerAuth report whether any operation uses an API key
+	// or bearer token security scheme, so clients only expose the credentials they need
+	HasAPIKeyAuth bool
+	HasBearerAuth bool
+	// ModelsOnly renders only the model structs, for the models.go file of split Go output
+	ModelsOnly bool
+	// TagClients lists the sub-clients generated with Options.TagClients, sorted by name
+	TagClients []TagClientData
+	// Models are the struct types of the elements of top-level slice, array and
+	// map bodies, e.g. User for []User, shared by operations and sorted by name
+	Models []StructData
+}
+
+// TagClientData describes a Go sub-client grouping the operations of one tag
+type TagClientData struct {
+	Name string // Go name, e.g. "Users" for UsersClient and Client.Users()
+	Tag  string // Tag as declared in the spec
+}
+
+// HasMultipartResponses reports whether any operation streams a multipart
+// response, so the client only includes the multipart reader when it is used
+func (d *TemplateData) HasMultipartResponses() bool {
+	for _, op := range d.Operations {
+		if op.MultipartResponse {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPaginatedOperations reports whether any operation has a page iterator, so
+// the client only includes PageIterator when it is used
+func (d *TemplateData) HasPaginatedOperations() bool {
+	for _, op := range d.Operations {
+		if op.Paginated {
+			return true
+		}
+	}
+	return false
+}
+
+// GoImports returns the standard library packages used by the generated Go client,
+// or by its models.go when ModelsOnly is set, so that operations without request
+// bodies or typed parameters do not leave unused imports behind
+func (d *TemplateData) GoImports() []string {
+	modelsUseTime := false
+	modelsUseIO := false
+	modelsUseHTTP := false
+	modelsUseStrconv := false
+	for _, op := range d.Operations {
+		for _, getter := range op.HeaderGetters {
+			modelsUseHTTP = true
+			if strings.Contains(getter.ParseHeader, "strconv.") {
+				modelsUseStrconv = true
+			}
+		}
+		fields := append(append([]FieldData{}, op.RequestBodyFields...), op.ResponseFields...)
+		for _, nested := range op.NestedStructs {
+			fields = append(fields, nested.Fields...)
+		}
+		for _, model := range d.Models {
+			fields = append(fields, model.Fields...)
+		}
+		for _, field := range fields {
+			if strings.Contains(field.GoType, "time.Time") {
+				modelsUseTime = true
+			}
+			if strings.Contains(field.GoType, "io.Reader") {
+				modelsUseIO = true
+			}
+		}
+	}
+	if d.ModelsOnly {
+		var imports []string
+		if modelsUseIO {
+			imports = append(imports, "io")
+		}
+		if modelsUseHTTP {
+			imports = append(imports, "net/http")
+		}
+		if modelsUseStrconv {
+			imports = append(imports, "strconv")
+		}
+		if modelsUseTime {
+			imports = append(imports, "time")
+		}
+		return imports
+	}
+
+	used := map[string]bool{
+		"bytes":    true,
+		"context":  true,
+		"fmt":      true,
+		"io":       true,
+		"net/http": true,
+		"net/url":  true,
+		"strconv":  true,
+		"strings":  true,
+		"sync":     true,
+		"time":     true,
+	}
+
+	for _, op := range d.Operations {
+		if op.HasRequestBody && op.RequestMediaType != string(gopenapi.MultipartFormData) {
+			used["encoding/json"] = true
+		}
+		for _, field := range op.RequestBodyFields {
+			if strings.Contains(field.WriteMultipart, "json.") {
+				used["encoding/json"] = true
+			}
+			if strings.Contains(field.WriteMultipart, "strconv.") {
+				used["strconv"] = true
+			}
+		}
+		if op.RequestMediaType == string(gopenapi.MultipartFormData) {
+			used["mime/multipart"] = true
+		}
+		if op.MultipartResponse {
+			used["mime"] = true
+			used["mime/multipart"] = true
+		}
+		if op.HasResponseBody && (len(op.ResponseFields) > 0 || op.ResponseType != "") && !op.returnsRawBody() {
+			used["encoding/json"] = true
+		}
+		for _, params := range [][]ParamData{op.PathParams, op.QueryParams, op.HeaderParams} {
+			for _, param := range params {
+				if strings.Contains(param.ConvertToString+param.AddToParams+param.SetHeader, "strconv.") {
+					used["strconv"] = true
+				}
+			}
+		}
+	}
+
+	imports := make([]string, 0, len(used))
+	for pkg := range used {
+		imports = append(imports, pkg)
+	}
+	sort.Strings(imports)
+	return imports
 }
 
 type OperationData struct {
-	OperationId       string
-	Method            string
-	Path              string
-	Description       string
-	StructName        string
-	MethodName        string // Go method name (properly capitalized camelCase)
-	HasPathParams     bool
-	HasQueryParams    bool
-	HasHeaderParams   bool
-	HasRequestBody    bool
-	HasResponseBody   bool
-	HasAnyParams      bool   // True if any of the above params exist
-	ResponseType      string // For simple types like "string", "int", etc. Empty if ResponseFields is used
-	PathParams        []ParamData
-	QueryParams       []ParamData
-	HeaderParams      []ParamData
-	RequestBodyFields []FieldData
-	ResponseFields    []FieldData
+	OperationId        string
+	Method             string
+	Path               string
+	Description        string
+	StructName         string
+	MethodName         string // Go method name (properly capitalized camelCase)
+	HasPathParams      bool
+	HasQueryParams     bool
+	HasHeaderParams    bool
+	HasRequestBody     bool
+	RequestMediaType   string // Media type the request body is sent as, e.g. "multipart/form-data"
+	HasResponseBody    bool
+	NoContent          bool        // The success response declares no content, e.g. 204 No Content
+	HasAnyParams       bool        // True if any of the above params exist
+	HasOptions         bool        // The Go method takes an <Operation>Options, i.e. it has parameters not passed as a QueryStruct
+	HasQueryDefaults   bool        // True if any query parameter declares a default value
+	ResponseType       string      // For simple types like "string", "int", etc. Empty if ResponseFields is used
+	ResponseMediaTypes []string    // All media types offered by the success response, sorted
+	ResponseFormat     string      // How the response body is decoded: "json", "text" or "binary"; empty without a body
+	MultipartResponse  bool        // The success response is multipart, e.g. multipart/mixed, and can be streamed part by part
+	Paginated          bool        // GET operation marked x-pagination with a JSON response, given a <Method>Pages iterator
+	ResponseHeaders    []ParamData // Headers declared on the success response, sorted by name
+	HeaderGetters      []ParamData // Typed accessors for ResponseHeaders on the response struct, named by GoName
+	PathParams         []ParamData
+	QueryParams        []ParamData
+	HeaderParams       []ParamData
+	RequestBodyFields  []FieldData
+	RequestBodyType    string // Go type of a top-level slice, array or map request body, e.g. "[]User"; empty for objects
+	ResponseFields     []FieldData
+	NestedStructs      []StructData // Named types for struct-typed fields of the request and response bodies
+	Enums              []EnumData   // Named enum types for enum-constrained parameters
+	Auth               []AuthData   // Security schemes applied to the request, sorted by scheme name
+	Timeout            string       // Go expression for the x-timeout default, e.g. "2 * time.Minute"; empty when unset
+	Tag                string       // First tag of the operation, empty when untagged
+	TagClient          string       // Name of the sub-client the Go method belongs to; empty for the root Client
+	ModelsQualifier    string       // Qualifier of model types in Go client code, e.g. "models."; empty when they share its package
+	NoContext          bool         // Go methods take no ctx parameter and use context.Background()
+	ValidatorTags      bool         // Go struct fields carry their Validate tags
+	QueryStruct        bool         // Go methods take the query parameters as a separate *<Operation>Query argument
+	QueryType          string       // Name of the Go struct of the query parameters, e.g. "ListUsersQueryParams"
+}
+
+// AuthData describes how a security scheme is applied to requests by generated clients
+type AuthData struct {
+	Scheme string // Name of the security scheme in the spec
+	Kind   string // "apiKey" or "bearer"
+	In     string // Location of an API key: "header", "query" or "cookie"
+	Name   string // Header, query parameter or cookie name of an API key
+}
+
+type EnumData struct {
+	TypeName string
+	Values   []EnumValue
+}
+
+type EnumValue struct {
+	Name    string // Identifier for the value, used by enum-style output
+	Literal string // Value as a source literal, e.g. "active" or 3
 }
 
 type ParamData struct {
@@ -53,16 +297,35 @@ type ParamData struct {
 	AddToParams     string
 	SetHeader       string
 	PathPattern     string // For path parameter replacement
+	EnumType        string // Named enum type when the schema declares enum values
+	Default         string // Go literal of the schema default, empty when there is none
+	ParseHeader     string // Body of the typed accessor of a response header
+	Validate        string // go-playground/validator tag of the field, e.g. "required,min=1"
 }
 
 type FieldData struct {
+	Name           string
+	GoName         string
+	GoType         string
+	OmitEmpty      bool   // The json tag of the field sets omitempty, so the field is optional
+	WriteMultipart string // Code writing the field of a multipart/form-data request body
+	Validate       string // go-playground/validator tag of the field, e.g. "omitempty,email"
+}
+
+// StructData is a named Go struct generated for a nested object in a request
+// or response body, e.g. CreateOrderRequestBodyAddress
+type StructData struct {
 	Name   string
-	GoName string
-	GoType string
+	Fields []FieldData
 }
 
 // GenerateClientToStdout generates a client for the specified language and outputs to stdout
 func GenerateClientToStdout(spec *gopenapi.Spec, language, packageName string) error {
+	return GenerateClientToStdoutWithOptions(spec, language, Options{PackageName: packageName})
+}
+
+// GenerateClientToStdoutWithOptions generates a client for the specified language using the given options and outputs to stdout
+func GenerateClientToStdoutWithOptions(spec *gopenapi.Spec, language string, opts Options) error {
 	// Determine template file based on language
 	var templateFile string
 
@@ -77,11 +340,16 @@ func GenerateClientToStdout(spec *gopenapi.Spec, language, packageName string) e
 		return fmt.Errorf("unsupported language: %s", language)
 	}
 
-	return GenerateClientToWriter(spec, os.Stdout, packageName, templateFile, language)
+	return GenerateClientToWriterWithOptions(spec, os.Stdout, templateFile, language, opts)
 }
 
 // GenerateClientForLanguage generates a client for the specified language
 func GenerateClientForLanguage(spec *gopenapi.Spec, language, outputDir, packageName string) error {
+	return GenerateClientForLanguageWithOptions(spec, language, outputDir, Options{PackageName: packageName})
+}
+
+// GenerateClientForLanguageWithOptions generates a client for the specified language using the given options
+func GenerateClientForLanguageWithOptions(spec *gopenapi.Spec, language, outputDir string, opts Options) error {
 	// Determine template file and output file based on language
 	var templateFile, outputFile string
 
@@ -89,6 +357,16 @@ func GenerateClientForLanguage(spec *gopenapi.Spec, language, outputDir, package
 	case "go":
 		templateFile = "templates/go.tpl"
 		outputFile = filepath.Join(outputDir, "client.go")
+		if opts.ModelsPackage != "" {
+			modelsFile := filepath.Join(outputDir, path.Base(opts.ModelsPackage), "models.go")
+			if err := GenerateModelsWithOptions(spec, modelsFile, opts); err != nil {
+				return err
+			}
+		} else if opts.SplitModels {
+			if err := GenerateModelsWithOptions(spec, filepath.Join(outputDir, "models.go"), opts); err != nil {
+				return err
+			}
+		}
 	case "python":
 		templateFile = "templates/python.tpl"
 		outputFile = filepath.Join(outputDir, "client.py")
@@ -99,11 +377,52 @@ func GenerateClientForLanguage(spec *gopenapi.Spec, language, outputDir, package
 		return fmt.Errorf("unsupported language: %s", language)
 	}
 
-	return GenerateClient(spec, outputFile, packageName, templateFile, language)
+	return GenerateClientWithOptions(spec, outputFile, templateFile, language, opts)
 }
 
 // GenerateClientToWriter generates a client from a gopenapi.Spec and writes to the provided writer
 func GenerateClientToWriter(spec *gopenapi.Spec, writer io.Writer, packageName, templateFile, language string) error {
+	return GenerateClientToWriterWithOptions(spec, writer, templateFile, language, Options{PackageName: packageName})
+}
+
+// GenerateClientToWriterWithOptions generates a client from a gopenapi.Spec using the given options and writes to the provided writer
+func GenerateClientToWriterWithOptions(spec *gopenapi.Spec, writer io.Writer, templateFile, language string, opts Options) error {
+	templateData, err := generateTemplateDataWithOptions(spec, opts)
+	if err != nil {
+		return err
+	}
+	return renderToWriter(writer, templateFile, language, templateData)
+}
+
+// GenerateModelsWithOptions writes the Go model structs of a client generated
+// with SplitModels or ModelsPackage to outputFile
+func GenerateModelsWithOptions(spec *gopenapi.Spec, outputFile string, opts Options) error {
+	outFile, err := createOutputFile(outputFile)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	return GenerateModelsToWriterWithOptions(spec, outFile, opts)
+}
+
+// GenerateModelsToWriterWithOptions writes the Go model structs of a client
+// generated with SplitModels or ModelsPackage to the provided writer
+func GenerateModelsToWriterWithOptions(spec *gopenapi.Spec, writer io.Writer, opts Options) error {
+	templateData, err := generateTemplateDataWithOptions(spec, opts)
+	if err != nil {
+		return err
+	}
+	templateData.ModelsOnly = true
+	if opts.ModelsPackage != "" {
+		templateData.PackageName = path.Base(opts.ModelsPackage)
+	}
+	return renderToWriter(writer, "templates/go.tpl", "go", templateData)
+}
+
+// renderToWriter executes a client template with the given data and writes the
+// result, gofmt'd for Go, to writer
+func renderToWriter(writer io.Writer, templateFile, language string, templateData any) error {
 	// Load template from embedded filesystem
 	tmplContent, err := templateFS.ReadFile(templateFile)
 	if err != nil {
@@ -116,36 +435,61 @@ func GenerateClientToWriter(spec *gopenapi.Spec, writer io.Writer, packageName,
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	// Generate template data
-	templateData := generateTemplateData(spec, packageName)
-
 	// Execute template
-	if err := tmpl.Execute(writer, templateData); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
+	output := buf.Bytes()
+	if language == "go" {
+		formatted, err := format.Source(output)
+		if err != nil {
+			return fmt.Errorf("failed to format generated Go code: %w", err)
+		}
+		output = formatted
+	}
+
+	if _, err := writer.Write(output); err != nil {
+		return fmt.Errorf("failed to write generated client: %w", err)
+	}
+
 	return nil
 }
 
 // GenerateClient generates a client from a gopenapi.Spec
 func GenerateClient(spec *gopenapi.Spec, outputFile, packageName, templateFile, language string) error {
+	return GenerateClientWithOptions(spec, outputFile, templateFile, language, Options{PackageName: packageName})
+}
+
+// GenerateClientWithOptions generates a client from a gopenapi.Spec using the given options
+func GenerateClientWithOptions(spec *gopenapi.Spec, outputFile, templateFile, language string, opts Options) error {
+	outFile, err := createOutputFile(outputFile)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	// Use the writer-based function
+	return GenerateClientToWriterWithOptions(spec, outFile, templateFile, language, opts)
+}
+
+// createOutputFile creates outputFile along with its parent directory
+func createOutputFile(outputFile string) (*os.File, error) {
 	// Create output directory
 	outputDir := filepath.Dir(outputFile)
 	if outputDir != "." {
 		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
 		}
 	}
 
 	// Create output file
 	outFile, err := os.Create(outputFile)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return nil, fmt.Errorf("failed to create output file: %w", err)
 	}
-	defer outFile.Close()
-
-	// Use the writer-based function
-	return GenerateClientToWriter(spec, outFile, packageName, templateFile, language)
+	return outFile, nil
 }
 
 // getTemplateFuncs returns template functions for the specified language
@@ -165,6 +509,8 @@ func getTemplateFuncs(language string) template.FuncMap {
 	case "python":
 		funcs["snake_case"] = toSnakeCase
 		funcs["python_type"] = toPythonType
+		funcs["python_optional_type"] = toPythonOptionalType
+		funcs["python_from_json"] = toPythonFromJSON
 	case "typescript":
 		funcs["camel_case"] = toCamelCase
 		funcs["typescript_type"] = toTypeScriptType
@@ -186,24 +532,75 @@ func toSnakeCase(s string) string {
 	return strings.ToLower(s)
 }
 
-// toPythonType converts Go types to Python types
+// toPythonType converts Go types to Python types. Slices, string-keyed maps and
+// pointers map to List, Dict and Optional; generated nested structs keep their
+// names, referring to the dataclasses emitted for them.
 func toPythonType(goType string) string {
+	if goType == "[]byte" {
+		return "bytes"
+	}
+	if elem, ok := strings.CutPrefix(goType, "*"); ok {
+		return "Optional[" + toPythonType(elem) + "]"
+	}
+	if elem, ok := strings.CutPrefix(goType, "map[string]"); ok {
+		return "Dict[str, " + toPythonType(elem) + "]"
+	}
+	if match := goSliceTypePattern.FindStringSubmatch(goType); match != nil {
+		return "List[" + toPythonType(match[1]) + "]"
+	}
+
 	switch goType {
-	case "string":
+	case "string", "time.Time":
 		return "str"
-	case "int":
+	case "int", "uint":
 		return "int"
 	case "float64":
 		return "float"
 	case "bool":
 		return "bool"
-	case "[]interface{}":
-		return "List[Any]"
 	default:
+		if isNestedStructType(goType) {
+			return goType
+		}
 		return "Any"
 	}
 }
 
+// toPythonOptionalType converts a Go type to an Optional Python type, without
+// wrapping pointers twice
+func toPythonOptionalType(goType string) string {
+	return "Optional[" + toPythonType(strings.TrimPrefix(goType, "*")) + "]"
+}
+
+// toPythonFromJSON returns the Python expression reading the JSON field name
+// from data, building the dataclasses of nested structs and lists or maps of them
+func toPythonFromJSON(name, goType string) string {
+	value := fmt.Sprintf("data.get(%q)", name)
+	goType = strings.TrimPrefix(goType, "*")
+
+	var expr string
+	if isNestedStructType(goType) {
+		expr = fmt.Sprintf("%s.from_dict(%s)", goType, value)
+	} else if elem, ok := strings.CutPrefix(goType, "[]"); ok && isNestedStructType(elem) {
+		expr = fmt.Sprintf("[%s.from_dict(item) for item in %s]", elem, value)
+	} else if elem, ok := strings.CutPrefix(goType, "map[string]"); ok && isNestedStructType(elem) {
+		expr = fmt.Sprintf("{key: %s.from_dict(item) for key, item in %s.items()}", elem, value)
+	} else {
+		return value
+	}
+	return fmt.Sprintf("%s if %s is not None else None", expr, value)
+}
+
+// goSliceTypePattern matches Go slice and array types, capturing the element type
+var goSliceTypePattern = regexp.MustCompile(`^\[\d*\](.+)$`)
+
+// isNestedStructType reports whether goType names a struct generated for a
+// nested object, e.g. CreateOrderRequestBodyAddress. Field types are otherwise
+// built from predeclared types, time.Time and io.Reader.
+func isNestedStructType(goType string) bool {
+	return goType != "" && unicode.IsUpper(rune(goType[0])) && !strings.ContainsAny(goType, ".[]*{}")
+}
+
 // toCamelCase converts a string to camelCase
 func toCamelCase(s string) string {
 	if s == "" {
@@ -219,26 +616,63 @@ func toCamelCase(s string) string {
 	return strings.ToLower(s[:1]) + s[1:]
 }
 
-// toTypeScriptType converts Go types to TypeScript types
+// toTypeScriptType converts Go types to TypeScript types. Slices, string-keyed
+// maps and pointers map to T[], Record<string, V> and T | null; generated nested
+// structs keep their names, referring to the interfaces emitted for them.
 func toTypeScriptType(goType string) string {
+	if goType == "[]byte" {
+		return "ArrayBuffer"
+	}
+	if elem, ok := strings.CutPrefix(goType, "*"); ok {
+		return toTypeScriptType(elem) + " | null"
+	}
+	if elem, ok := strings.CutPrefix(goType, "map[string]"); ok {
+		return "Record<string, " + toTypeScriptType(elem) + ">"
+	}
+	if match := goSliceTypePattern.FindStringSubmatch(goType); match != nil {
+		elem := toTypeScriptType(match[1])
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	}
+
 	switch goType {
-	case "string":
+	case "string", "time.Time":
 		return "string"
-	case "int":
-		return "number"
-	case "float64":
+	case "int", "uint", "float64":
 		return "number"
 	case "bool":
 		return "boolean"
-	case "[]interface{}":
-		return "any[]"
 	default:
+		if isNestedStructType(goType) {
+			return goType
+		}
 		return "any"
 	}
 }
 
-func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData {
+func generateTemplateData(spec *gopenapi.Spec, packageName string) (*TemplateData, error) {
+	return generateTemplateDataWithOptions(spec, Options{PackageName: packageName})
+}
+
+func generateTemplateDataWithOptions(spec *gopenapi.Spec, opts Options) (*TemplateData, error) {
+	if opts.TypeScriptEnumStyle == "" {
+		opts.TypeScriptEnumStyle = EnumStyleUnion
+	}
+	modelsQualifier := ""
+	if opts.ModelsPackage != "" {
+		opts.SplitModels = true
+		modelsQualifier = path.Base(opts.ModelsPackage) + "."
+	}
+
+	operationIds, err := resolveOperationIds(spec, opts.AllowDuplicateIds)
+	if err != nil {
+		return nil, err
+	}
+
 	var operations []OperationData
+	models := newModelRegistry(opts.TypeName)
 
 	for path, pathItem := range spec.Paths {
 		methodOps := map[string]*gopenapi.Operation{
@@ -261,17 +695,28 @@ func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData
 				continue
 			}
 
+			operationId := operationIds[method+" "+path]
 			opData := OperationData{
-				OperationId: operation.OperationId,
+				OperationId: operationId,
 				Method:      method,
 				Path:        path,
 				Description: operation.Description,
-				StructName:  ToStructName(operation.OperationId),
-				MethodName:  ToMethodName(operation.OperationId),
+				StructName:  ToStructName(operationId),
+				MethodName:  ToMethodName(operationId),
+			}
+			opData.ModelsQualifier = modelsQualifier
+			opData.NoContext = opts.NoContext
+			opData.ValidatorTags = opts.ValidatorTags
+
+			// Process parameters, including those shared by the path
+			parameters := pathItem.OperationParameters(operation)
+			grouped := parameters.Group()
+			required := map[string]bool{}
+			for _, parameter := range parameters {
+				if parameter.Required || parameter.In == gopenapi.InPath {
+					required[string(parameter.In)+" "+parameter.Name] = true
+				}
 			}
-
-			// Process parameters
-			grouped := operation.Parameters.Group()
 
 			// Path parameters
 			if len(grouped.Path) > 0 {
@@ -282,8 +727,10 @@ func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData
 						GoName:      ToGoName(name),
 						GoType:      SchemaToGoType(schema),
 						PathPattern: "{" + name + "}",
+						Validate:    validateTag(schema, true),
 					}
 					param.ConvertToString = generateConvertToString(param.GoName, param.GoType)
+					opData.addEnum(&param, schema)
 					opData.PathParams = append(opData.PathParams, param)
 				}
 			}
@@ -291,13 +738,32 @@ func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData
 			// Query parameters
 			if len(grouped.Query) > 0 {
 				opData.HasQueryParams = true
+				opData.QueryStruct = opts.QueryStructs
+				opData.QueryType = opData.StructName + "QueryParams"
+				query := "opts.Query"
+				if opData.QueryStruct {
+					opData.QueryType = opData.StructName + "Query"
+					query = "query"
+				}
+				explode := map[string]bool{}
+				for _, parameter := range parameters {
+					if parameter.In == gopenapi.InQuery {
+						explode[parameter.Name] = parameter.Explode == nil || *parameter.Explode
+					}
+				}
 				for name, schema := range grouped.Query {
 					param := ParamData{
-						Name:   name,
-						GoName: ToGoName(name),
-						GoType: SchemaToGoType(schema),
+						Name:     name,
+						GoName:   ToGoName(name),
+						GoType:   SchemaToGoType(schema),
+						Validate: validateTag(schema, required[string(gopenapi.InQuery)+" "+name]),
+					}
+					param.AddToParams = generateAddToParams(query, param.GoName, param.GoType, name, explode[name])
+					if literal, ok := goLiteral(schema.Default, param.GoType); ok {
+						param.Default = literal
+						opData.HasQueryDefaults = true
 					}
-					param.AddToParams = generateAddToParams(param.GoName, param.GoType, name)
+					opData.addEnum(&param, schema)
 					opData.QueryParams = append(opData.QueryParams, param)
 				}
 			}
@@ -307,11 +773,13 @@ func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData
 				opData.HasHeaderParams = true
 				for name, schema := range grouped.Header {
 					param := ParamData{
-						Name:   name,
-						GoName: ToGoName(name),
-						GoType: SchemaToGoType(schema),
+						Name:     name,
+						GoName:   ToGoName(name),
+						GoType:   SchemaToGoType(schema),
+						Validate: validateTag(schema, required[string(gopenapi.InHeader)+" "+name]),
 					}
 					param.SetHeader = generateSetHeader(param.GoName, param.GoType, name)
+					opData.addEnum(&param, schema)
 					opData.HeaderParams = append(opData.HeaderParams, param)
 				}
 			}
@@ -319,128 +787,455 @@ func generateTemplateData(spec *gopenapi.Spec, packageName string) *TemplateData
 			// Request body
 			if operation.RequestBody.Content != nil {
 				opData.HasRequestBody = true
-				for _, content := range operation.RequestBody.Content {
-					if content.Schema.Type != nil {
-						requestBodyStructName := opData.StructName + "RequestBody"
-						opData.RequestBodyFields = schemaToFieldsWithName(content.Schema, requestBodyStructName)
-						break
+				if mediaType, ok := preferredMediaType(operation.RequestBody.Content); ok {
+					requestBodyStructName := opData.StructName + "RequestBody"
+					schema := operation.RequestBody.Content[mediaType].Schema
+					fields, nested := schemaToFieldsWithName(schema, requestBodyStructName)
+					opData.RequestMediaType = string(mediaType)
+					if mediaType != gopenapi.MultipartFormData {
+						opData.RequestBodyType = models.goType(schema, requestBodyStructName+"Item")
+					}
+					if mediaType == gopenapi.MultipartFormData {
+						for i := range fields {
+							fields[i].WriteMultipart = generateWriteMultipart(fields[i].GoName, fields[i].GoType, fields[i].Name)
+						}
 					}
+					opData.RequestBodyFields = fields
+					opData.NestedStructs = append(opData.NestedStructs, nested...)
 				}
 			}
 
 			// Response body
-			if operation.Responses != nil {
-				for statusCode, response := range operation.Responses {
-					if statusCode >= 200 && statusCode < 300 && response.Content != nil {
-						for _, content := range response.Content {
-							if content.Schema.Type != nil {
-								opData.HasResponseBody = true
-
-								// Check if this is a simple type or a struct
-								if content.Schema.Type.Kind() == reflect.Struct {
-									// Complex type - create response struct
-									responseStructName := opData.StructName + "Response"
-									opData.ResponseFields = schemaToFieldsWithName(content.Schema, responseStructName)
-									opData.ResponseType = ""
-
-								} else {
-									// Simple type - no response struct needed, just use the type directly
-									opData.ResponseFields = nil
-									opData.ResponseType = SchemaToGoType(content.Schema)
-
-								}
-								break
-							}
+			if statusCode, ok := successStatusCode(operation.Responses); ok {
+				response := operation.Responses[statusCode]
+				opData.NoContent = len(response.Content) == 0
+				opData.ResponseMediaTypes = sortedMediaTypes(response.Content)
+				for _, mediaType := range opData.ResponseMediaTypes {
+					if strings.HasPrefix(mediaType, "multipart/") {
+						opData.MultipartResponse = true
+					}
+				}
+				for _, name := range sortedHeaderNames(response.Headers) {
+					opData.ResponseHeaders = append(opData.ResponseHeaders, ParamData{
+						Name:   name,
+						GoName: ToGoName(name),
+						GoType: SchemaToGoType(response.Headers[name].Schema),
+					})
+				}
+				if mediaType, ok := preferredMediaType(response.Content); ok {
+					schema := response.Content[mediaType].Schema
+					opData.HasResponseBody = true
+					opData.ResponseFormat = responseFormat(mediaType, schema)
+
+					// Check if this is a simple type or a struct
+					if schema.Type.Kind() == reflect.Struct {
+						// Complex type - create response struct
+						responseStructName := opData.StructName + "Response"
+						fields, nested := schemaToFieldsWithName(schema, responseStructName)
+						opData.ResponseFields = fields
+						opData.NestedStructs = append(opData.NestedStructs, nested...)
+						opData.ResponseType = ""
+						opData.HeaderGetters = headerGetters(opData.ResponseHeaders, fields)
+					} else {
+						// Simple type - no response struct needed, just use the type directly
+						opData.ResponseFields = nil
+						opData.ResponseType = SchemaToGoType(schema)
+						if goType := models.goType(schema, opData.StructName+"ResponseItem"); goType != "" {
+							opData.ResponseType = goType
+						}
+						if opData.ResponseFormat == "binary" {
+							opData.ResponseType = "[]byte"
+						}
+					}
+				} else {
+					// Binary media types are often declared without a schema
+					for _, mediaType := range opData.ResponseMediaTypes {
+						if responseFormat(gopenapi.MediaType(mediaType), gopenapi.Schema{}) == "binary" {
+							opData.HasResponseBody = true
+							opData.ResponseFormat = "binary"
+							opData.ResponseType = "[]byte"
+							break
 						}
-						break
 					}
 				}
 			}
 
+			// Security
+			opData.Auth = authData(spec, operation)
+
+			if operation.Timeout > 0 {
+				opData.Timeout = goDurationExpr(operation.Timeout)
+			}
+			// Streamed, multipart and raw bodies cannot be decoded into pages
+			opData.Paginated = operation.Paginated && method == "GET" && opData.ResponseFormat == "json" && !opData.MultipartResponse
+			if len(operation.Tags) > 0 {
+				opData.Tag = operation.Tags[0]
+			}
+
 			// Set HasAnyParams
 			opData.HasAnyParams = opData.HasPathParams || opData.HasQueryParams || opData.HasHeaderParams || opData.HasRequestBody
+			opData.HasOptions = opData.HasPathParams || (opData.HasQueryParams && !opData.QueryStruct) || opData.HasHeaderParams || opData.HasRequestBody
 
 			operations = append(operations, opData)
 		}
 	}
 
-	return &TemplateData{
-		PackageName: packageName,
+	data := &TemplateData{
+		PackageName: opts.PackageName,
 		ClientName:  "", // Always empty - class/struct should just be "Client"
 		Operations:  operations,
+		Options:     opts,
+		Models:      models.sorted(),
+	}
+	if opts.TagClients {
+		data.TagClients = groupByTag(operations)
+	}
+	for _, server := range spec.Servers {
+		if u, err := url.Parse(server.URL); err == nil && u.Scheme != "" && u.Host != "" {
+			data.DefaultBaseURL = server.URL
+			break
+		}
 	}
+	for _, op := range operations {
+		for _, auth := range op.Auth {
+			switch auth.Kind {
+			case "apiKey":
+				data.HasAPIKeyAuth = true
+			case "bearer":
+				data.HasBearerAuth = true
+			}
+		}
+	}
+	return data, nil
 }
 
-func ToStructName(operationId string) string {
-	// Convert operationId to PascalCase struct name
-	if operationId == "" {
-		return ""
+// resolveOperationIds returns the operationId to generate for each operation,
+// keyed by "METHOD path". Operations whose operationIds generate the same Go
+// name are an error unless allowDuplicates is set, in which case all but the
+// first, in path and method order, get a numeric suffix.
+func resolveOperationIds(spec *gopenapi.Spec, allowDuplicates bool) (map[string]string, error) {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	ids := map[string]string{}
+	var keys []string // Operations in path and method order
+	for _, path := range paths {
+		item := spec.Paths[path]
+		for _, op := range []struct {
+			method    string
+			operation *gopenapi.Operation
+		}{
+			{"GET", item.Get},
+			{"POST", item.Post},
+			{"PUT", item.Put},
+			{"DELETE", item.Delete},
+			{"PATCH", item.Patch},
+			{"HEAD", item.Head},
+			{"OPTIONS", item.Options},
+		} {
+			if op.operation == nil || op.operation.OperationId == "" {
+				continue
+			}
+			key := op.method + " " + path
+			ids[key] = op.operation.OperationId
+			keys = append(keys, key)
+		}
 	}
 
-	// Check if it contains separators (underscore, hyphen, etc.)
-	hasSeparators := strings.ContainsAny(operationId, "_-.")
+	byName := map[string][]string{}
+	var names []string
+	for _, key := range keys {
+		name := ToStructName(ids[key])
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], key)
+	}
 
-	if hasSeparators {
-		// Split by separators and convert to PascalCase
-		parts := strings.FieldsFunc(operationId, func(r rune) bool {
-			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
-		})
+	var conflicts []string
+	for _, name := range names {
+		dups := byName[name]
+		if len(dups) < 2 {
+			continue
+		}
+		sameId := true
+		for _, key := range dups {
+			sameId = sameId && ids[key] == ids[dups[0]]
+		}
+		if sameId {
+			conflicts = append(conflicts, fmt.Sprintf("operationId %q is used by %s", ids[dups[0]], strings.Join(dups, ", ")))
+			continue
+		}
+		described := make([]string, len(dups))
+		for i, key := range dups {
+			described[i] = fmt.Sprintf("%s (%s)", key, ids[key])
+		}
+		conflicts = append(conflicts, fmt.Sprintf("operationIds of %s all generate %s", strings.Join(described, ", "), name))
+	}
+	if len(conflicts) == 0 {
+		return ids, nil
+	}
+	if !allowDuplicates {
+		return nil, fmt.Errorf("duplicate operationIds: %s", strings.Join(conflicts, "; "))
+	}
 
-		var result strings.Builder
-		for _, part := range parts {
-			if len(part) > 0 {
-				result.WriteString(strings.ToUpper(part[:1]))
-				if len(part) > 1 {
-					result.WriteString(strings.ToLower(part[1:]))
+	for _, name := range names {
+		for _, key := range byName[name][1:] {
+			id := ids[key]
+			for n := 2; ; n++ {
+				suffixed := fmt.Sprintf("%s%d", id, n)
+				if _, taken := byName[ToStructName(suffixed)]; !taken {
+					ids[key] = suffixed
+					byName[ToStructName(suffixed)] = []string{key}
+					break
 				}
 			}
 		}
-		return result.String()
 	}
+	return ids, nil
+}
 
-	// For camelCase inputs (no separators), just capitalize the first letter
-	if unicode.IsLower(rune(operationId[0])) {
-		return strings.ToUpper(operationId[:1]) + operationId[1:]
+// groupByTag assigns each operation to a sub-client named after its first tag
+// and returns the sub-clients. Operations without a usable tag stay on the root client.
+func groupByTag(operations []OperationData) []TagClientData {
+	seen := map[string]bool{}
+	var clients []TagClientData
+	for i := range operations {
+		tag := operations[i].Tag
+		name := ToGoName(strings.Join(strings.Fields(tag), "_"))
+		if !isIdentifier(name) {
+			continue
+		}
+		operations[i].TagClient = name
+		if !seen[name] {
+			seen[name] = true
+			clients = append(clients, TagClientData{Name: name, Tag: tag})
+		}
 	}
 
-	// For PascalCase inputs, return as-is but ensure proper casing
-	return strings.ToUpper(operationId[:1]) + strings.ToLower(operationId[1:])
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Name < clients[j].Name
+	})
+	return clients
 }
 
-func ToMethodName(operationId string) string {
-	// Convert operationId to PascalCase method name (same as ToStructName for Go)
-	if operationId == "" {
-		return ""
+// goDurationExpr renders d as a Go expression in the largest unit that
+// represents it exactly, e.g. "90 * time.Second"
+func goDurationExpr(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return fmt.Sprintf("%d * %s", d/u.unit, u.name)
+		}
 	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
 
-	// Check if it contains separators (underscore, hyphen, etc.)
-	hasSeparators := strings.ContainsAny(operationId, "_-.")
+// authData returns the security schemes a client must apply to requests for an
+// operation. Operations without their own security requirements inherit the
+// spec-level ones. Schemes a client cannot satisfy with a static credential,
+// such as HTTP basic, are skipped.
+func authData(spec *gopenapi.Spec, operation *gopenapi.Operation) []AuthData {
+	requirements := operation.Security
+	if requirements == nil {
+		requirements = spec.Security
+	}
 
-	if hasSeparators {
-		// Split by separators and convert to PascalCase
-		parts := strings.FieldsFunc(operationId, func(r rune) bool {
-			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
-		})
+	seen := map[string]bool{}
+	var names []string
+	for _, requirement := range requirements {
+		for name := range requirement {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
 
-		var result strings.Builder
-		for _, part := range parts {
-			if len(part) > 0 {
-				result.WriteString(strings.ToUpper(part[:1]))
-				if len(part) > 1 {
-					result.WriteString(strings.ToLower(part[1:]))
-				}
+	var auth []AuthData
+	for _, name := range names {
+		scheme, ok := spec.Components.SecuritySchemes[name]
+		if !ok {
+			continue
+		}
+		switch {
+		case scheme.Type == gopenapi.APIKey && scheme.Name != "":
+			in := string(scheme.In)
+			if in == "" {
+				in = string(gopenapi.InHeader)
 			}
+			auth = append(auth, AuthData{Scheme: name, Kind: "apiKey", In: in, Name: scheme.Name})
+		case scheme.Type == gopenapi.HTTP && strings.EqualFold(string(scheme.Scheme), string(gopenapi.BearerScheme)),
+			scheme.Type == gopenapi.OAuth2, scheme.Type == gopenapi.OpenIDConnect:
+			auth = append(auth, AuthData{Scheme: name, Kind: "bearer"})
+		}
+	}
+	return auth
+}
+
+// addEnum records a named enum type for a parameter whose schema declares enum values
+func (op *OperationData) addEnum(param *ParamData, schema gopenapi.Schema) {
+	if enum, ok := enumData(op.StructName+param.GoName, schema); ok {
+		param.EnumType = enum.TypeName
+		op.Enums = append(op.Enums, enum)
+	}
+}
+
+// enumData builds a named enum type for a parameter schema that declares enum values
+func enumData(typeName string, schema gopenapi.Schema) (EnumData, bool) {
+	if len(schema.Enum) == 0 {
+		return EnumData{}, false
+	}
+
+	enum := EnumData{TypeName: typeName}
+	for i, value := range schema.Enum {
+		literal, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if str, ok := value.(string); ok {
+			name = ToGoName(str)
+		}
+		if name == "" || !isIdentifier(name) {
+			name = fmt.Sprintf("Value%d", i)
+		}
+		enum.Values = append(enum.Values, EnumValue{Name: name, Literal: string(literal)})
+	}
+	return enum, len(enum.Values) > 0
+}
+
+// isIdentifier reports whether s is a valid identifier in the generated languages
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// successStatusCode returns the lowest declared 2xx status code
+func successStatusCode(responses gopenapi.Responses) (int, bool) {
+	best, found := 0, false
+	for statusCode := range responses {
+		if statusCode >= 200 && statusCode < 300 && (!found || statusCode < best) {
+			best, found = statusCode, true
+		}
+	}
+	return best, found
+}
+
+// sortedMediaTypes returns the media types declared in content in sorted order
+func sortedMediaTypes(content gopenapi.Content) []string {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, string(mediaType))
+	}
+	sort.Strings(mediaTypes)
+	return mediaTypes
+}
+
+// sortedHeaderNames returns the names of the given headers in sorted order
+func sortedHeaderNames(headers gopenapi.Headers) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// responseFormat classifies how generated clients decode a response body of
+// mediaType: "json", "text", or "binary" for raw bytes. Clients use it when a
+// response carries no Content-Type header.
+func responseFormat(mediaType gopenapi.MediaType, schema gopenapi.Schema) string {
+	name := string(mediaType)
+	switch {
+	case schema.Format == "binary":
+		return "binary"
+	case mediaType == gopenapi.ApplicationJSON || mediaType == gopenapi.TextJSON || strings.HasSuffix(name, "+json"):
+		return "json"
+	case strings.HasPrefix(name, "text/") || strings.HasSuffix(name, "+xml") ||
+		mediaType == gopenapi.ApplicationXML || mediaType == gopenapi.ApplicationYAML || mediaType == gopenapi.ApplicationYML:
+		return "text"
+	default:
+		return "binary"
+	}
+}
+
+// GoResponseType returns ResponseType with the shared models it names
+// qualified by ModelsQualifier, e.g. []models.User
+func (op OperationData) GoResponseType() string {
+	return qualifyModels(op.ResponseType, op.ModelsQualifier)
+}
+
+// returnsRawBody reports whether the Go client returns the response body
+// without decoding it: binary bodies as []byte and text bodies as string
+func (op OperationData) returnsRawBody() bool {
+	return op.ResponseFormat == "binary" || (op.ResponseFormat == "text" && op.ResponseType == "string")
+}
+
+// preferredMediaType picks the media type used to derive generated types.
+// application/json wins when it has a schema; otherwise the first media type
+// with a schema in sorted order is used so the choice is deterministic.
+func preferredMediaType(content gopenapi.Content) (gopenapi.MediaType, bool) {
+	if json, ok := content[gopenapi.ApplicationJSON]; ok && json.Schema.Type != nil {
+		return gopenapi.ApplicationJSON, true
+	}
+	for _, mediaType := range sortedMediaTypes(content) {
+		if content[gopenapi.MediaType(mediaType)].Schema.Type != nil {
+			return gopenapi.MediaType(mediaType), true
+		}
+	}
+	return "", false
+}
+
+// ToStructName converts an operationId to an exported Go identifier in
+// PascalCase. Underscores, hyphens, dots and other non-alphanumeric characters
+// separate words, so get_user_by_id, get-user-by-id, get.user.by.id and
+// getUserById all become GetUserById. Identifiers that would start with a
+// digit are prefixed with "Op".
+func ToStructName(operationId string) string {
+	parts := strings.FieldsFunc(operationId, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var result strings.Builder
+	for _, part := range parts {
+		// Words in upper case, e.g. GET_USER, are title-cased rather than kept
+		if strings.ToUpper(part) == part {
+			part = strings.ToLower(part)
 		}
-		return result.String()
+		first, size := utf8.DecodeRuneInString(part)
+		result.WriteRune(unicode.ToUpper(first))
+		result.WriteString(part[size:])
 	}
 
-	// For camelCase inputs (no separators), just capitalize the first letter
-	if unicode.IsLower(rune(operationId[0])) {
-		return strings.ToUpper(operationId[:1]) + operationId[1:]
+	name := result.String()
+	if first, _ := utf8.DecodeRuneInString(name); unicode.IsDigit(first) {
+		name = "Op" + name
 	}
+	return name
+}
 
-	// For PascalCase inputs, return as-is but ensure proper casing
-	return strings.ToUpper(operationId[:1]) + strings.ToLower(operationId[1:])
+// ToMethodName converts an operationId to the exported Go method name of a
+// client, which is the same as its struct name
+func ToMethodName(operationId string) string {
+	return ToStructName(operationId)
 }
 
 func ToGoName(name string) string {
@@ -477,6 +1272,9 @@ func SchemaToGoType(schema gopenapi.Schema) string {
 	case gopenapi.Boolean:
 		return "bool"
 	case gopenapi.Array:
+		if schema.Items != nil && schema.Items.Type != nil {
+			return "[]" + SchemaToGoType(*schema.Items)
+		}
 		return "[]interface{}"
 	default:
 		// For other types, use the reflect.Type to determine the Go type
@@ -484,30 +1282,48 @@ func SchemaToGoType(schema gopenapi.Schema) string {
 	}
 }
 
-func schemaToFieldsWithName(schema gopenapi.Schema, structName string) []FieldData {
-	var fields []FieldData
-
+// schemaToFieldsWithName returns the fields of a struct schema along with the
+// named types generated for its nested struct fields, recursively. Nested types
+// are named after their parent and field, e.g. CreateOrderRequestBodyAddress.
+func schemaToFieldsWithName(schema gopenapi.Schema, structName string) ([]FieldData, []StructData) {
 	if schema.Type == nil || schema.Type.Kind() != reflect.Struct {
-		return fields
+		return nil, nil
 	}
 
-	t := schema.Type
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if !field.IsExported() {
-			continue
-		}
+	var nested []StructData
+	named := map[reflect.Type]string{schema.Type: structName}
+	fields := structFields(schema.Type, structName, named, &nested)
+	return fields, nested
+}
+
+// structFields converts the serialized fields of t, declaring a named type in
+// nested for each struct-typed field. named maps struct types to the names
+// already generated for them so that shared and recursive types are reused.
+// The fields of embedded structs are promoted, so that the generated struct
+// decodes the flat JSON of t.
+func structFields(t reflect.Type, structName string, named map[reflect.Type]string, nested *[]StructData) []FieldData {
+	var fields []FieldData
+	goNames := make(map[string]bool)
 
-		jsonTag := field.Tag.Get("json")
-		fieldName := field.Name
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "" && parts[0] != "-" {
-				fieldName = parts[0]
+	for _, field := range gopenapi.JSONFields(t) {
+		fieldName := field.JSONName
+
+		// Promoted fields may share the Go name of a field with another JSON
+		// name, e.g. an ID tagged "parentId" next to an embedded ID tagged "id"
+		goName := field.Name
+		for n := 2; goNames[goName]; n++ {
+			goName = fmt.Sprintf("%s%d", field.Name, n)
+		}
+		goNames[goName] = true
+
+		goType := fieldGoType(field.Type, structName+goName, named, nested)
+		// Values of fields with the string option are sent as JSON strings, e.g. "42" for an int
+		if gopenapi.HasJSONOption(field.Tag, "string") && gopenapi.StringEncodable(field.Type) {
+			goType = "string"
+			if field.Type.Kind() == reflect.Ptr {
+				goType = "*string"
 			}
 		}
-
-		goType := typeToGoType(field.Type)
 		if goType == "interface{}" {
 			// Use the provided struct name or fall back to reflect type name
 			typeName := structName
@@ -521,15 +1337,245 @@ func schemaToFieldsWithName(schema gopenapi.Schema, structName string) []FieldDa
 		}
 
 		fields = append(fields, FieldData{
-			Name:   fieldName,
-			GoName: field.Name,
-			GoType: goType,
+			Name:      fieldName,
+			GoName:    goName,
+			GoType:    goType,
+			OmitEmpty: gopenapi.HasJSONOption(field.Tag, "omitempty"),
+			Validate:  fieldValidateTag(field.StructField),
 		})
 	}
 
 	return fields
 }
 
+// validatorFormats maps string formats to go-playground/validator tags
+var validatorFormats = map[string]string{
+	"email": "email",
+	"ipv4":  "ipv4",
+	"ipv6":  "ipv6",
+	"uri":   "uri",
+	"url":   "url",
+	"uuid":  "uuid",
+}
+
+// validateTag returns the go-playground/validator tag enforcing the
+// constraints of schema, starting with required or omitempty, or "" when an
+// optional value is unconstrained
+func validateTag(schema gopenapi.Schema, required bool) string {
+	var rules []string
+	if schema.Minimum != nil {
+		op := "min"
+		if schema.ExclusiveMinimum {
+			op = "gt"
+		}
+		rules = append(rules, op+"="+strconv.FormatFloat(*schema.Minimum, 'f', -1, 64))
+	}
+	if schema.Maximum != nil {
+		op := "max"
+		if schema.ExclusiveMaximum {
+			op = "lt"
+		}
+		rules = append(rules, op+"="+strconv.FormatFloat(*schema.Maximum, 'f', -1, 64))
+	}
+	if schema.MinLength != nil {
+		rules = append(rules, "min="+strconv.Itoa(*schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		rules = append(rules, "max="+strconv.Itoa(*schema.MaxLength))
+	}
+	if format, ok := validatorFormats[schema.Format]; ok {
+		rules = append(rules, format)
+	}
+	if oneOf := validateOneOf(schema.Enum); oneOf != "" {
+		rules = append(rules, oneOf)
+	}
+
+	switch {
+	case required:
+		return strings.Join(append([]string{"required"}, rules...), ",")
+	case len(rules) > 0:
+		return strings.Join(append([]string{"omitempty"}, rules...), ",")
+	default:
+		return ""
+	}
+}
+
+// validateOneOf returns a oneof rule for enum, or "" when it is empty or has
+// values the space-separated rule cannot express
+func validateOneOf(enum []any) string {
+	if len(enum) == 0 {
+		return ""
+	}
+	values := make([]string, len(enum))
+	for i, value := range enum {
+		values[i] = fmt.Sprint(value)
+		if values[i] == "" || strings.ContainsAny(values[i], " ,|'\"`") {
+			return ""
+		}
+	}
+	return "oneof=" + strings.Join(values, " ")
+}
+
+// fieldValidateTag returns the validate tag of a body field from its format and
+// enum tags. Fields without omitempty are required, except booleans and
+// numbers, whose zero values the validator would reject.
+func fieldValidateTag(field reflect.StructField) string {
+	schema := gopenapi.Schema{Format: field.Tag.Get("format")}
+	if enum := field.Tag.Get("enum"); enum != "" {
+		for _, value := range strings.Split(enum, ",") {
+			schema.Enum = append(schema.Enum, value)
+		}
+	}
+	jsonTag := field.Tag.Get("json")
+	required := jsonTag != "" && !strings.Contains(jsonTag, "omitempty")
+	switch field.Type.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		required = false
+	}
+	return validateTag(schema, required)
+}
+
+// clientTypeNames are the types declared by every generated Go client, which
+// shared models must not be named after
+var clientTypeNames = map[string]bool{
+	"CircuitBreakerOptions": true,
+	"CircuitOpenError":      true,
+	"Client":                true,
+	"Error":                 true,
+	"Option":                true,
+	"PageIterator":          true,
+	"RateLimitState":        true,
+	"RedirectError":         true,
+}
+
+// modelRegistry generates the struct types of the elements of top-level slice,
+// array and map bodies, which have no parent struct to be named after. Structs
+// are named after their Go type, e.g. User for []User, so operations sharing
+// an element type share one model.
+type modelRegistry struct {
+	typeName func(reflect.Type) string
+	named    map[reflect.Type]string
+	structs  []StructData
+}
+
+func newModelRegistry(typeName func(reflect.Type) string) *modelRegistry {
+	if typeName == nil {
+		typeName = reflect.Type.Name
+	}
+	return &modelRegistry{typeName: typeName, named: map[reflect.Type]string{}}
+}
+
+// goType returns the Go type of a top-level slice, array or map body schema,
+// declaring the structs of its elements like those of nested fields. Anonymous
+// element structs and those whose name is taken are named fallback. It returns
+// "" for other schemas.
+func (m *modelRegistry) goType(schema gopenapi.Schema, fallback string) string {
+	t := schema.Type
+	if t == gopenapi.Array {
+		// Arrays of component or struct schemas declared through Items
+		if schema.Items == nil || schema.Items.Type == nil || schema.Items.Type == gopenapi.Array {
+			return ""
+		}
+		t = reflect.SliceOf(schema.Items.Type)
+	}
+	if t == nil {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return ""
+	}
+
+	elem := t
+	for elem.Kind() == reflect.Ptr || elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array || elem.Kind() == reflect.Map {
+		elem = elem.Elem()
+	}
+	typeName := fallback
+	if name := m.typeName(elem); elem.Kind() == reflect.Struct && name != "" {
+		// Models are exported so that a separate models package can declare them
+		name = strings.ToUpper(name[:1]) + name[1:]
+		if !m.taken(name, elem) {
+			typeName = name
+		}
+	}
+	return fieldGoType(t, typeName, m.named, &m.structs)
+}
+
+// taken reports whether name is used by a client type or a model of another type
+func (m *modelRegistry) taken(name string, t reflect.Type) bool {
+	if clientTypeNames[name] {
+		return true
+	}
+	for other, otherName := range m.named {
+		if otherName == name && other != t {
+			return true
+		}
+	}
+	return false
+}
+
+// sorted returns the generated models sorted by name
+func (m *modelRegistry) sorted() []StructData {
+	structs := append([]StructData{}, m.structs...)
+	sort.Slice(structs, func(i, j int) bool { return structs[i].Name < structs[j].Name })
+	return structs
+}
+
+// qualifyModels qualifies the model type named by goType, e.g. []User, with
+// qualifier, leaving predeclared and standard library types as they are
+func qualifyModels(goType, qualifier string) string {
+	if qualifier == "" {
+		return goType
+	}
+	elem := strings.TrimLeft(goType, "*[]0123456789")
+	if rest, ok := strings.CutPrefix(elem, "map[string]"); ok {
+		return goType[:len(goType)-len(rest)] + qualifyModels(rest, qualifier)
+	}
+	if !isNestedStructType(elem) {
+		return goType
+	}
+	return goType[:len(goType)-len(elem)] + qualifier + elem
+}
+
+// fieldGoType returns the Go type of a body field, generating a struct named
+// typeName for struct types found directly or inside pointers, slices, arrays
+// and string-keyed maps
+func fieldGoType(t reflect.Type, typeName string, named map[reflect.Type]string, nested *[]StructData) string {
+	if t == gopenapi.File {
+		return "io.Reader"
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + fieldGoType(t.Elem(), typeName, named, nested)
+	case reflect.Slice:
+		return "[]" + fieldGoType(t.Elem(), typeName, named, nested)
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), fieldGoType(t.Elem(), typeName, named, nested))
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return typeToGoType(t)
+		}
+		return "map[string]" + fieldGoType(t.Elem(), typeName, named, nested)
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return "time.Time"
+		}
+		if name, ok := named[t]; ok {
+			return name
+		}
+		named[t] = typeName
+		fields := structFields(t, typeName, named, nested)
+		*nested = append(*nested, StructData{Name: typeName, Fields: fields})
+		return typeName
+	default:
+		return typeToGoType(t)
+	}
+}
+
 func typeToGoType(t reflect.Type) string {
 	// Handle named types (aliases) by resolving to their underlying type
 	if t.PkgPath() != "" && t.Name() != "" {
@@ -644,18 +1690,170 @@ func generateConvertToString(goName, goType string) string {
 	}
 }
 
-func generateAddToParams(goName, goType, paramName string) string {
+// generateAddToParams returns the code adding a query parameter, read from the
+// struct query, e.g. opts.Query, to params. Slice values are sent as repeated
+// keys when explode is set, and as a single comma-separated value otherwise.
+func generateAddToParams(query, goName, goType, paramName string, explode bool) string {
+	if elemType, ok := strings.CutPrefix(goType, "[]"); ok {
+		value := valueToString("v", elemType)
+		if explode {
+			return fmt.Sprintf("for _, v := range %s.%s {\n\t\tparams.Add(\"%s\", %s)\n\t}", query, goName, paramName, value)
+		}
+		return fmt.Sprintf("if len(%s.%s) > 0 {\n\t\tvalues := make([]string, len(%s.%s))\n\t\tfor i, v := range %s.%s {\n\t\t\tvalues[i] = %s\n\t\t}\n\t\tparams.Add(\"%s\", strings.Join(values, \",\"))\n\t}", query, goName, query, goName, query, goName, value, paramName)
+	}
+
+	switch goType {
+	case "string":
+		return fmt.Sprintf("if %s.%s != \"\" {\n\t\tparams.Add(\"%s\", %s.%s)\n\t}", query, goName, paramName, query, goName)
+	case "int":
+		return fmt.Sprintf("if %s.%s != 0 {\n\t\tparams.Add(\"%s\", strconv.Itoa(%s.%s))\n\t}", query, goName, paramName, query, goName)
+	case "float64":
+		return fmt.Sprintf("if %s.%s != 0 {\n\t\tparams.Add(\"%s\", strconv.FormatFloat(%s.%s, 'f', -1, 64))\n\t}", query, goName, paramName, query, goName)
+	case "bool":
+		return fmt.Sprintf("params.Add(\"%s\", strconv.FormatBool(%s.%s))", paramName, query, goName)
+	default:
+		return fmt.Sprintf("if %s.%s != nil {\n\t\tparams.Add(\"%s\", fmt.Sprintf(\"%%v\", %s.%s))\n\t}", query, goName, paramName, query, goName)
+	}
+}
+
+// goLiteral returns value as a Go literal of goType, used for schema defaults.
+// It reports false for nil values and values that do not fit goType.
+func goLiteral(value any, goType string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+
+	v := reflect.ValueOf(value)
+	if elemType, ok := strings.CutPrefix(goType, "[]"); ok {
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return "", false
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			elem, ok := goLiteral(v.Index(i).Interface(), elemType)
+			if !ok {
+				return "", false
+			}
+			elems[i] = elem
+		}
+		return goType + "{" + strings.Join(elems, ", ") + "}", true
+	}
+
 	switch goType {
 	case "string":
-		return fmt.Sprintf("if opts.Query.%s != \"\" {\n\t\tparams.Add(\"%s\", opts.Query.%s)\n\t}", goName, paramName, goName)
+		if v.Kind() == reflect.String {
+			return strconv.Quote(v.String()), true
+		}
+	case "bool":
+		if v.Kind() == reflect.Bool {
+			return strconv.FormatBool(v.Bool()), true
+		}
+	case "int":
+		switch {
+		case v.CanInt():
+			return strconv.FormatInt(v.Int(), 10), true
+		case v.CanUint():
+			return strconv.FormatUint(v.Uint(), 10), true
+		case v.CanFloat() && v.Float() == math.Trunc(v.Float()):
+			return strconv.FormatInt(int64(v.Float()), 10), true
+		}
+	case "float64":
+		switch {
+		case v.CanInt():
+			return strconv.FormatInt(v.Int(), 10), true
+		case v.CanUint():
+			return strconv.FormatUint(v.Uint(), 10), true
+		case v.CanFloat():
+			return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+		}
+	}
+	return "", false
+}
+
+// valueToString returns the expression converting a query or form value of goType to a string
+func valueToString(expr, goType string) string {
+	switch goType {
+	case "string":
+		return expr
 	case "int":
-		return fmt.Sprintf("if opts.Query.%s != 0 {\n\t\tparams.Add(\"%s\", strconv.Itoa(opts.Query.%s))\n\t}", goName, paramName, goName)
+		return fmt.Sprintf("strconv.Itoa(%s)", expr)
 	case "float64":
-		return fmt.Sprintf("if opts.Query.%s != 0 {\n\t\tparams.Add(\"%s\", strconv.FormatFloat(opts.Query.%s, 'f', -1, 64))\n\t}", goName, paramName, goName)
+		return fmt.Sprintf("strconv.FormatFloat(%s, 'f', -1, 64)", expr)
 	case "bool":
-		return fmt.Sprintf("params.Add(\"%s\", strconv.FormatBool(opts.Query.%s))", paramName, goName)
+		return fmt.Sprintf("strconv.FormatBool(%s)", expr)
 	default:
-		return fmt.Sprintf("if opts.Query.%s != nil {\n\t\tparams.Add(\"%s\", fmt.Sprintf(\"%%v\", opts.Query.%s))\n\t}", goName, paramName, goName)
+		return fmt.Sprintf("fmt.Sprintf(\"%%v\", %s)", expr)
+	}
+}
+
+// generateWriteMultipart returns the code writing a request body field to a
+// multipart.Writer. io.Reader fields are streamed as file parts, slices are
+// written as repeated fields and other non-scalar values as JSON.
+func generateWriteMultipart(goName, goType, fieldName string) string {
+	switch goType {
+	case "io.Reader":
+		return fmt.Sprintf("if b.%s != nil {\n\t\tpart, err := writer.CreateFormFile(\"%s\", \"%s\")\n\t\tif err != nil {\n\t\t\treturn err\n\t\t}\n\t\tif _, err := io.Copy(part, b.%s); err != nil {\n\t\t\treturn err\n\t\t}\n\t}", goName, fieldName, fieldName, goName)
+	case "string", "int", "float64", "bool":
+		return fmt.Sprintf("if err := writer.WriteField(\"%s\", %s); err != nil {\n\t\treturn err\n\t}", fieldName, valueToString("b."+goName, goType))
+	case "[]string", "[]int", "[]float64", "[]bool":
+		return fmt.Sprintf("for _, v := range b.%s {\n\t\tif err := writer.WriteField(\"%s\", %s); err != nil {\n\t\t\treturn err\n\t\t}\n\t}", goName, fieldName, valueToString("v", goType[2:]))
+	default:
+		return fmt.Sprintf("if value, err := json.Marshal(b.%s); err != nil {\n\t\treturn err\n\t} else if err := writer.WriteField(\"%s\", string(value)); err != nil {\n\t\treturn err\n\t}", goName, fieldName)
+	}
+}
+
+// headerGetters returns the typed accessors generated for response headers on
+// a response struct with fields. Accessors are named after the header without
+// its "X-" prefix, e.g. RateLimitRemaining for X-Rate-Limit-Remaining, and take
+// a "Header" suffix when that name is already used by a field. Headers of types
+// other than string, int, float64 and bool are only available through the
+// Header field.
+func headerGetters(headers []ParamData, fields []FieldData) []ParamData {
+	taken := map[string]bool{"Header": true}
+	for _, field := range fields {
+		taken[field.GoName] = true
+	}
+
+	var getters []ParamData
+	for _, header := range headers {
+		parse := generateParseHeader(header.GoType, header.Name)
+		if parse == "" {
+			continue
+		}
+		name := header.Name
+		if len(name) > 2 && strings.EqualFold(name[:2], "X-") {
+			name = name[2:]
+		}
+		goName := ToGoName(name)
+		if taken[goName] {
+			goName += "Header"
+		}
+		taken[goName] = true
+		getters = append(getters, ParamData{
+			Name:        header.Name,
+			GoName:      goName,
+			GoType:      header.GoType,
+			ParseHeader: parse,
+		})
+	}
+	return getters
+}
+
+// generateParseHeader returns the body of the accessor parsing headerName from
+// the response headers, or "" when goType has no accessor. Absent or malformed
+// values read as the zero value.
+func generateParseHeader(goType, headerName string) string {
+	switch goType {
+	case "string":
+		return fmt.Sprintf("return r.Header.Get(\"%s\")", headerName)
+	case "int":
+		return fmt.Sprintf("value, _ := strconv.Atoi(r.Header.Get(\"%s\"))\n\treturn value", headerName)
+	case "float64":
+		return fmt.Sprintf("value, _ := strconv.ParseFloat(r.Header.Get(\"%s\"), 64)\n\treturn value", headerName)
+	case "bool":
+		return fmt.Sprintf("value, _ := strconv.ParseBool(r.Header.Get(\"%s\"))\n\treturn value", headerName)
+	default:
+		return ""
 	}
 }
 
@@ -2,11 +2,21 @@ package generator
 
 import (
 	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/runpod/gopenapi"
 )
@@ -266,201 +276,3302 @@ func TestToStructName(t *testing.T) {
 		{
 			name:        "PascalCase input",
 			operationId: "GetUserById",
-			expected:    "Getuserbyid", // Actual behavior - it lowercases everything after first char
+			expected:    "GetUserById",
+		},
+		{
+			name:        "snake_case input",
+			operationId: "get_user_by_id",
+			expected:    "GetUserById",
+		},
+		{
+			name:        "kebab-case input",
+			operationId: "get-user-by-id",
+			expected:    "GetUserById",
+		},
+		{
+			name:        "dotted input",
+			operationId: "users.get.by.id",
+			expected:    "UsersGetById",
+		},
+		{
+			name:        "mixed separators and camelCase",
+			operationId: "v2.users-get_userById",
+			expected:    "V2UsersGetUserById",
+		},
+		{
+			name:        "upper snake_case input",
+			operationId: "GET_USER",
+			expected:    "GetUser",
+		},
+		{
+			name:        "acronym in camelCase",
+			operationId: "getUserByID",
+			expected:    "GetUserByID",
+		},
+		{
+			name:        "leading digit",
+			operationId: "2fa-verify",
+			expected:    "Op2faVerify",
+		},
+		{
+			name:        "empty input",
+			operationId: "",
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToStructName(tt.operationId)
+			if result != tt.expected {
+				t.Errorf("ToStructName() = %v, want %v", result, tt.expected)
+			}
+			if tt.operationId != "" && !(token.IsIdentifier(result) && token.IsExported(result)) {
+				t.Errorf("ToStructName(%q) = %q is not an exported Go identifier", tt.operationId, result)
+			}
+		})
+	}
+}
+
+func TestToMethodName(t *testing.T) {
+	tests := []struct {
+		name        string
+		operationId string
+		expected    string
+	}{
+		{
+			name:        "camelCase input",
+			operationId: "getUserById",
+			expected:    "GetUserById",
+		},
+		{
+			name:        "PascalCase input",
+			operationId: "GetUserById",
+			expected:    "GetUserById",
 		},
 		{
 			name:        "snake_case input",
 			operationId: "get_user_by_id",
-			expected:    "GetUserById", // Should convert properly
+			expected:    "GetUserById",
 		},
 		{
 			name:        "kebab-case input",
 			operationId: "get-user-by-id",
-			expected:    "GetUserById", // Should convert properly
+			expected:    "GetUserById",
+		},
+		{
+			name:        "dotted input",
+			operationId: "users.list",
+			expected:    "UsersList",
+		},
+		{
+			name:        "empty input",
+			operationId: "",
+			expected:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToMethodName(tt.operationId)
+			if result != tt.expected {
+				t.Errorf("ToMethodName() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToGoName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "snake_case",
+			input:    "user_id",
+			expected: "UserId",
+		},
+		{
+			name:     "kebab-case",
+			input:    "user-id",
+			expected: "UserId",
+		},
+		{
+			name:     "dot.case",
+			input:    "user.id",
+			expected: "UserId",
+		},
+		{
+			name:     "single word",
+			input:    "user",
+			expected: "User",
+		},
+		{
+			name:     "already PascalCase",
+			input:    "UserId",
+			expected: "UserId",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ToGoName(tt.input)
+			if result != tt.expected {
+				t.Errorf("ToGoName() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateConvertToString(t *testing.T) {
+	tests := []struct {
+		name     string
+		goName   string
+		goType   string
+		expected string
+	}{
+		{
+			name:     "string type",
+			goName:   "Id",
+			goType:   "string",
+			expected: "opts.Path.Id",
+		},
+		{
+			name:     "int type",
+			goName:   "Id",
+			goType:   "int",
+			expected: "strconv.Itoa(opts.Path.Id)",
+		},
+		{
+			name:     "float64 type",
+			goName:   "Price",
+			goType:   "float64",
+			expected: "strconv.FormatFloat(opts.Path.Price, 'f', -1, 64)",
+		},
+		{
+			name:     "bool type",
+			goName:   "Active",
+			goType:   "bool",
+			expected: "strconv.FormatBool(opts.Path.Active)",
+		},
+		{
+			name:     "other type",
+			goName:   "Data",
+			goType:   "interface{}",
+			expected: "fmt.Sprintf(\"%v\", opts.Path.Data)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := generateConvertToString(tt.goName, tt.goType)
+			if result != tt.expected {
+				t.Errorf("generateConvertToString() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateTemplateData(t *testing.T) {
+	templateData, err := generateTemplateData(&testSpec, "testclient")
+	if err != nil {
+		t.Fatalf("generateTemplateData() error = %v", err)
+	}
+
+	if templateData.PackageName != "testclient" {
+		t.Errorf("Expected PackageName to be 'testclient', got %v", templateData.PackageName)
+	}
+
+	if len(templateData.Operations) == 0 {
+		t.Error("Expected at least one operation in template data")
+	}
+
+	// Check the first operation
+	op := templateData.Operations[0]
+	if op.OperationId != "getUserById" {
+		t.Errorf("Expected OperationId to be 'getUserById', got %v", op.OperationId)
+	}
+
+	if op.Method != "GET" {
+		t.Errorf("Expected Method to be 'GET', got %v", op.Method)
+	}
+
+	if op.Path != "/users/{id}" {
+		t.Errorf("Expected Path to be '/users/{id}', got %v", op.Path)
+	}
+
+	if !op.HasPathParams {
+		t.Error("Expected HasPathParams to be true")
+	}
+
+	if len(op.PathParams) != 1 {
+		t.Errorf("Expected 1 path parameter, got %d", len(op.PathParams))
+	}
+
+	if op.PathParams[0].Name != "id" {
+		t.Errorf("Expected path parameter name to be 'id', got %v", op.PathParams[0].Name)
+	}
+}
+
+func TestGenerateTemplateDataPrefersJSONResponse(t *testing.T) {
+	type Report struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/reports/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getReport",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Report",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationXML: {
+									Schema: gopenapi.Schema{Type: gopenapi.String},
+								},
+								gopenapi.ApplicationJSON: {
+									Schema: gopenapi.Schema{Type: gopenapi.Object[Report]()},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Map iteration order is random, so repeat to make sure the choice is stable
+	for range 20 {
+		templateData, err := generateTemplateData(&spec, "testclient")
+		if err != nil {
+			t.Fatalf("generateTemplateData() error = %v", err)
+		}
+		op := templateData.Operations[0]
+
+		if !op.HasResponseBody {
+			t.Fatal("Expected HasResponseBody to be true")
+		}
+		if op.ResponseType != "" {
+			t.Fatalf("Expected the JSON struct schema to be chosen, got simple ResponseType %q", op.ResponseType)
+		}
+		if len(op.ResponseFields) != 2 {
+			t.Fatalf("Expected 2 response fields from the JSON schema, got %d", len(op.ResponseFields))
+		}
+
+		expectedMediaTypes := []string{"application/json", "application/xml"}
+		if !reflect.DeepEqual(op.ResponseMediaTypes, expectedMediaTypes) {
+			t.Fatalf("Expected ResponseMediaTypes %v, got %v", expectedMediaTypes, op.ResponseMediaTypes)
+		}
+	}
+}
+
+func TestGenerateGoClientDocumentsResponseHeaders(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Users",
+							Headers: gopenapi.Headers{
+								"X-Rate-Limit-Remaining": {Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+							},
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "//   - X-Rate-Limit-Remaining (int)") {
+		t.Errorf("Expected the response header to be documented on the method, got:\n%s", buf.String())
+	}
+}
+
+func TestGenerateGoClientResponseHeaderAccessors(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Paginated:   true,
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Users",
+							Headers: gopenapi.Headers{
+								"X-Rate-Limit-Remaining": {Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+								"X-Request-Id":           {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf(struct {
+									Users []string `json:"users"`
+								}{})}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	src := `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Rate-Limit-Remaining", "42")
+		w.Header().Set("X-Request-Id", "req-1")
+		fmt.Fprint(w, ` + "`" + `{"users":["ada"]}` + "`" + `)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	var remaining int = result.RateLimitRemaining()
+	if remaining != 42 {
+		t.Errorf("RateLimitRemaining() = %d, want 42", remaining)
+	}
+	if id := result.RequestId(); id != "req-1" {
+		t.Errorf("RequestId() = %q, want %q", id, "req-1")
+	}
+	if len(result.Users) != 1 || result.Users[0] != "ada" {
+		t.Errorf("Users = %v, want [ada]", result.Users)
+	}
+
+	pages := client.ListUsersPages(context.Background())
+	if !pages.Next() {
+		t.Fatalf("Next() = false, err = %v", pages.Err())
+	}
+	if remaining := pages.Page().RateLimitRemaining(); remaining != 42 {
+		t.Errorf("page RateLimitRemaining() = %d, want 42", remaining)
+	}
+}
+`
+
+	t.Run("single package", func(t *testing.T) {
+		runGeneratedGoClientTest(t, &spec, src)
+	})
+	t.Run("models package", func(t *testing.T) {
+		runGeneratedGoClientTestWithOptions(t, &spec, Options{PackageName: "testclient", ModelsPackage: "testclient/models"}, src)
+	})
+}
+
+func TestHeaderGetters(t *testing.T) {
+	headers := []ParamData{
+		{Name: "Retry-After", GoType: "int"},
+		{Name: "X-Total", GoType: "int"},
+		{Name: "X-Trace", GoType: "[]string"},
+	}
+	fields := []FieldData{{Name: "total", GoName: "Total", GoType: "int"}}
+
+	var names []string
+	for _, getter := range headerGetters(headers, fields) {
+		names = append(names, getter.GoName)
+	}
+	expected := []string{"RetryAfter", "TotalHeader"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("headerGetters() names = %v, want %v", names, expected)
+	}
+}
+
+func TestGenerateTypeScriptEnumStyle(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{
+							Name: "status",
+							In:   gopenapi.InQuery,
+							Schema: gopenapi.Schema{
+								Type: gopenapi.String,
+								Enum: []any{"active", "in-review"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		style    EnumStyle
+		expected []string
+	}{
+		{
+			name:  "union (default)",
+			style: "",
+			expected: []string{
+				`export type ListUsersStatus = "active" | "in-review";`,
+				`status?: ListUsersStatus;`,
+			},
+		},
+		{
+			name:  "enum",
+			style: EnumStyleEnum,
+			expected: []string{
+				"export enum ListUsersStatus {",
+				`Active = "active",`,
+				`InReview = "in-review",`,
+				`status?: ListUsersStatus;`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := Options{PackageName: "testclient", TypeScriptEnumStyle: tt.style}
+			if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/typescript.tpl", "typescript", opts); err != nil {
+				t.Fatalf("GenerateClientToWriterWithOptions() error = %v", err)
+			}
+
+			output := buf.String()
+			for _, want := range tt.expected {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected TypeScript output to contain %q, got:\n%s", want, output)
+				}
+			}
+			if tt.style == EnumStyleEnum && strings.Contains(output, "export type ListUsersStatus") {
+				t.Error("Enum style should not emit a union type")
+			}
+		})
+	}
+}
+
+func TestGeneratePythonAsyncClient(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{PackageName: "testclient", PythonAsync: true}
+	if err := GenerateClientToWriterWithOptions(&testSpec, &buf, "templates/python.tpl", "python", opts); err != nil {
+		t.Fatalf("GenerateClientToWriterWithOptions() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"import aiohttp",
+		"class AsyncClient:",
+		"async def get_user_by_id(self, path: GetUserByIdPathParams)",
+		"content = await self._make_request(",
+		"async with self.session.request(",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected async Python output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "import requests") {
+		t.Error("Async Python client should not depend on requests")
+	}
+}
+
+func TestToPythonType(t *testing.T) {
+	tests := []struct {
+		goType   string
+		expected string
+	}{
+		{"string", "str"},
+		{"int", "int"},
+		{"float64", "float"},
+		{"bool", "bool"},
+		{"time.Time", "str"},
+		{"[]interface{}", "List[Any]"},
+		{"[]string", "List[str]"},
+		{"[3]int", "List[int]"},
+		{"map[string]float64", "Dict[str, float]"},
+		{"*int", "Optional[int]"},
+		{"CreateOrderRequestBodyAddress", "CreateOrderRequestBodyAddress"},
+		{"[]*CreateOrderRequestBodyItem", "List[Optional[CreateOrderRequestBodyItem]]"},
+		{"interface{}", "Any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := toPythonType(tt.goType); got != tt.expected {
+				t.Errorf("toPythonType(%q) = %q, want %q", tt.goType, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeneratePythonNestedModels(t *testing.T) {
+	type Item struct {
+		SKU      string `json:"sku"`
+		Quantity int    `json:"quantity"`
+	}
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Order struct {
+		Tags    []string `json:"tags"`
+		Note    *string  `json:"note"`
+		Address Address  `json:"address"`
+		Items   []Item   `json:"items"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/orders": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "createOrder",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Order]()}},
+						},
+					},
+					Responses: gopenapi.Responses{
+						201: {
+							Description: "Created",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Order]()}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/python.tpl", "python"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"class CreateOrderRequestBodyAddress:",
+		"class CreateOrderResponseItems:",
+		"    tags: List[str]",
+		"    note: Optional[str]",
+		"    address: CreateOrderRequestBodyAddress",
+		"    items: List[CreateOrderRequestBodyItems]",
+		"    note: Optional[str] = None",
+		`address=CreateOrderResponseAddress.from_dict(data.get("address")) if data.get("address") is not None else None`,
+		`items=[CreateOrderResponseItems.from_dict(item) for item in data.get("items")] if data.get("items") is not None else None`,
+		`"address": _to_json(self.address),`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected Python output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestToTypeScriptType(t *testing.T) {
+	tests := []struct {
+		goType   string
+		expected string
+	}{
+		{"string", "string"},
+		{"int", "number"},
+		{"float64", "number"},
+		{"bool", "boolean"},
+		{"time.Time", "string"},
+		{"[]interface{}", "any[]"},
+		{"[]string", "string[]"},
+		{"map[string]int", "Record<string, number>"},
+		{"*string", "string | null"},
+		{"[]*int", "(number | null)[]"},
+		{"ListOrdersResponseItems", "ListOrdersResponseItems"},
+		{"[]ListOrdersResponseItems", "ListOrdersResponseItems[]"},
+		{"interface{}", "any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := toTypeScriptType(tt.goType); got != tt.expected {
+				t.Errorf("toTypeScriptType(%q) = %q, want %q", tt.goType, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateTypeScriptNestedInterfaces(t *testing.T) {
+	type LineItem struct {
+		SKU   string  `json:"sku"`
+		Price float64 `json:"price"`
+	}
+	type Order struct {
+		ID     string            `json:"id"`
+		Items  []LineItem        `json:"items"`
+		Note   *string           `json:"note"`
+		Labels map[string]string `json:"labels"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/orders/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getOrder",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "OK",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Order]()}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/typescript.tpl", "typescript"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{
+		"export interface GetOrderResponseItems {\n  sku: string;\n  price: number;\n}",
+		"export interface GetOrderResponse {",
+		"  items: GetOrderResponseItems[];",
+		"  note: string | null;",
+		"  labels: Record<string, string>;",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected TypeScript output to contain %q, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateGoClientIsFormatted(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&testSpec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Generated Go client does not parse: %v", err)
+	}
+	if !bytes.Equal(formatted, buf.Bytes()) {
+		t.Errorf("Expected generated Go client to be gofmt'd, got:\n%s", buf.String())
+	}
+}
+
+// runGeneratedGoClientTest generates a Go client for spec into a scratch module
+// alongside testSource and runs `go test` on it, so tests can exercise the
+// generated code at runtime
+func runGeneratedGoClientTest(t *testing.T, spec *gopenapi.Spec, testSource string) {
+	t.Helper()
+	runGeneratedGoClientTestWithOptions(t, spec, Options{PackageName: "testclient"}, testSource)
+}
+
+// runGeneratedGoClientTestWithOptions is runGeneratedGoClientTest for clients
+// generated with non-default options
+func runGeneratedGoClientTestWithOptions(t *testing.T, spec *gopenapi.Spec, opts Options, testSource string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping generated client test in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	dir := t.TempDir()
+	if err := GenerateClientForLanguageWithOptions(spec, "go", dir, opts); err != nil {
+		t.Fatalf("GenerateClientForLanguageWithOptions() error = %v", err)
+	}
+	files := map[string]string{
+		"go.mod":         "module testclient\n\ngo 1.21\n",
+		"client_test.go": testSource,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "test", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod", "GOTOOLCHAIN=local")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		client, _ := os.ReadFile(filepath.Join(dir, "client.go"))
+		t.Fatalf("Generated client test failed: %v\n%s\nGenerated client:\n%s", err, output, client)
+	}
+}
+
+func TestGenerateGoClientBaseContext(t *testing.T) {
+	runGeneratedGoClientTest(t, &testSpec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type tenantKey struct{}
+type requestKey struct{}
+
+func TestBaseContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+	}))
+	defer server.Close()
+
+	var tenant, request any
+	base := context.WithValue(context.Background(), tenantKey{}, "acme")
+	base = context.WithValue(base, requestKey{}, "base")
+	client, err := NewClient(server.URL, WithBaseContext(base), WithRequestInterceptor(func(r *http.Request) error {
+		tenant, request = r.Context().Value(tenantKey{}), r.Context().Value(requestKey{})
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Per-call values take precedence over the base context's
+	ctx := context.WithValue(context.Background(), requestKey{}, "call")
+	if _, err := client.GetUserById(ctx, &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("GetUserById() error = %v", err)
+	}
+	if tenant != "acme" || request != "call" {
+		t.Errorf("Expected interceptor to see tenant acme and request call, got %v and %v", tenant, request)
+	}
+
+	// The per-call context alone controls cancellation
+	canceled, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-canceled.Done()
+	if _, err := client.GetUserById(canceled, &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err == nil {
+		t.Error("Expected an error for a canceled per-call context")
+	}
+}
+`)
+}
+
+func TestGenerateGoClientMetrics(t *testing.T) {
+	runGeneratedGoClientTest(t, &testSpec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+	}))
+	defer server.Close()
+
+	var calls int
+	var op string
+	var status int
+	var dur time.Duration
+	client, err := NewClient(server.URL, WithMetrics(func(o string, s int, d time.Duration) {
+		calls++
+		op, status, dur = o, s, d
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("GetUserById() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected the metrics function to be called once, got %d", calls)
+	}
+	if op != "getUserById" || status != http.StatusAccepted {
+		t.Errorf("Expected getUserById with status 202, got %q with %d", op, status)
+	}
+	if dur <= 0 {
+		t.Errorf("Expected a non-zero duration, got %v", dur)
+	}
+
+	server.Close()
+	client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}})
+	if calls != 2 || status != 0 {
+		t.Errorf("Expected a call with status 0 for a failed request, got %d calls and status %d", calls, status)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientWithoutRedirects(t *testing.T) {
+	runGeneratedGoClientTest(t, &testSpec, `package testclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithoutRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+			return
+		}
+		w.Header().Set("Location", "/moved")
+		w.WriteHeader(http.StatusFound)
+		fmt.Fprint(w, "<a href=\"/moved\">Found</a>")
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithoutRedirects())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}})
+	var redirect *RedirectError
+	if !errors.As(err, &redirect) {
+		t.Fatalf("Expected a *RedirectError, got %v", err)
+	}
+	if redirect.StatusCode != http.StatusFound || redirect.Location != server.URL+"/moved" {
+		t.Errorf("Expected a 302 to %s/moved, got %d to %s", server.URL, redirect.StatusCode, redirect.Location)
+	}
+	if result != "" {
+		t.Errorf("Expected the redirect body not to be decoded, got %q", result)
+	}
+
+	// Redirects are followed by default
+	client, err = NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("Expected the redirect to be followed, got %v", err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientCircuitBreaker(t *testing.T) {
+	runGeneratedGoClientTest(t, &testSpec, `package testclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var requests int
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithCircuitBreaker(CircuitBreakerOptions{Threshold: 3, Cooldown: 50 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func() error {
+		_, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}})
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		var apiErr *Error
+		if err := call(); !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an *Error for request %d, got %v", i+1, err)
+		}
+	}
+	var open *CircuitOpenError
+	if err := call(); !errors.As(err, &open) {
+		t.Fatalf("Expected a *CircuitOpenError after 3 failures, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected the open circuit not to send the request, got %d requests", requests)
+	}
+
+	failing = false
+	time.Sleep(60 * time.Millisecond)
+	if err := call(); err != nil {
+		t.Fatalf("Expected the circuit to let requests through after the cooldown, got %v", err)
+	}
+	failing = true
+	if err := call(); errors.As(err, &open) {
+		t.Errorf("Expected a success to close the circuit, got %v", err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientRateLimit(t *testing.T) {
+	runGeneratedGoClientTest(t, &testSpec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	reset := "30"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := client.RateLimit(); !state.UpdatedAt.IsZero() {
+		t.Fatalf("Expected no rate limit state before a response, got %+v", state)
+	}
+
+	before := time.Now()
+	if _, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("GetUserById() error = %v", err)
+	}
+	state := client.RateLimit()
+	if state.Limit != 100 || state.Remaining != 99 || state.UpdatedAt.Before(before) {
+		t.Errorf("Unexpected rate limit state %+v", state)
+	}
+	if wait := state.Reset.Sub(before); wait < 29*time.Second || wait > 31*time.Second {
+		t.Errorf("Expected the window to reset in 30s, got %v", wait)
+	}
+
+	reset = "1700000000"
+	if _, err := client.GetUserById(context.Background(), &GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("GetUserById() error = %v", err)
+	}
+	if got := client.RateLimit().Reset; !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected a Unix timestamp reset, got %v", got)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientMultipartResponse(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/logs": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "streamLogs",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Log events",
+							Content:     gopenapi.Content{"multipart/mixed": {}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMultipartResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer := multipart.NewWriter(w)
+		w.Header().Set("Content-Type", "multipart/mixed; boundary="+writer.Boundary())
+		for _, event := range []string{"started", "finished"} {
+			part, err := writer.CreatePart(map[string][]string{"Content-Type": {"text/plain"}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			io.WriteString(part, event)
+			w.(http.Flusher).Flush()
+		}
+		writer.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	err = client.StreamLogsParts(context.Background(), func(part *multipart.Part) error {
+		if part.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Expected a text/plain part, got %s", part.Header.Get("Content-Type"))
+		}
+		data, err := io.ReadAll(part)
+		events = append(events, string(data))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("StreamLogsParts() error = %v", err)
+	}
+	if len(events) != 2 || events[0] != "started" || events[1] != "finished" {
+		t.Errorf("Expected both parts to be delivered in order, got %q", events)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientNoContext(t *testing.T) {
+	opts := Options{PackageName: "testclient", NoContext: true}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&testSpec, &buf, "templates/go.tpl", "go", opts); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"func (c *Client) GetUserById(opts *GetUserByIdOptions) (",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected generated code to contain %q", expected)
+		}
+	}
+
+	runGeneratedGoClientTestWithOptions(t, &testSpec, opts, `package testclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNoContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `+"`"+`"alice"`+"`"+`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetUserById(&GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}); err != nil {
+		t.Fatalf("GetUserById() error = %v", err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientQueryStructs(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer, Default: 20}},
+						{Name: "role", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}}}},
+					},
+				},
+			},
+			"/orgs/{org}/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listOrgUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "org", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "role", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}}}},
+					},
+				},
+			},
+		},
+	}
+	opts := Options{PackageName: "testclient", QueryStructs: true}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", opts); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"type ListUsersQuery struct",
+		"func DefaultListUsersQuery() *ListUsersQuery",
+		"func (c *Client) ListUsers(ctx context.Context, query *ListUsersQuery) (",
+		"func (c *Client) ListOrgUsers(ctx context.Context, query *ListOrgUsersQuery, opts *ListOrgUsersOptions) (",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected generated code to contain %q", expected)
+		}
+	}
+	for _, unexpected := range []string{"ListUsersQueryParams", "ListUsersOptions", "opts.Query"} {
+		if strings.Contains(code, unexpected) {
+			t.Errorf("Expected generated code not to contain %q", unexpected)
+		}
+	}
+
+	runGeneratedGoClientTestWithOptions(t, &spec, opts, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryStructs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%q", r.URL.Path+"?"+r.URL.RawQuery)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := DefaultListUsersQuery()
+	query.Role = "admin"
+	got, err := client.ListUsers(context.Background(), query)
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if got != "/users?limit=20&role=admin" {
+		t.Errorf("ListUsers() sent %s", got)
+	}
+
+	got, err = client.ListOrgUsers(context.Background(), &ListOrgUsersQuery{Role: query.Role}, &ListOrgUsersOptions{Path: &ListOrgUsersPathParams{Org: "acme"}})
+	if err != nil {
+		t.Fatalf("ListOrgUsers() error = %v", err)
+	}
+	if got != "/orgs/acme/users?role=admin" {
+		t.Errorf("ListOrgUsers() sent %s", got)
+	}
+
+	if _, err := client.ListUsers(context.Background(), nil); err != nil {
+		t.Fatalf("ListUsers() without a query error = %v", err)
+	}
+}
+`)
+}
+
+// EmbeddedBase is embedded by EmbeddedResource, whose JSON holds its fields
+type EmbeddedBase struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+type EmbeddedResource struct {
+	EmbeddedBase
+	Name string `json:"name"`
+	// Kind shares its Go name with the promoted EmbeddedBase.Kind
+	Kind string `json:"type"`
+}
+
+func TestGenerateGoClientEmbeddedFields(t *testing.T) {
+	schema := gopenapi.Schema{Type: gopenapi.Object[EmbeddedResource]()}
+	spec := gopenapi.Spec{
+		OpenAPI:           "3.0.0",
+		Info:              gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:           gopenapi.Servers{{URL: "/"}},
+		ValidateResponses: true,
+		Paths: gopenapi.Paths{
+			"/resources": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "createResource",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: schema}},
+					},
+					Responses: gopenapi.Responses{
+						201: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: schema}}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var resource EmbeddedResource
+						if err := gopenapi.ValidateRequestBody(r, &resource); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusCreated, resource)
+					}),
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", Options{PackageName: "testclient"}); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, pattern := range []string{`ID\s+string\s+` + "`json:\"id\"`", `Kind\s+string\s+` + "`json:\"kind\"`", `Kind2\s+string\s+` + "`json:\"type\"`"} {
+		if !regexp.MustCompile(pattern).MatchString(code) {
+			t.Errorf("Expected the promoted fields to match %q", pattern)
+		}
+	}
+	if strings.Contains(code, "EmbeddedBase") {
+		t.Errorf("Expected the embedded struct to be flattened")
+	}
+
+	// The generated client talks to a server for the same spec
+	mux, err := gopenapi.NewServerMux(&spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv("GOPENAPI_SERVER_URL", server.URL)
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestEmbeddedFields(t *testing.T) {
+	client, err := NewClient(os.Getenv("GOPENAPI_SERVER_URL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := CreateResourceRequestBody{ID: "r1", Kind: "volume", Name: "data", Kind2: "ssd"}
+	got, err := client.CreateResource(context.Background(), &CreateResourceOptions{Body: &body})
+	if err != nil {
+		t.Fatalf("CreateResource() error = %v", err)
+	}
+	want := CreateResourceResponse{ID: "r1", Kind: "volume", Name: "data", Kind2: "ssd"}
+	if *got != want {
+		t.Errorf("CreateResource() = %+v, want %+v", *got, want)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientSkipsIgnoredFields(t *testing.T) {
+	type account struct {
+		ID     int    `json:"id"`
+		Secret string `json:"-"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/account": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "updateAccount",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[account]()}}},
+					},
+					Responses: gopenapi.Responses{
+						200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[account]()}}}},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", Options{PackageName: "testclient"}); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	if !strings.Contains(code, "`json:\"id\"`") {
+		t.Errorf("Expected the id field in the generated structs")
+	}
+	if strings.Contains(code, "Secret") {
+		t.Errorf("Expected the json:\"-\" field to be omitted from the generated structs")
+	}
+}
+
+func TestGenerateClientTagOptions(t *testing.T) {
+	type order struct {
+		ID   int64  `json:"id,string"`
+		Note string `json:"note,omitempty"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/order": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getOrder",
+					Responses: gopenapi.Responses{
+						200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[order]()}}}},
+					},
+				},
+			},
+		},
+	}
+
+	var goBuf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &goBuf, "templates/go.tpl", "go", Options{PackageName: "testclient"}); err != nil {
+		t.Fatalf("Failed to generate Go client: %v", err)
+	}
+	goCode := goBuf.String()
+	for _, expected := range []string{"ID   string `json:\"id\"`", "Note string `json:\"note,omitempty\"`"} {
+		if !strings.Contains(goCode, expected) {
+			t.Errorf("Expected Go client to contain %q, got:\n%s", expected, goCode)
+		}
+	}
+
+	var tsBuf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &tsBuf, "templates/typescript.tpl", "typescript", Options{}); err != nil {
+		t.Fatalf("Failed to generate TypeScript client: %v", err)
+	}
+	tsCode := tsBuf.String()
+	for _, expected := range []string{"id: string", "note?: string"} {
+		if !strings.Contains(tsCode, expected) {
+			t.Errorf("Expected TypeScript client to contain %q, got:\n%s", expected, tsCode)
+		}
+	}
+}
+
+func TestGenerateGoClientValidatorTags(t *testing.T) {
+	type createUserBody struct {
+		Email string `json:"email" format:"email"`
+		Role  string `json:"role,omitempty" enum:"admin,member"`
+		Age   int    `json:"age"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "page", In: gopenapi.InQuery, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer, Minimum: gopenapi.Ptr(1.0)}},
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.IntRange(1, 100)},
+						{Name: "cursor", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}}}},
+					},
+				},
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[createUserBody]()}}},
+					},
+					Responses: gopenapi.Responses{201: {Description: "Created"}},
+				},
+			},
+		},
+	}
+	opts := Options{PackageName: "testclient", ValidatorTags: true}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", opts); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"`json:\"page\" validate:\"required,min=1\"`",
+		"`json:\"limit\" validate:\"omitempty,min=1,max=100\"`",
+		"`json:\"cursor\"`",
+		"`json:\"email\" validate:\"required,email\"`",
+		"`json:\"role,omitempty\" validate:\"omitempty,oneof=admin member\"`",
+		"`json:\"age\"`",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected generated code to contain %s", expected)
+		}
+	}
+
+	buf.Reset()
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", Options{PackageName: "testclient"}); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	if strings.Contains(buf.String(), "validate:") {
+		t.Error("Expected no validate tags without ValidatorTags")
+	}
+
+	runGeneratedGoClientTestWithOptions(t, &spec, opts, `package testclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatorTags(t *testing.T) {
+	field, _ := reflect.TypeOf(ListUsersQueryParams{}).FieldByName("Page")
+	if got := field.Tag.Get("validate"); got != "required,min=1" {
+		t.Errorf("validate tag = %q", got)
+	}
+}
+`)
+}
+
+func TestGenerateTemplateDataPathParameters(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/items/{id}": gopenapi.Path{
+				Parameters: gopenapi.Parameters{
+					{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+				},
+				Get: &gopenapi.Operation{
+					OperationId: "getItem",
+					Responses:   gopenapi.Responses{200: {Description: "Item"}},
+				},
+				Delete: &gopenapi.Operation{
+					OperationId: "deleteItem",
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{204: {Description: "Deleted"}},
+				},
+			},
+		},
+	}
+
+	data, err := generateTemplateData(&spec, "testclient")
+	if err != nil {
+		t.Fatalf("generateTemplateData() error = %v", err)
+	}
+	expected := map[string]string{"getItem": "int", "deleteItem": "string"}
+	for _, op := range data.Operations {
+		if len(op.PathParams) != 1 || op.PathParams[0].Name != "id" {
+			t.Errorf("Expected %s to take the shared id path parameter, got %+v", op.OperationId, op.PathParams)
+			continue
+		}
+		if got := op.PathParams[0].GoType; got != expected[op.OperationId] {
+			t.Errorf("Expected %s id of type %s, got %s", op.OperationId, expected[op.OperationId], got)
+		}
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import "testing"
+
+func TestPathParameters(t *testing.T) {
+	_ = GetItemOptions{Path: &GetItemPathParams{Id: 1}}
+	_ = DeleteItemOptions{Path: &DeleteItemPathParams{Id: "1"}}
+}
+`)
+}
+
+func TestGenerateTemplateDataDuplicateOperationIds(t *testing.T) {
+	getUser := func() *gopenapi.Operation {
+		return &gopenapi.Operation{
+			OperationId: "GetUserById",
+			Parameters: gopenapi.Parameters{
+				{Name: "id", In: gopenapi.InPath, Schema: gopenapi.Schema{Type: gopenapi.String}},
+			},
+			Responses: gopenapi.Responses{200: {Description: "The user"}},
+		}
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users/{id}":    gopenapi.Path{Get: getUser()},
+			"/v2/users/{id}": gopenapi.Path{Get: getUser()},
+			"/users":         gopenapi.Path{Get: &gopenapi.Operation{OperationId: "listUsers"}},
+		},
+	}
+
+	_, err := generateTemplateDataWithOptions(&spec, Options{PackageName: "testclient"})
+	if err == nil {
+		t.Fatal("Expected an error for duplicate operationIds")
+	}
+	want := `duplicate operationIds: operationId "GetUserById" is used by GET /users/{id}, GET /v2/users/{id}`
+	if err.Error() != want {
+		t.Errorf("error = %q, want %q", err.Error(), want)
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err == nil {
+		t.Error("Expected GenerateClientToWriter() to reject duplicate operationIds")
+	}
+
+	// Suffixes are assigned in path order regardless of map iteration order
+	for range 10 {
+		templateData, err := generateTemplateDataWithOptions(&spec, Options{PackageName: "testclient", AllowDuplicateIds: true})
+		if err != nil {
+			t.Fatalf("generateTemplateDataWithOptions() error = %v", err)
+		}
+		methods := map[string]string{}
+		for _, op := range templateData.Operations {
+			methods[op.Path] = op.MethodName
+		}
+		expected := map[string]string{
+			"/users":         "ListUsers",
+			"/users/{id}":    "GetUserById",
+			"/v2/users/{id}": "GetUserById2",
+		}
+		if !reflect.DeepEqual(methods, expected) {
+			t.Fatalf("Expected methods %v, got %v", expected, methods)
+		}
+	}
+
+	buf.Reset()
+	opts := Options{PackageName: "testclient", AllowDuplicateIds: true}
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", opts); err != nil {
+		t.Fatalf("GenerateClientToWriterWithOptions() error = %v", err)
+	}
+}
+
+func TestResolveOperationIdsNameCollision(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/a": gopenapi.Path{Get: &gopenapi.Operation{OperationId: "get_user"}},
+			"/b": gopenapi.Path{Get: &gopenapi.Operation{OperationId: "getUser"}},
+		},
+	}
+
+	_, err := resolveOperationIds(&spec, false)
+	want := "duplicate operationIds: operationIds of GET /a (get_user), GET /b (getUser) all generate GetUser"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestGenerateGoClientDefaultOptions(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer, Default: 20}},
+						{Name: "order", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Default: "asc"}},
+						{Name: "cursor", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "A page of users",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Array}},
+							},
+						},
+					},
+				},
+			},
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {Description: "The user"},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "func DefaultGetUserOptions()") {
+		t.Errorf("Expected no defaults constructor for an operation without query defaults, got:\n%s", buf.String())
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDefaultListUsersOptions(t *testing.T) {
+	opts := DefaultListUsersOptions()
+	if opts.Query.Limit != 20 || opts.Query.Order != "asc" || opts.Query.Cursor != "" {
+		t.Fatalf("DefaultListUsersOptions().Query = %+v, want Limit 20 and Order asc", *opts.Query)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RawQuery; got != "limit=20&order=asc" {
+			http.Error(w, "unexpected query "+got, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `+"`"+`[]`+"`"+`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ListUsers(context.Background(), opts); err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+}
+`)
+}
+
+func TestGoLiteral(t *testing.T) {
+	tests := []struct {
+		value  any
+		goType string
+		want   string
+		ok     bool
+	}{
+		{"asc", "string", `"asc"`, true},
+		{20, "int", "20", true},
+		{float64(20), "int", "20", true},
+		{1.5, "int", "", false},
+		{1.5, "float64", "1.5", true},
+		{true, "bool", "true", true},
+		{[]any{"a", "b"}, "[]string", `[]string{"a", "b"}`, true},
+		{"20", "int", "", false},
+		{nil, "string", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := goLiteral(tt.value, tt.goType)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("goLiteral(%#v, %q) = %q, %v; want %q, %v", tt.value, tt.goType, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGenerateGoClientFollowsLinkPagination(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Paginated:   true,
+					Parameters: gopenapi.Parameters{
+						{Name: "page", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "A page of users",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Array}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListUsersPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("Link", `+"`"+`</users?page=2>; rel="next", </users?page=1>; rel="first"`+"`"+`)
+			fmt.Fprint(w, `+"`"+`["alice","bob"]`+"`"+`)
+		case "2":
+			fmt.Fprint(w, `+"`"+`["carol"]`+"`"+`)
+		default:
+			http.Error(w, "unexpected page", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := client.ListUsersPages(context.Background(), &ListUsersOptions{
+		Query: &ListUsersQueryParams{Page: 1},
+	})
+
+	var got [][]interface{}
+	for pages.Next() {
+		got = append(got, pages.Page())
+	}
+	if err := pages.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+
+	want := [][]interface{}{{"alice", "bob"}, {"carol"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected pages %v, got %v", want, got)
+	}
+}
+`)
+}
+
+type listAddress struct {
+	City string `json:"city"`
+}
+
+type listUser struct {
+	ID      string      `json:"id"`
+	Address listAddress `json:"address"`
+}
+
+func TestGenerateGoClientArrayBodies(t *testing.T) {
+	users := gopenapi.Content{
+		gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[[]listUser]()}},
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Responses:   gopenapi.Responses{200: {Description: "Users", Content: users}},
+				},
+				Put: &gopenapi.Operation{
+					OperationId: "updateUsers",
+					RequestBody: gopenapi.RequestBody{Content: users},
+					Responses:   gopenapi.Responses{200: {Description: "Users", Content: users}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"type ListUser struct {",
+		"Address ListUserAddress `json:\"address\"`",
+		"type UpdateUsersRequestBody []ListUser",
+		"func (c *Client) ListUsers(ctx context.Context) ([]ListUser, error) {",
+		"func (c *Client) UpdateUsers(ctx context.Context, opts *UpdateUsersOptions) ([]ListUser, error) {",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Expected generated code to contain %q, got:\n%s", expected, code)
+		}
+	}
+	if strings.Count(code, "type ListUser struct {") != 1 {
+		t.Errorf("Expected operations to share one ListUser model, got:\n%s", code)
+	}
+	if strings.Contains(code, "[]interface{}") {
+		t.Errorf("Expected no []interface{} bodies, got:\n%s", code)
+	}
+
+	// Types parsed from source are named through Options.TypeName
+	opts := Options{PackageName: "testclient", TypeName: func(t reflect.Type) string {
+		if t == reflect.TypeOf(listUser{}) {
+			return "User"
+		}
+		return t.Name()
+	}}
+	buf.Reset()
+	if err := GenerateClientToWriterWithOptions(&spec, &buf, "templates/go.tpl", "go", opts); err != nil {
+		t.Fatalf("GenerateClientToWriterWithOptions() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "func (c *Client) ListUsers(ctx context.Context) ([]User, error) {") {
+		t.Errorf("Expected TypeName to name the model, got:\n%s", buf.String())
+	}
+
+	const server = `
+func newServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			io.Copy(w, r.Body)
+			return
+		}
+		fmt.Fprint(w, ` + "`" + `[{"id":"1","address":{"city":"Paris"}}]` + "`" + `)
+	}))
+}
+`
+	t.Run("single package", func(t *testing.T) {
+		runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+`+server+`
+func TestArrayBodies(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := client.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if len(users) != 1 || users[0].Address.City != "Paris" {
+		t.Errorf("ListUsers() = %+v", users)
+	}
+
+	updated, err := client.UpdateUsers(context.Background(), &UpdateUsersOptions{
+		Body: &UpdateUsersRequestBody{{ID: "2", Address: ListUserAddress{City: "Lyon"}}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateUsers() error = %v", err)
+	}
+	if len(updated) != 1 || updated[0].ID != "2" || updated[0].Address.City != "Lyon" {
+		t.Errorf("UpdateUsers() = %+v", updated)
+	}
+}
+`)
+	})
+	t.Run("models package", func(t *testing.T) {
+		runGeneratedGoClientTestWithOptions(t, &spec, Options{PackageName: "testclient", ModelsPackage: "testclient/models"}, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"testclient/models"
+)
+`+server+`
+func TestArrayBodies(t *testing.T) {
+	server := newServer(t)
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []models.ListUser
+	users, err = client.UpdateUsers(context.Background(), &models.UpdateUsersOptions{
+		Body: &models.UpdateUsersRequestBody{{ID: "2"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateUsers() error = %v", err)
+	}
+	if len(users) != 1 || users[0].ID != "2" {
+		t.Errorf("UpdateUsers() = %+v", users)
+	}
+}
+`)
+	})
+}
+
+func TestGenerateGoClientPagesOnlyForPaginatedOperations(t *testing.T) {
+	jsonResponse := gopenapi.Responses{
+		200: {
+			Description: "OK",
+			Content: gopenapi.Content{
+				gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Array}},
+			},
+		},
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{OperationId: "listUsers", Paginated: true, Responses: jsonResponse},
+			},
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{OperationId: "getUserById", Responses: jsonResponse},
+			},
+			"/events": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "streamEvents",
+					Paginated:   true,
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Events",
+							Content: gopenapi.Content{
+								"text/event-stream": {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+			"/logs": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "streamLogs",
+					Paginated:   true,
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Log parts",
+							Content: gopenapi.Content{
+								"multipart/mixed": {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+	code := buf.String()
+	if !strings.Contains(code, "func (c *Client) ListUsersPages(") {
+		t.Errorf("Expected a page iterator for the paginated operation, got:\n%s", code)
+	}
+	for _, unexpected := range []string{"GetUserByIdPages", "StreamEventsPages", "StreamLogsPages"} {
+		if strings.Contains(code, unexpected) {
+			t.Errorf("Expected no %s iterator", unexpected)
+		}
+	}
+
+	// Clients without paginated operations leave out PageIterator
+	delete(spec.Paths, "/users")
+	buf.Reset()
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "PageIterator") {
+		t.Errorf("Expected no PageIterator without paginated operations")
+	}
+}
+
+func TestGenerateGoClientAppliesAuth(t *testing.T) {
+	okResponse := gopenapi.Responses{
+		200: {
+			Description: "OK",
+			Content: gopenapi.Content{
+				gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+			},
+		},
+	}
+	spec := gopenapi.Spec{
+		Components: gopenapi.Components{
+			SecuritySchemes: gopenapi.SecuritySchemes{
+				"apiKeyAuth": {Type: gopenapi.APIKey, In: gopenapi.InHeader, Name: "X-API-Key"},
+				"bearerAuth": {Type: gopenapi.HTTP, Scheme: gopenapi.BearerScheme},
+			},
+		},
+		Security: []gopenapi.Security{{"apiKeyAuth": {}}},
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{OperationId: "listUsers", Responses: okResponse},
+			},
+			"/admin": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getAdmin",
+					Security:    []gopenapi.Security{{"bearerAuth": {}}},
+					Responses:   okResponse,
+				},
+			},
+			"/health": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getHealth",
+					Security:    gopenapi.NoSecurity,
+					Responses:   okResponse,
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(r.Header.Get("X-API-Key") + "|" + r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, WithAPIKey("secret"), WithBearerToken("token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() (string, error)
+		want string
+	}{
+		{"inherited api key", func() (string, error) { return client.ListUsers(ctx) }, "secret|"},
+		{"operation bearer", func() (string, error) { return client.GetAdmin(ctx) }, "|Bearer token"},
+		{"no security", func() (string, error) { return client.GetHealth(ctx) }, "|"},
+	}
+	for _, tt := range tests {
+		got, err := tt.call()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected credentials %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+`)
+}
+
+func TestGenerateGoClientHeaderPrecedence(t *testing.T) {
+	spec := gopenapi.Spec{
+		Components: gopenapi.Components{
+			SecuritySchemes: gopenapi.SecuritySchemes{
+				"bearerAuth": {Type: gopenapi.HTTP, Scheme: gopenapi.BearerScheme},
+			},
+		},
+		Security: []gopenapi.Security{{"bearerAuth": {}}},
+		Paths: gopenapi.Paths{
+			"/me": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getMe",
+					Parameters: gopenapi.Parameters{
+						{Name: "Authorization", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "OK",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderPrecedence(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	perCall := &GetMeOptions{Headers: &GetMeHeaderParams{Authorization: "Bearer per-call"}}
+
+	client, err := NewClient(server.URL, WithBearerToken("token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := client.GetMe(ctx, nil); err != nil || got != "Bearer token" {
+		t.Errorf("auth option: expected %q, got %q (%v)", "Bearer token", got, err)
+	}
+	if got, err := client.GetMe(ctx, perCall); err != nil || got != "Bearer per-call" {
+		t.Errorf("per-call over auth option: expected %q, got %q (%v)", "Bearer per-call", got, err)
+	}
+
+	client.SetHeader("Authorization", "Bearer default")
+	if got, err := client.GetMe(ctx, nil); err != nil || got != "Bearer default" {
+		t.Errorf("default header over auth option: expected %q, got %q (%v)", "Bearer default", got, err)
+	}
+	if got, err := client.GetMe(ctx, perCall); err != nil || got != "Bearer per-call" {
+		t.Errorf("per-call over default header: expected %q, got %q (%v)", "Bearer per-call", got, err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientOptions(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/ping": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "ping",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "OK",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOptions(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`+"`"+`"pong"`+"`"+`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client, err := NewClient("http://unused.invalid",
+		WithBaseURL(server.URL+"/"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.Ping(context.Background())
+	if err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if got != "pong" {
+		t.Errorf("expected pong, got %q", got)
+	}
+	if len(paths) != 1 || paths[0] != "/ping" {
+		t.Errorf("expected one request to /ping on the test server, got %v", paths)
+	}
+	if transport.requests != 1 {
+		t.Errorf("expected the custom http.Client to send 1 request, got %d", transport.requests)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientRequiresBaseURL(t *testing.T) {
+	paths := gopenapi.Paths{
+		"/ping": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "ping",
+				Responses:   gopenapi.Responses{204: {Description: "OK"}},
+			},
+		},
+	}
+
+	t.Run("no servers", func(t *testing.T) {
+		runGeneratedGoClientTest(t, &gopenapi.Spec{Paths: paths}, `package testclient
+
+import "testing"
+
+func TestNewClient(t *testing.T) {
+	if _, err := NewClient(""); err == nil {
+		t.Error("expected an error without a base URL")
+	}
+	client, err := NewClient("", WithBaseURL("https://api.example.com"))
+	if err != nil {
+		t.Fatalf("expected WithBaseURL to satisfy the base URL, got %v", err)
+	}
+	if client.BaseURL != "https://api.example.com" {
+		t.Errorf("unexpected base URL %q", client.BaseURL)
+	}
+}
+`)
+	})
+
+	t.Run("with servers", func(t *testing.T) {
+		spec := gopenapi.Spec{
+			Paths: paths,
+			Servers: gopenapi.Servers{
+				{URL: "/"},
+				{URL: "https://api.example.com/v1/"},
+			},
+		}
+		runGeneratedGoClientTest(t, &spec, `package testclient
+
+import "testing"
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient("")
+	if err != nil {
+		t.Fatalf("expected the spec server to be used, got %v", err)
+	}
+	if client.BaseURL != "https://api.example.com/v1" {
+		t.Errorf("unexpected base URL %q", client.BaseURL)
+	}
+}
+`)
+	})
+}
+
+func TestGenerateGoClientInterceptors(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "OK",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`+"`"+`"`+"`"+` + r.Header.Get("X-Trace-Id") + `+"`"+`"`+"`"+`))
+	}))
+	defer server.Close()
+
+	var seenURL, seenBody string
+	client, err := NewClient(server.URL,
+		WithRequestInterceptor(func(req *http.Request) error {
+			seenURL = req.URL.String()
+			req.Header.Set("X-Trace-Id", "trace-1")
+			return nil
+		}),
+		WithResponseInterceptor(func(resp *http.Response) error {
+			body, err := io.ReadAll(resp.Body)
+			seenBody = string(body)
+			return err
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := client.GetUser(context.Background(), &GetUserOptions{Path: &GetUserPathParams{Id: "42"}})
+	if err != nil {
+		t.Fatalf("GetUser failed: %v", err)
+	}
+	if seenURL != server.URL+"/users/42" {
+		t.Errorf("expected interceptor to observe %s, got %s", server.URL+"/users/42", seenURL)
+	}
+	if got != "trace-1" || seenBody != `+"`"+`"trace-1"`+"`"+` {
+		t.Errorf("expected header set by the request interceptor to round-trip, got result %q and body %q", got, seenBody)
+	}
+
+	blocked := errors.New("blocked")
+	client, _ = NewClient(server.URL, WithRequestInterceptor(func(*http.Request) error { return blocked }))
+	if _, err := client.GetUser(context.Background(), nil); !errors.Is(err, blocked) {
+		t.Errorf("expected request interceptor error, got %v", err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientSplitModels(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenerateClientForLanguageWithOptions(&testSpec, "go", dir, Options{PackageName: "client", SplitModels: true}); err != nil {
+		t.Fatalf("GenerateClientForLanguageWithOptions() error = %v", err)
+	}
+
+	fset := token.NewFileSet()
+	declared := map[string]map[string]bool{}
+	for _, name := range []string{"client.go", "models.go"} {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			t.Fatalf("Generated %s does not parse: %v", name, err)
+		}
+		if file.Name.Name != "client" {
+			t.Errorf("Expected %s to be in package client, got %s", name, file.Name.Name)
+		}
+		declared[name] = map[string]bool{}
+		for _, decl := range file.Decls {
+			if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+				for _, spec := range gen.Specs {
+					declared[name][spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			}
+		}
+	}
+
+	// The split files must declare exactly the types of the single-file client
+	var single bytes.Buffer
+	if err := GenerateClientToWriterWithOptions(&testSpec, &single, "templates/go.tpl", "go", Options{PackageName: "client"}); err != nil {
+		t.Fatalf("GenerateClientToWriterWithOptions() error = %v", err)
+	}
+	file, err := parser.ParseFile(fset, "single.go", single.Bytes(), 0)
+	if err != nil {
+		t.Fatalf("Generated client does not parse: %v", err)
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			name := spec.(*ast.TypeSpec).Name.Name
+			inClient, inModels := declared["client.go"][name], declared["models.go"][name]
+			if inClient == inModels {
+				t.Errorf("Expected %s to be declared in exactly one file (client.go: %v, models.go: %v)", name, inClient, inModels)
+			}
+			delete(declared["client.go"], name)
+			delete(declared["models.go"], name)
+		}
+	}
+	for name, types := range declared {
+		for typeName := range types {
+			t.Errorf("%s declares %s, which the single-file client does not", name, typeName)
+		}
+	}
+
+	models, _ := os.ReadFile(filepath.Join(dir, "models.go"))
+	for _, name := range []string{"type Client struct", "func NewClient"} {
+		if strings.Contains(string(models), name) {
+			t.Errorf("Expected models.go not to contain %q", name)
+		}
+	}
+	if !strings.Contains(string(models), "type GetUserByIdOptions struct") {
+		t.Errorf("Expected models.go to declare the options model, got:\n%s", models)
+	}
+
+	t.Run("compiles", func(t *testing.T) {
+		runGeneratedGoClientTestWithOptions(t, &testSpec, Options{PackageName: "testclient", SplitModels: true}, `package testclient
+
+import "testing"
+
+func TestModels(t *testing.T) {
+	_ = GetUserByIdOptions{Path: &GetUserByIdPathParams{Id: 1}}
+}
+`)
+	})
+}
+
+func TestGenerateGoClientModelsPackage(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Parameters: gopenapi.Parameters{
+						{Name: "notify", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Boolean, Default: true}},
+					},
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[struct {
+								Name    string  `json:"name"`
+								Address address `json:"address"`
+							}]()}},
+						},
+					},
+					Responses: gopenapi.Responses{
+						201: {
+							Description: "Created",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[struct {
+									ID int `json:"id"`
+								}]()}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	opts := Options{PackageName: "testclient", ModelsPackage: "testclient/models"}
+
+	dir := t.TempDir()
+	if err := GenerateClientForLanguageWithOptions(&spec, "go", dir, opts); err != nil {
+		t.Fatalf("GenerateClientForLanguageWithOptions() error = %v", err)
+	}
+
+	fset := token.NewFileSet()
+	models, err := parser.ParseFile(fset, filepath.Join(dir, "models", "models.go"), nil, 0)
+	if err != nil {
+		t.Fatalf("Generated models.go does not parse: %v", err)
+	}
+	if models.Name.Name != "models" {
+		t.Errorf("Expected models.go to be in package models, got %s", models.Name.Name)
+	}
+	declared := map[string]bool{}
+	for _, decl := range models.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				declared[spec.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+	for _, name := range []string{"CreateUserOptions", "CreateUserQueryParams", "CreateUserRequestBody", "CreateUserRequestBodyAddress", "CreateUserResponse"} {
+		if !declared[name] {
+			t.Errorf("Expected models.go to declare %s, got %v", name, declared)
+		}
+	}
+
+	client, err := parser.ParseFile(fset, filepath.Join(dir, "client.go"), nil, 0)
+	if err != nil {
+		t.Fatalf("Generated client.go does not parse: %v", err)
+	}
+	imported := false
+	for _, spec := range client.Imports {
+		imported = imported || spec.Path.Value == `"testclient/models"`
+	}
+	if !imported {
+		t.Error("Expected client.go to import testclient/models")
+	}
+	for _, decl := range client.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				if name := spec.(*ast.TypeSpec).Name.Name; declared[name] {
+					t.Errorf("Expected %s to be declared only in the models package", name)
+				}
+			}
+		}
+	}
+
+	runGeneratedGoClientTestWithOptions(t, &spec, opts, `package testclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"testclient/models"
+)
+
+func TestCreateUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("notify") != "true" {
+			http.Error(w, "missing notify", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `+"`"+`{"id":7}`+"`"+`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := models.DefaultCreateUserOptions()
+	opts.Body = &models.CreateUserRequestBody{Name: "ann", Address: models.CreateUserRequestBodyAddress{City: "Oslo"}}
+	var user *models.CreateUserResponse
+	user, err = client.CreateUser(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if user.ID != 7 {
+		t.Errorf("Expected ID 7, got %d", user.ID)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientOperationTimeouts(t *testing.T) {
+	operation := func(operationId string, timeout time.Duration) *gopenapi.Operation {
+		return &gopenapi.Operation{
+			OperationId: operationId,
+			Timeout:     timeout,
+			Responses: gopenapi.Responses{
+				200: {
+					Description: "OK",
+					Content: gopenapi.Content{
+						gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+				},
+			},
+		}
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/generations": gopenapi.Path{
+				Post: operation("createGeneration", 10*time.Minute),
+			},
+			"/health": gopenapi.Path{
+				Get: operation("health", 1500*time.Millisecond),
+			},
+			"/users": gopenapi.Path{
+				Get: operation("listUsers", 0),
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`+"`"+`"ok"`+"`"+`))
+	}))
+	defer server.Close()
+
+	var remaining time.Duration
+	var hasDeadline bool
+	client, err := NewClient(server.URL, WithRequestInterceptor(func(req *http.Request) error {
+		var deadline time.Time
+		deadline, hasDeadline = req.Context().Deadline()
+		remaining = time.Until(deadline)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := client.CreateGeneration(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !hasDeadline || remaining <= 9*time.Minute || remaining > 10*time.Minute {
+		t.Errorf("expected a 10m default deadline for CreateGeneration, got %v (deadline set: %v)", remaining, hasDeadline)
+	}
+
+	if _, err := client.Health(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !hasDeadline || remaining <= 0 || remaining > 1500*time.Millisecond {
+		t.Errorf("expected a 1.5s default deadline for Health, got %v (deadline set: %v)", remaining, hasDeadline)
+	}
+
+	if _, err := client.ListUsers(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if hasDeadline {
+		t.Errorf("expected no default deadline for ListUsers, got %v", remaining)
+	}
+
+	callerCtx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+	if _, err := client.Health(callerCtx); err != nil {
+		t.Fatal(err)
+	}
+	if remaining <= 59*time.Minute {
+		t.Errorf("expected the caller's deadline to take precedence, got %v", remaining)
+	}
+}
+`)
+}
+
+func TestGoDurationExpr(t *testing.T) {
+	tests := map[time.Duration]string{
+		2 * time.Hour:           "2 * time.Hour",
+		90 * time.Second:        "90 * time.Second",
+		1500 * time.Millisecond: "1500 * time.Millisecond",
+		time.Duration(1500):     "time.Duration(1500)",
+	}
+	for d, want := range tests {
+		if got := goDurationExpr(d); got != want {
+			t.Errorf("goDurationExpr(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestGenerateGoClientTagClients(t *testing.T) {
+	operation := func(operationId string, tags ...string) *gopenapi.Operation {
+		return &gopenapi.Operation{
+			OperationId: operationId,
+			Tags:        tags,
+			Responses: gopenapi.Responses{
+				200: {
+					Description: "OK",
+					Content: gopenapi.Content{
+						gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+				},
+			},
+		}
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get:  operation("listUsers", "users"),
+				Post: operation("createUser", "users", "admin"),
+			},
+			"/orders": gopenapi.Path{
+				Get: operation("listOrders", "orders"),
+			},
+			"/health": gopenapi.Path{
+				Get: operation("health"),
+			},
+		},
+	}
+
+	runGeneratedGoClientTestWithOptions(t, &spec, Options{PackageName: "testclient", TagClients: true}, `package testclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func methods(v any) []string {
+	var names []string
+	typ := reflect.TypeOf(v)
+	for i := 0; i < typ.NumMethod(); i++ {
+		names = append(names, typ.Method(i).Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestTagClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`+"`"+`"`+"`"+` + r.Method + " " + r.URL.Path + `+"`"+`"`+"`"+`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := methods(client.Users()), []string{"CreateUser", "ListUsers"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UsersClient methods = %v, want %v", got, want)
+	}
+	if got, want := methods(client.Orders()), []string{"ListOrders"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OrdersClient methods = %v, want %v", got, want)
+	}
+	for _, name := range []string{"ListUsers", "CreateUser", "ListOrders"} {
+		if _, ok := reflect.TypeOf(client).MethodByName(name); ok {
+			t.Errorf("expected %s to be available only on its tag client", name)
+		}
+	}
+
+	ctx := context.Background()
+	if got, err := client.Users().CreateUser(ctx); err != nil || got != "POST /users" {
+		t.Errorf("CreateUser() = %q, %v", got, err)
+	}
+	if got, err := client.Orders().ListOrders(ctx); err != nil || got != "GET /orders" {
+		t.Errorf("ListOrders() = %q, %v", got, err)
+	}
+	if got, err := client.Health(ctx); err != nil || got != "GET /health" {
+		t.Errorf("Health() = %q, %v", got, err)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientNestedStructs(t *testing.T) {
+	type Address struct {
+		City   string `json:"city"`
+		Street string `json:"street"`
+	}
+	type Customer struct {
+		Name    string   `json:"name"`
+		Address *Address `json:"address"`
+	}
+	type LineItem struct {
+		SKU      string `json:"sku"`
+		Quantity int    `json:"quantity"`
+	}
+	type CreateOrderRequest struct {
+		Customer Customer   `json:"customer"`
+		Items    []LineItem `json:"items"`
+		Billing  Address    `json:"billing"`
+	}
+
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/orders": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "createOrder",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[CreateOrderRequest]()}},
+						},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Order",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("GenerateClientToWriter() error = %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		`type CreateOrderRequestBodyCustomer struct`,
+		`type CreateOrderRequestBodyCustomerAddress struct`,
+		`type CreateOrderRequestBodyItems struct`,
+		`Customer\s+CreateOrderRequestBodyCustomer\s+` + "`",
+		`Address\s+\*CreateOrderRequestBodyCustomerAddress\s+` + "`",
+		`Items\s+\[\]CreateOrderRequestBodyItems\s+` + "`",
+		// Address is shared, so Billing reuses the type generated for its first use
+		`Billing\s+CreateOrderRequestBodyCustomerAddress\s+` + "`",
+	}
+	for _, pattern := range expected {
+		if !regexp.MustCompile(pattern).MatchString(output) {
+			t.Errorf("Expected generated client to match %q, got:\n%s", pattern, output)
+		}
+	}
+	if strings.Contains(output, "interface{}") {
+		t.Errorf("Expected nested fields to be typed, got:\n%s", output)
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNestedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		customer := body["customer"].(map[string]any)
+		address := customer["address"].(map[string]any)
+		items := body["items"].([]any)
+		json.NewEncoder(w).Encode(address["city"].(string) + "/" + items[0].(map[string]any)["sku"].(string))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.CreateOrder(context.Background(), &CreateOrderOptions{
+		Body: &CreateOrderRequestBody{
+			Customer: CreateOrderRequestBodyCustomer{
+				Name:    "Alice",
+				Address: &CreateOrderRequestBodyCustomerAddress{City: "Berlin"},
+			},
+			Items: []CreateOrderRequestBodyItems{{SKU: "sku-1", Quantity: 2}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Berlin/sku-1" {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientArrayQueryParameters(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{
+							Name:   "tags",
+							In:     gopenapi.InQuery,
+							Schema: gopenapi.Schema{Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.String}},
+						},
+						{
+							Name:    "ids",
+							In:      gopenapi.InQuery,
+							Explode: gopenapi.Ptr(false),
+							Schema:  gopenapi.Schema{Type: reflect.TypeOf([]int{})},
+						},
+					},
+					Responses: gopenapi.Responses{
+						200: {Description: "OK"},
+					},
+				},
+			},
+		},
+	}
+
+	var buf strings.Builder
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"Tags []string",
+		"Ids  []int",
+		"for _, v := range opts.Query.Tags {\n\t\t\tparams.Add(\"tags\", v)",
+		"params.Add(\"ids\", strings.Join(values, \",\"))",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated client missing %q:\n%s", expected, code)
+		}
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestArrayQuery(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.ListUsers(context.Background(), &ListUsersOptions{
+		Query: &ListUsersQueryParams{Tags: []string{"a", "b"}, Ids: []int{1, 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ids=1%2C2&tags=a&tags=b"; rawQuery != want {
+		t.Errorf("expected query %q, got %q", want, rawQuery)
+	}
+}
+`)
+}
+
+func TestGenerateGoClientMultipartRequestBody(t *testing.T) {
+	type UploadAvatarRequest struct {
+		UserID string    `json:"user_id"`
+		Size   int       `json:"size"`
+		Tags   []string  `json:"tags"`
+		File   io.Reader `json:"file"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/avatars": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "uploadAvatar",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.MultipartFormData: {Schema: gopenapi.Schema{Type: gopenapi.Object[UploadAvatarRequest]()}},
+						},
+					},
+					Responses: gopenapi.Responses{
+						201: {Description: "Uploaded"},
+					},
+				},
+			},
 		},
-		{
-			name:        "empty input",
-			operationId: "",
-			expected:    "",
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	for _, expected := range []string{
+		"multipart.NewWriter(pw)",
+		"File   io.Reader",
+		`writer.CreateFormFile("file", "file")`,
+		`req.Header.Set("Content-Type", writer.FormDataContentType())`,
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated client missing %q:\n%s", expected, code)
+		}
+	}
+	if strings.Contains(code, "json.Marshal(opts.Body)") {
+		t.Error("Multipart request body should not be marshaled as JSON")
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMultipartUpload(t *testing.T) {
+	var fields map[string][]string
+	var file string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("ParseMultipartForm: %v", err)
+			return
+		}
+		fields = r.MultipartForm.Value
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			t.Errorf("FormFile: %v", err)
+			return
+		}
+		data, _ := io.ReadAll(f)
+		file = string(data)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.UploadAvatar(context.Background(), &UploadAvatarOptions{
+		Body: &UploadAvatarRequestBody{
+			UserID: "u1",
+			Size:   64,
+			Tags:   []string{"a", "b"},
+			File:   strings.NewReader("image bytes"),
 		},
+	})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := ToStructName(tt.operationId)
-			if result != tt.expected {
-				t.Errorf("ToStructName() = %v, want %v", result, tt.expected)
-			}
-		})
+	expected := map[string][]string{"user_id": {"u1"}, "size": {"64"}, "tags": {"a", "b"}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected fields %v, got %v", expected, fields)
+	}
+	if file != "image bytes" {
+		t.Errorf("expected file contents %q, got %q", "image bytes", file)
 	}
 }
+`)
+}
 
-func TestToMethodName(t *testing.T) {
+func TestGenerateGoClientErrorClassification(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Responses: gopenapi.Responses{
+						200: {Description: "OK"},
+					},
+				},
+			},
+		},
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorClassification(t *testing.T) {
 	tests := []struct {
-		name        string
-		operationId string
-		expected    string
+		status       int
+		retryable    bool
+		notFound     bool
+		unauthorized bool
 	}{
-		{
-			name:        "camelCase input",
-			operationId: "getUserById",
-			expected:    "GetUserById",
-		},
-		{
-			name:        "PascalCase input",
-			operationId: "GetUserById",
-			expected:    "Getuserbyid", // Actual behavior
-		},
-		{
-			name:        "snake_case input",
-			operationId: "get_user_by_id",
-			expected:    "GetUserById", // Should convert properly
-		},
-		{
-			name:        "empty input",
-			operationId: "",
-			expected:    "",
-		},
+		{http.StatusTooManyRequests, true, false, false},
+		{http.StatusServiceUnavailable, true, false, false},
+		{http.StatusNotFound, false, true, false},
+		{http.StatusUnauthorized, false, false, true},
+		{http.StatusBadRequest, false, false, false},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := ToMethodName(tt.operationId)
-			if result != tt.expected {
-				t.Errorf("ToMethodName() = %v, want %v", result, tt.expected)
-			}
-		})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+
+		client, err := NewClient(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = client.GetUser(context.Background())
+		server.Close()
+
+		var apiErr *Error
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("status %d: expected *Error, got %v", tt.status, err)
+		}
+		if apiErr.IsRetryable() != tt.retryable {
+			t.Errorf("status %d: IsRetryable() = %v, want %v", tt.status, apiErr.IsRetryable(), tt.retryable)
+		}
+		if apiErr.IsNotFound() != tt.notFound {
+			t.Errorf("status %d: IsNotFound() = %v, want %v", tt.status, apiErr.IsNotFound(), tt.notFound)
+		}
+		if apiErr.IsUnauthorized() != tt.unauthorized {
+			t.Errorf("status %d: IsUnauthorized() = %v, want %v", tt.status, apiErr.IsUnauthorized(), tt.unauthorized)
+		}
 	}
 }
+`)
+}
 
-func TestToGoName(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "snake_case",
-			input:    "user_id",
-			expected: "UserId",
-		},
-		{
-			name:     "kebab-case",
-			input:    "user-id",
-			expected: "UserId",
-		},
-		{
-			name:     "dot.case",
-			input:    "user.id",
-			expected: "UserId",
-		},
-		{
-			name:     "single word",
-			input:    "user",
-			expected: "User",
-		},
-		{
-			name:     "already PascalCase",
-			input:    "UserId",
-			expected: "UserId",
+func TestGenerateGoClientNoContentResponse(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users/{id}": gopenapi.Path{
+				Delete: &gopenapi.Operation{
+					OperationId: "deleteUser",
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						204: {Description: "Deleted"},
+					},
+				},
+			},
 		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := ToGoName(tt.input)
-			if result != tt.expected {
-				t.Errorf("ToGoName() = %v, want %v", result, tt.expected)
-			}
-		})
+	var buf strings.Builder
+	if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/go.tpl", "go"); err != nil {
+		t.Fatalf("Failed to generate client: %v", err)
+	}
+	code := buf.String()
+	if !strings.Contains(code, "func (c *Client) DeleteUser(ctx context.Context, opts *DeleteUserOptions) error {") {
+		t.Errorf("Expected DeleteUser to return only an error, got:\n%s", code)
+	}
+	for _, unexpected := range []string{"decodeDeleteUserResponse", "json.Unmarshal", "DeleteUserResponse"} {
+		if strings.Contains(code, unexpected) {
+			t.Errorf("Expected no decode logic for a 204 response, found %q", unexpected)
+		}
+	}
+
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNoContent(t *testing.T) {
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteUser(context.Background(), &DeleteUserOptions{Path: &DeleteUserPathParams{Id: "42"}}); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+
+	status = http.StatusNotFound
+	var apiErr *Error
+	if err := client.DeleteUser(context.Background(), &DeleteUserOptions{Path: &DeleteUserPathParams{Id: "42"}}); !errors.As(err, &apiErr) || !apiErr.IsNotFound() {
+		t.Errorf("expected a not found *Error, got %v", err)
 	}
 }
+`)
+}
 
-func TestGenerateConvertToString(t *testing.T) {
+func TestResponseFormat(t *testing.T) {
 	tests := []struct {
-		name     string
-		goName   string
-		goType   string
-		expected string
+		mediaType gopenapi.MediaType
+		schema    gopenapi.Schema
+		expected  string
 	}{
-		{
-			name:     "string type",
-			goName:   "Id",
-			goType:   "string",
-			expected: "opts.Path.Id",
-		},
-		{
-			name:     "int type",
-			goName:   "Id",
-			goType:   "int",
-			expected: "strconv.Itoa(opts.Path.Id)",
-		},
-		{
-			name:     "float64 type",
-			goName:   "Price",
-			goType:   "float64",
-			expected: "strconv.FormatFloat(opts.Path.Price, 'f', -1, 64)",
-		},
-		{
-			name:     "bool type",
-			goName:   "Active",
-			goType:   "bool",
-			expected: "strconv.FormatBool(opts.Path.Active)",
-		},
-		{
-			name:     "other type",
-			goName:   "Data",
-			goType:   "interface{}",
-			expected: "fmt.Sprintf(\"%v\", opts.Path.Data)",
-		},
+		{gopenapi.ApplicationJSON, gopenapi.Schema{Type: gopenapi.String}, "json"},
+		{"application/problem+json", gopenapi.Schema{}, "json"},
+		{gopenapi.TextPlain, gopenapi.Schema{Type: gopenapi.String}, "text"},
+		{gopenapi.ApplicationYAML, gopenapi.Schema{}, "text"},
+		{gopenapi.ImagePNG, gopenapi.Schema{}, "binary"},
+		{"application/octet-stream", gopenapi.Schema{Type: gopenapi.String}, "binary"},
+		{gopenapi.TextPlain, gopenapi.Schema{Type: gopenapi.String, Format: "binary"}, "binary"},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := generateConvertToString(tt.goName, tt.goType)
-			if result != tt.expected {
-				t.Errorf("generateConvertToString() = %v, want %v", result, tt.expected)
+		t.Run(string(tt.mediaType), func(t *testing.T) {
+			if got := responseFormat(tt.mediaType, tt.schema); got != tt.expected {
+				t.Errorf("responseFormat(%q) = %q, want %q", tt.mediaType, got, tt.expected)
 			}
 		})
 	}
 }
 
-func TestGenerateTemplateData(t *testing.T) {
-	templateData := generateTemplateData(&testSpec, "testclient")
-
-	if templateData.PackageName != "testclient" {
-		t.Errorf("Expected PackageName to be 'testclient', got %v", templateData.PackageName)
+func TestGenerateClientResponseWithoutContentType(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
 	}
-
-	if len(templateData.Operations) == 0 {
-		t.Error("Expected at least one operation in template data")
+	response := func(mediaType gopenapi.MediaType, schema gopenapi.Schema) gopenapi.Responses {
+		return gopenapi.Responses{
+			200: {
+				Description: "OK",
+				Content:     gopenapi.Content{mediaType: {Schema: schema}},
+			},
+		}
 	}
-
-	// Check the first operation
-	op := templateData.Operations[0]
-	if op.OperationId != "getUserById" {
-		t.Errorf("Expected OperationId to be 'getUserById', got %v", op.OperationId)
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/user": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Responses:   response(gopenapi.ApplicationJSON, gopenapi.Schema{Type: gopenapi.Object[User]()}),
+				},
+			},
+			"/avatar": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getAvatar",
+					Responses:   response(gopenapi.ImagePNG, gopenapi.Schema{}),
+				},
+			},
+			"/motd": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getMotd",
+					Responses:   response(gopenapi.TextPlain, gopenapi.Schema{Type: gopenapi.String}),
+				},
+			},
+		},
 	}
 
-	if op.Method != "GET" {
-		t.Errorf("Expected Method to be 'GET', got %v", op.Method)
-	}
+	t.Run("typescript", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := GenerateClientToWriter(&spec, &buf, "testclient", "templates/typescript.tpl", "typescript"); err != nil {
+			t.Fatalf("GenerateClientToWriter() error = %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{
+			"let format: ResponseFormat = options.format ?? 'text';",
+			"format: 'json',",
+			"format: 'binary',",
+			"format: 'text',",
+			"async getAvatar(\n  ): Promise<ArrayBuffer>",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected TypeScript output to contain %q, got:\n%s", want, output)
+			}
+		}
+	})
 
-	if op.Path != "/users/{id}" {
-		t.Errorf("Expected Path to be '/users/{id}', got %v", op.Path)
+	runGeneratedGoClientTest(t, &spec, `package testclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponseWithoutContentType(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G'}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Suppress Content-Type sniffing by the server
+		w.Header()["Content-Type"] = nil
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`+"`"+`{"name":"Ada"}`+"`"+`))
+		case "/avatar":
+			w.Write(png)
+		case "/motd":
+			w.Write([]byte("hello"))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
 	}
+	ctx := context.Background()
 
-	if !op.HasPathParams {
-		t.Error("Expected HasPathParams to be true")
+	user, err := client.GetUser(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Ada" {
+		t.Errorf("expected JSON to be decoded per the spec, got %+v", user)
 	}
 
-	if len(op.PathParams) != 1 {
-		t.Errorf("Expected 1 path parameter, got %d", len(op.PathParams))
+	avatar, err := client.GetAvatar(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(avatar, png) {
+		t.Errorf("expected raw bytes %v, got %v", png, avatar)
 	}
 
-	if op.PathParams[0].Name != "id" {
-		t.Errorf("Expected path parameter name to be 'id', got %v", op.PathParams[0].Name)
+	motd, err := client.GetMotd(ctx)
+	if err != nil {
+		t.Fatal(err)
 	}
+	if motd != "hello" {
+		t.Errorf("expected text %q, got %q", "hello", motd)
+	}
+}
+`)
 }
 
 // Test types for alias resolution
@@ -619,6 +3730,7 @@ func TestGenerateAddToParams(t *testing.T) {
 		goName    string
 		goType    string
 		paramName string
+		explode   bool
 		expected  string
 	}{
 		{
@@ -656,13 +3768,36 @@ func TestGenerateAddToParams(t *testing.T) {
 			paramName: "data",
 			expected:  "if opts.Query.Data != nil {\n\t\tparams.Add(\"data\", fmt.Sprintf(\"%v\", opts.Query.Data))\n\t}",
 		},
+		{
+			name:      "string slice exploded",
+			goName:    "Tags",
+			goType:    "[]string",
+			paramName: "tags",
+			explode:   true,
+			expected:  "for _, v := range opts.Query.Tags {\n\t\tparams.Add(\"tags\", v)\n\t}",
+		},
+		{
+			name:      "int slice exploded",
+			goName:    "IDs",
+			goType:    "[]int",
+			paramName: "ids",
+			explode:   true,
+			expected:  "for _, v := range opts.Query.IDs {\n\t\tparams.Add(\"ids\", strconv.Itoa(v))\n\t}",
+		},
+		{
+			name:      "int slice comma-separated",
+			goName:    "IDs",
+			goType:    "[]int",
+			paramName: "ids",
+			expected:  "if len(opts.Query.IDs) > 0 {\n\t\tvalues := make([]string, len(opts.Query.IDs))\n\t\tfor i, v := range opts.Query.IDs {\n\t\t\tvalues[i] = strconv.Itoa(v)\n\t\t}\n\t\tparams.Add(\"ids\", strings.Join(values, \",\"))\n\t}",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateAddToParams(tt.goName, tt.goType, tt.paramName)
+			result := generateAddToParams("opts.Query", tt.goName, tt.goType, tt.paramName, tt.explode)
 			if result != tt.expected {
-				t.Errorf("generateAddToParams(%q, %q, %q) = %q, want %q", tt.goName, tt.goType, tt.paramName, result, tt.expected)
+				t.Errorf("generateAddToParams(%q, %q, %q, %v) = %q, want %q", tt.goName, tt.goType, tt.paramName, tt.explode, result, tt.expected)
 			}
 		})
 	}
@@ -843,7 +3978,10 @@ func TestAliasTypesInStructFields(t *testing.T) {
 		},
 	}
 
-	templateData := generateTemplateData(spec, "client")
+	templateData, err := generateTemplateData(spec, "client")
+	if err != nil {
+		t.Fatalf("generateTemplateData() error = %v", err)
+	}
 
 	if len(templateData.Operations) != 1 {
 		t.Fatalf("Expected 1 operation, got %d", len(templateData.Operations))
@@ -1,16 +1,28 @@
 // Code generated by gopenapi. DO NOT EDIT.
 package {{.PackageName}}
+{{- if .ModelsOnly}}
+{{- if .GoImports}}
 
 import (
-	"bytes"
-	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
-	"strconv"
-	"strings"
+{{- range .GoImports}}
+	"{{.}}"
+{{- end}}
+)
+{{- end}}
+{{- template "models" .}}
+{{- range .Operations}}
+{{template "operationModels" .}}
+{{- end}}
+{{- else}}
+
+import (
+{{- range .GoImports}}
+	"{{.}}"
+{{- end}}
+{{- if .Options.ModelsPackage}}
+
+	"{{.Options.ModelsPackage}}"
+{{- end}}
 )
 
 // Client represents the HTTP client for the API
@@ -18,15 +30,153 @@ type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
 	Headers    map[string]string
+
+	requestInterceptors  []func(*http.Request) error
+	responseInterceptors []func(*http.Response) error
+	baseCtx              context.Context
+	metrics              func(op string, status int, dur time.Duration)
+	noRedirects          bool
+	breaker              *circuitBreaker
+	rateLimit            *rateLimitTracker
+{{- if .HasAPIKeyAuth}}
+	// APIKey is sent with operations secured by an API key scheme
+	APIKey string
+{{- end}}
+{{- if .HasBearerAuth}}
+	// BearerToken is sent as "Authorization: Bearer <token>" with operations secured by a bearer scheme
+	BearerToken string
+{{- end}}
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the *http.Client used to send requests, e.g. to configure
+// timeouts, proxies or a test server's client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithRequestInterceptor registers a function run on every request before it is
+// sent. Interceptors run in registration order; an error aborts the request.
+func WithRequestInterceptor(interceptor func(*http.Request) error) Option {
+	return func(c *Client) {
+		c.requestInterceptors = append(c.requestInterceptors, interceptor)
+	}
+}
+
+// WithResponseInterceptor registers a function run on every response after its
+// body has been read, so the body can be read again by the interceptor.
+// Interceptors run in registration order; an error is returned to the caller.
+func WithResponseInterceptor(interceptor func(*http.Response) error) Option {
+	return func(c *Client) {
+		c.responseInterceptors = append(c.responseInterceptors, interceptor)
+	}
+}
+
+// WithBaseContext sets a context whose values are visible to every request, e.g.
+// tenant or auth information read by interceptors. Values of the per-call
+// context take precedence; its deadline and cancellation alone apply.
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *Client) {
+		c.baseCtx = ctx
+	}
+}
+
+// WithMetrics registers a function called after each request is sent with the
+// operationId, the response status code (0 when no response was received) and
+// the time taken to receive the response body, e.g. to record Prometheus metrics
+func WithMetrics(metrics func(op string, status int, dur time.Duration)) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithoutRedirects stops the client from following 3xx redirects. Responses
+// with a Location header are then returned as a *RedirectError instead of being
+// decoded, so callers can read the Location themselves.
+func WithoutRedirects() Option {
+	return func(c *Client) {
+		c.noRedirects = true
+	}
+}
+
+// CircuitBreakerOptions configures the circuit breaker of WithCircuitBreaker
+type CircuitBreakerOptions struct {
+	// Threshold is the number of consecutive failures that opens the circuit
+	Threshold int
+	// Cooldown is how long the circuit stays open before requests are sent again
+	Cooldown time.Duration
+}
+
+// WithCircuitBreaker stops sending requests once opts.Threshold consecutive
+// requests have failed, returning a *CircuitOpenError instead until
+// opts.Cooldown has elapsed. Failures are transport errors and responses that
+// are retryable (429 and 5xx); the first failure after the cooldown opens the
+// circuit again, and the first success closes it.
+func WithCircuitBreaker(opts CircuitBreakerOptions) Option {
+	return func(c *Client) {
+		c.breaker = &circuitBreaker{opts: opts}
+	}
+}
+
+// WithBaseURL overrides the base URL passed to NewClient
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.BaseURL = strings.TrimSuffix(baseURL, "/")
+	}
 }
+{{- if .HasAPIKeyAuth}}
+
+// WithAPIKey sets the API key sent with operations secured by an API key scheme
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.APIKey = key
+	}
+}
+{{- end}}
+{{- if .HasBearerAuth}}
+
+// WithBearerToken sets the token sent with operations secured by a bearer scheme
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.BearerToken = token
+	}
+}
+{{- end}}
+
+{{- if .DefaultBaseURL}}
+
+// DefaultBaseURL is the first server URL declared by the spec
+const DefaultBaseURL = {{printf "%q" .DefaultBaseURL}}
+
+// NewClient creates a new API client. An empty baseURL falls back to DefaultBaseURL.
+{{- else}}
 
-// NewClient creates a new API client
-func NewClient(baseURL string) *Client {
-	return &Client{
+// NewClient creates a new API client. The spec declares no servers, so a base URL
+// is required, given either as baseURL or with WithBaseURL.
+{{- end}}
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+{{- if .DefaultBaseURL}}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+{{- end}}
+	c := &Client{
 		BaseURL:    strings.TrimSuffix(baseURL, "/"),
 		HTTPClient: &http.Client{},
 		Headers:    make(map[string]string),
+		rateLimit:  &rateLimitTracker{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.BaseURL == "" {
+		return nil, fmt.Errorf("a base URL is required")
+	}
+	return c, nil
 }
 
 // SetHeader sets a default header for all requests
@@ -34,6 +184,40 @@ func (c *Client) SetHeader(key, value string) {
 	c.Headers[key] = value
 }
 
+// mergedContext is a per-call context that falls back to the client's base
+// context for values
+type mergedContext struct {
+	context.Context
+	base context.Context
+}
+
+func (ctx mergedContext) Value(key any) any {
+	if value := ctx.Context.Value(key); value != nil {
+		return value
+	}
+	return ctx.base.Value(key)
+}
+
+// withBaseContext adds the values of the client's base context, if any, to ctx
+func (c *Client) withBaseContext(ctx context.Context) context.Context {
+	if c.baseCtx == nil {
+		return ctx
+	}
+	return mergedContext{Context: ctx, base: c.baseCtx}
+}
+{{- range .TagClients}}
+
+// {{.Name}}Client groups the operations tagged {{printf "%q" .Tag}}
+type {{.Name}}Client struct {
+	client *Client
+}
+
+// {{.Name}} returns the client for operations tagged {{printf "%q" .Tag}}
+func (c *Client) {{.Name}}() *{{.Name}}Client {
+	return &{{.Name}}Client{client: c}
+}
+{{- end}}
+
 // Error represents an API error response
 type Error struct {
 	StatusCode int
@@ -45,77 +229,407 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
 }
 
-{{- range .Operations}}
-{{- if .HasPathParams}}
-// {{.StructName}}PathParams contains path parameters for {{.OperationId}}
-type {{.StructName}}PathParams struct {
-{{- range .PathParams}}
-	{{.GoName}} {{.GoType}} `json:"{{.Name}}"`
-{{- end}}
+// IsRetryable reports whether the request may succeed if retried: the server
+// was rate limiting (429) or failed with a 5xx status
+func (e *Error) IsRetryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
 }
-{{- end}}
 
-{{- if .HasQueryParams}}
-// {{.StructName}}QueryParams contains query parameters for {{.OperationId}}
-type {{.StructName}}QueryParams struct {
-{{- range .QueryParams}}
-	{{.GoName}} {{.GoType}} `json:"{{.Name}}"`
-{{- end}}
+// IsNotFound reports whether the resource was not found (404)
+func (e *Error) IsNotFound() bool {
+	return e.StatusCode == http.StatusNotFound
 }
-{{- end}}
 
-{{- if .HasHeaderParams}}
-// {{.StructName}}HeaderParams contains header parameters for {{.OperationId}}
-type {{.StructName}}HeaderParams struct {
-{{- range .HeaderParams}}
-	{{.GoName}} {{.GoType}} `json:"{{.Name}}"`
-{{- end}}
+// IsUnauthorized reports whether the request lacked valid credentials (401)
+func (e *Error) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
 }
-{{- end}}
 
-{{- if .HasRequestBody}}
-// {{.StructName}}RequestBody contains the request body for {{.OperationId}}
-type {{.StructName}}RequestBody struct {
-{{- range .RequestBodyFields}}
-	{{.GoName}} {{.GoType}} `json:"{{.Name}}"`
-{{- end}}
+// RedirectError is returned for 3xx responses with a Location header by clients
+// created WithoutRedirects
+type RedirectError struct {
+	StatusCode int
+	// Location is the Location header resolved against the request URL
+	Location string
+	Body     []byte
 }
-{{- end}}
 
-{{- if .HasAnyParams}}
-// {{.StructName}}Options contains all parameters for {{.OperationId}}
-type {{.StructName}}Options struct {
-{{- if .HasPathParams}}
-	Path   *{{.StructName}}PathParams   `json:"path,omitempty"`
-{{- end}}
-{{- if .HasQueryParams}}
-	Query  *{{.StructName}}QueryParams  `json:"query,omitempty"`
-{{- end}}
-{{- if .HasHeaderParams}}
-	Headers *{{.StructName}}HeaderParams `json:"headers,omitempty"`
-{{- end}}
-{{- if .HasRequestBody}}
-	Body   *{{.StructName}}RequestBody   `json:"body,omitempty"`
-{{- end}}
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("API redirect %d to %s", e.StatusCode, e.Location)
 }
-{{- end}}
 
-{{- if and .HasResponseBody (gt (len .ResponseFields) 0)}}
-// {{.StructName}}Response represents the response from {{.OperationId}}
-type {{.StructName}}Response struct {
-{{- range .ResponseFields}}
-	{{.GoName}} {{.GoType}} `json:"{{.Name}}"`
+// CircuitOpenError is returned without sending the request while the circuit
+// breaker of WithCircuitBreaker is open
+type CircuitOpenError struct {
+	// Until is when the circuit lets requests through again
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open until %s", e.Until.Format(time.RFC3339))
+}
+
+// circuitBreaker counts consecutive failed requests
+type circuitBreaker struct {
+	opts      CircuitBreakerOptions
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow returns a *CircuitOpenError while the circuit is open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if time.Now().Before(b.openUntil) {
+		return &CircuitOpenError{Until: b.openUntil}
+	}
+	return nil
+}
+
+// record counts the outcome of a request, opening the circuit at the threshold
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.opts.Threshold {
+		b.openUntil = time.Now().Add(b.opts.Cooldown)
+	}
+}
+
+// RateLimitState is the rate limit reported by the X-RateLimit-* headers of the
+// latest response that carried them
+type RateLimitState struct {
+	// Limit is the number of requests allowed per window, 0 when not reported
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends, zero when not reported
+	Reset time.Time
+	// UpdatedAt is when the state was last updated, zero until a response
+	// carries rate limit headers
+	UpdatedAt time.Time
+}
+
+// RateLimit returns the rate limit state of the latest response carrying
+// X-RateLimit-Remaining or X-RateLimit-Reset headers
+func (c *Client) RateLimit() RateLimitState {
+	if c.rateLimit == nil {
+		return RateLimitState{}
+	}
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+	return c.rateLimit.state
+}
+
+// rateLimitTracker holds the rate limit state of the latest response
+type rateLimitTracker struct {
+	mu    sync.Mutex
+	state RateLimitState
+}
+
+// update records the rate limit headers of header, if any. X-RateLimit-Reset
+// may be a Unix timestamp or a number of seconds from now.
+func (t *rateLimitTracker) update(header http.Header) {
+	if t == nil {
+		return
+	}
+	remaining, hasRemaining := headerInt(header, "X-RateLimit-Remaining")
+	reset, hasReset := headerInt(header, "X-RateLimit-Reset")
+	if !hasRemaining && !hasReset {
+		return
+	}
+	now := time.Now()
+	state := RateLimitState{Remaining: remaining, UpdatedAt: now}
+	state.Limit, _ = headerInt(header, "X-RateLimit-Limit")
+	if hasReset {
+		// Timestamps are far larger than any window length in seconds
+		if reset > 1_000_000_000 {
+			state.Reset = time.Unix(int64(reset), 0)
+		} else {
+			state.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state = state
+}
+
+// headerInt returns the integer value of the header name
+func headerInt(header http.Header, name string) (int, bool) {
+	value, err := strconv.Atoi(strings.TrimSpace(header.Get(name)))
+	return value, err == nil
+}
+
+// operationIDKey is the context key of the operationId a request was built for
+type operationIDKey struct{}
+
+// observe reports a request sent at start to the metrics function, if any
+func (c *Client) observe(req *http.Request, status int, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+	op, _ := req.Context().Value(operationIDKey{}).(string)
+	c.metrics(op, status, time.Since(start))
+}
+
+// do executes the request and reads the response body. Responses with a status
+// code of 400 or above are returned as an *Error, and redirects not followed
+// because of WithoutRedirects as a *RedirectError.
+func (c *Client) do(req *http.Request) (*http.Response, []byte, error) {
+	resp, start, err := c.send(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.read(req, resp, start)
+}
+
+// stream executes the request and passes a successful response to consume
+// without reading its body first, so that long responses are not buffered.
+// Response interceptors are not run for streamed responses; other responses
+// are read and returned as by do.
+func (c *Client) stream(req *http.Request, consume func(*http.Response) error) error {
+	resp, start, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		if _, _, err := c.read(req, resp, start); err != nil {
+			return err
+		}
+		return fmt.Errorf("unexpected status %d for a streamed response", resp.StatusCode)
+	}
+	defer resp.Body.Close()
+	err = consume(resp)
+	c.observe(req, resp.StatusCode, start)
+	return err
+}
+
+// send runs the request interceptors and executes the request, returning the
+// response with its body unread and the time it was sent
+func (c *Client) send(req *http.Request) (*http.Response, time.Time, error) {
+	for _, interceptor := range c.requestInterceptors {
+		if err := interceptor(req); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, time.Time{}, fmt.Errorf("request interceptor: %w", err)
+		}
+	}
+
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, time.Time{}, err
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if c.noRedirects {
+		// Copied so that a client passed to WithHTTPClient is left unchanged
+		noRedirectClient := *httpClient
+		noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		httpClient = &noRedirectClient
+	}
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if c.breaker != nil {
+		c.breaker.record(err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500)
+	}
+	if err != nil {
+		c.observe(req, 0, start)
+		return nil, start, fmt.Errorf("failed to execute request: %w", err)
+	}
+	c.rateLimit.update(resp.Header)
+	return resp, start, nil
+}
+
+// read reads the body of a response to req sent at start and runs the response
+// interceptors, returning error statuses and unfollowed redirects as errors
+func (c *Client) read(req *http.Request, resp *http.Response, start time.Time) (*http.Response, []byte, error) {
+	// Read response body
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.observe(req, resp.StatusCode, start)
+	if err != nil {
+		return resp, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	for _, interceptor := range c.responseInterceptors {
+		if err := interceptor(resp); err != nil {
+			return resp, respBody, fmt.Errorf("response interceptor: %w", err)
+		}
+	}
+
+	// Check for error status codes
+	if resp.StatusCode >= 400 {
+		return resp, respBody, &Error{
+			StatusCode: resp.StatusCode,
+			Message:    string(respBody),
+			Body:       respBody,
+		}
+	}
+	if c.noRedirects && resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != "" {
+		location := resp.Header.Get("Location")
+		if u, err := resp.Location(); err == nil {
+			location = u.String()
+		}
+		return resp, respBody, &RedirectError{
+			StatusCode: resp.StatusCode,
+			Location:   location,
+			Body:       respBody,
+		}
+	}
+
+	return resp, respBody, nil
+}
+
+{{- if .HasMultipartResponses}}
+
+// readMultipart calls onPart with each part of a multipart response body as it
+// is received, stopping at the first error returned by onPart
+func readMultipart(resp *http.Response, onPart func(*multipart.Part) error) error {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("invalid multipart content type: %w", err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return fmt.Errorf("expected a multipart response, got %s", mediaType)
+	}
+
+	reader := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read multipart response: %w", err)
+		}
+		err = onPart(part)
+		part.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
 {{- end}}
+{{- if .HasPaginatedOperations}}
+
+// PageIterator iterates over the pages of a paginated operation by following
+// RFC 5988 Link headers with rel="next". Pagination is detected from the
+// headers alone, so it works regardless of the shape of the response body.
+type PageIterator[T any] struct {
+	client *Client
+	req    *http.Request
+	decode func(*http.Response, []byte) (T, error)
+	page   T
+	err    error
+}
+
+// Next fetches the next page and reports whether one was read. It returns false
+// once the last page has been consumed or an error occurred; check Err afterwards.
+func (it *PageIterator[T]) Next() bool {
+	if it.req == nil || it.err != nil {
+		return false
+	}
+	req := it.req
+	it.req = nil
+
+	resp, respBody, err := it.client.do(req)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	page, err := it.decode(resp, respBody)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page = page
+
+	if next := nextLink(resp.Header); next != "" {
+		base := req.URL
+		if resp.Request != nil && resp.Request.URL != nil {
+			base = resp.Request.URL
+		}
+		nextURL, err := base.Parse(next)
+		if err != nil {
+			it.err = fmt.Errorf("invalid next page link %q: %w", next, err)
+			return true
+		}
+		it.req = req.Clone(req.Context())
+		it.req.URL = nextURL
+		it.req.Host = ""
+	}
+
+	return true
 }
+
+// Page returns the page read by the most recent call to Next
+func (it *PageIterator[T]) Page() T {
+	return it.page
+}
+
+// Err returns the first error encountered while iterating, if any
+func (it *PageIterator[T]) Err() error {
+	return it.err
+}
+
+// nextLink returns the target of the rel="next" entry of the Link headers, or
+// an empty string when there is none
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range parts[1:] {
+				key, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(val), `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+{{- end}}
+{{- if not .Options.SplitModels}}
+{{- template "models" .}}
 {{- end}}
 
-// {{.OperationId}} {{.Description}}
-func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts *{{.StructName}}Options{{- end}}) ({{- if and .HasResponseBody (gt (len .ResponseFields) 0)}}*{{.StructName}}Response{{- else if .ResponseType}}{{.ResponseType}}{{- else}}interface{}{{- end}}, error) {
-{{- if .HasAnyParams}}
+{{- range .Operations}}
+{{- if not $.Options.SplitModels}}
+{{template "operationModels" .}}
+{{- end}}
+
+// new{{.StructName}}Request builds the HTTP request for {{.OperationId}}
+func (c *Client) new{{.StructName}}Request(ctx context.Context{{- if .QueryStruct}}, query *{{.ModelsQualifier}}{{.QueryType}}{{- end}}{{- if .HasOptions}}, opts *{{.ModelsQualifier}}{{.StructName}}Options{{- end}}) (*http.Request, error) {
+{{- if .HasOptions}}
 	if opts == nil {
-		opts = &{{.StructName}}Options{}
+		opts = &{{.ModelsQualifier}}{{.StructName}}Options{}
 	}
 {{- end}}
+	ctx = context.WithValue(c.withBaseContext(ctx), operationIDKey{}, "{{.OperationId}}")
 
 	// Build URL path
 	path := "{{.Path}}"
@@ -129,7 +643,13 @@ func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts
 
 	// Build query parameters
 	params := url.Values{}
-{{- if .HasQueryParams}}
+{{- if .QueryStruct}}
+	if query != nil {
+{{- range .QueryParams}}
+		{{.AddToParams}}
+{{- end}}
+	}
+{{- else if .HasQueryParams}}
 	if opts.Query != nil {
 {{- range .QueryParams}}
 		{{.AddToParams}}
@@ -145,16 +665,14 @@ func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts
 
 	// Prepare request body
 	var body io.Reader
-{{- if .HasRequestBody}}
+{{- if eq .RequestMediaType "multipart/form-data"}}
+	// Multipart bodies are streamed once the request has been created
+	var writer *multipart.Writer
+{{- else if .HasRequestBody}}
 	if opts.Body != nil {
 		jsonBody, err := json.Marshal(opts.Body)
 		if err != nil {
-{{- if .ResponseType}}
-			var zero {{.ResponseType}}
-			return zero, fmt.Errorf("failed to marshal request body: %w", err)
-{{- else}}
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-{{- end}}
 		}
 		body = bytes.NewReader(jsonBody)
 	}
@@ -163,15 +681,48 @@ func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "{{.Method}}", fullURL, body)
 	if err != nil {
-{{- if .ResponseType}}
-		var zero {{.ResponseType}}
-		return zero, fmt.Errorf("failed to create request: %w", err)
-{{- else}}
 		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+{{- if eq .RequestMediaType "multipart/form-data"}}
+	if opts.Body != nil {
+		// Write the form through a pipe so file parts are not buffered in memory.
+		// The transport closes the pipe when the request ends, stopping the writer.
+		pr, pw := io.Pipe()
+		writer = multipart.NewWriter(pw)
+		go func(body *{{.ModelsQualifier}}{{.StructName}}RequestBody) {
+			pw.CloseWithError(write{{.StructName}}Multipart(writer, body))
+		}(opts.Body)
+		req.Body = pr
+	}
 {{- end}}
+
+{{- if .Auth}}
+	// Apply authentication. Headers are applied from lowest to highest precedence:
+	// authentication, then client default headers, then per-call header parameters.
+{{- range .Auth}}
+{{- if eq .Kind "bearer"}}
+	if c.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.BearerToken)
 	}
+{{- else if eq .In "query"}}
+	if c.APIKey != "" {
+		query := req.URL.Query()
+		query.Set("{{.Name}}", c.APIKey)
+		req.URL.RawQuery = query.Encode()
+	}
+{{- else if eq .In "cookie"}}
+	if c.APIKey != "" {
+		req.AddCookie(&http.Cookie{Name: "{{.Name}}", Value: c.APIKey})
+	}
+{{- else}}
+	if c.APIKey != "" {
+		req.Header.Set("{{.Name}}", c.APIKey)
+	}
+{{- end}}
+{{- end}}
+{{- end}}
 
-	// Set default headers
+	// Set default headers, overriding authentication
 	for key, value := range c.Headers {
 		req.Header.Set(key, value)
 	}
@@ -179,11 +730,15 @@ func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts
 {{- if .HasRequestBody}}
 	// Set content type for request body
 	if opts.Body != nil {
+{{- if eq .RequestMediaType "multipart/form-data"}}
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+{{- else}}
 		req.Header.Set("Content-Type", "application/json")
+{{- end}}
 	}
 {{- end}}
 
-	// Set custom headers
+	// Set per-call headers, overriding default headers and authentication
 {{- if .HasHeaderParams}}
 	if opts.Headers != nil {
 {{- range .HeaderParams}}
@@ -192,72 +747,307 @@ func (c *Client) {{.MethodName}}(ctx context.Context{{- if .HasAnyParams}}, opts
 	}
 {{- end}}
 
-	// Execute request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-{{- if .ResponseType}}
-		var zero {{.ResponseType}}
-		return zero, fmt.Errorf("failed to execute request: %w", err)
-{{- else}}
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-{{- end}}
-	}
-	defer resp.Body.Close()
+	return req, nil
+}
+{{- if eq .RequestMediaType "multipart/form-data"}}
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-{{- if .ResponseType}}
-		var zero {{.ResponseType}}
-		return zero, fmt.Errorf("failed to read response body: %w", err)
-{{- else}}
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// write{{.StructName}}Multipart writes the fields of b as a multipart/form-data body
+func write{{.StructName}}Multipart(writer *multipart.Writer, b *{{.ModelsQualifier}}{{.StructName}}RequestBody) error {
+{{- range .RequestBodyFields}}
+	{{.WriteMultipart}}
 {{- end}}
-	}
-
-	// Check for error status codes
-	if resp.StatusCode >= 400 {
-{{- if .ResponseType}}
-		var zero {{.ResponseType}}
-		return zero, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(respBody),
-			Body:       respBody,
-		}
-{{- else}}
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(respBody),
-			Body:       respBody,
-		}
+	return writer.Close()
+}
 {{- end}}
-	}
 
+{{- if not .NoContent}}
+
+// decode{{.StructName}}Response parses the response body of {{.OperationId}}
+func decode{{.StructName}}Response(resp *http.Response, respBody []byte) ({{template "returnType" .}}, error) {
 {{- if and .HasResponseBody (gt (len .ResponseFields) 0)}}
 	// Parse response
-	var result {{.StructName}}Response
+	var result {{.ModelsQualifier}}{{.StructName}}Response
 	if len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, &result); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 		}
 	}
+{{- if .HeaderGetters}}
+	result.Header = resp.Header
+{{- end}}
 	return &result, nil
+{{- else if eq .ResponseFormat "binary"}}
+	// Return binary responses as raw bytes
+	return respBody, nil
+{{- else if and (eq .ResponseFormat "text") (eq .ResponseType "string")}}
+	// Return text responses as-is
+	return string(respBody), nil
 {{- else if .ResponseType}}
 	// Parse simple type response
+	var result {{.GoResponseType}}
 	if len(respBody) > 0 {
-		var result {{.ResponseType}}
 		if err := json.Unmarshal(respBody, &result); err != nil {
-			var zero {{.ResponseType}}
+			var zero {{.GoResponseType}}
 			return zero, fmt.Errorf("failed to unmarshal response: %w", err)
 		}
-		return result, nil
 	}
-	var zero {{.ResponseType}}
-	return zero, nil
+	return result, nil
 {{- else}}
 	// Return raw response for non-JSON responses
 	return string(respBody), nil
 {{- end}}
 }
+{{- end}}
+
+// {{.OperationId}} {{.Description}}
+{{- if .ResponseHeaders}}
+//
+// The response declares the following headers:
+{{- range .ResponseHeaders}}
+//   - {{.Name}} ({{.GoType}})
+{{- end}}
+{{- end}}
+{{- if and .Timeout .NoContext}}
+//
+// The request times out after {{.Timeout}}.
+{{- else if .Timeout}}
+//
+// Unless ctx already has a deadline, the request times out after {{.Timeout}}.
+{{- end}}
+func (c *{{template "receiver" .}}) {{.MethodName}}({{template "methodParams" .}}) {{if .NoContent}}error{{else}}({{template "returnType" .}}, error){{end}} {
+{{- if .NoContext}}
+	ctx := context.Background()
+{{- end}}
+{{- if .Timeout}}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, {{.Timeout}})
+		defer cancel()
+	}
+{{end}}
+	req, err := {{template "clientRef" .}}.new{{.StructName}}Request({{template "requestArgs" .}})
+{{- if .NoContent}}
+	if err != nil {
+		return err
+	}
+
+	// The response declares no content, so its body is not decoded
+	_, _, err = {{template "clientRef" .}}.do(req)
+	return err
+}
+{{- else}}
+	if err != nil {
+		var zero {{template "returnType" .}}
+		return zero, err
+	}
+
+	resp, respBody, err := {{template "clientRef" .}}.do(req)
+	if err != nil {
+		var zero {{template "returnType" .}}
+		return zero, err
+	}
+
+	return decode{{.StructName}}Response(resp, respBody)
+}
+{{- end}}
+{{- if .MultipartResponse}}
 
-{{- end}} 
\ No newline at end of file
+// {{.MethodName}}Parts streams the multipart response of {{.OperationId}}, calling onPart
+// with each part as it is received. A part's body can only be read until onPart returns.
+func (c *{{template "receiver" .}}) {{.MethodName}}Parts({{template "methodParams" .}}{{if or (not .NoContext) .QueryStruct .HasOptions}}, {{end}}onPart func(*multipart.Part) error) error {
+{{- if .NoContext}}
+	ctx := context.Background()
+{{- end}}
+{{- if .Timeout}}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, {{.Timeout}})
+		defer cancel()
+	}
+{{end}}
+	req, err := {{template "clientRef" .}}.new{{.StructName}}Request({{template "requestArgs" .}})
+	if err != nil {
+		return err
+	}
+	return {{template "clientRef" .}}.stream(req, func(resp *http.Response) error {
+		return readMultipart(resp, onPart)
+	})
+}
+{{- end}}
+{{- if .Paginated}}
+
+// {{.MethodName}}Pages returns an iterator over the pages of {{.OperationId}}, following
+// the rel="next" Link header of each response until it is absent
+func (c *{{template "receiver" .}}) {{.MethodName}}Pages({{template "methodParams" .}}) *PageIterator[{{template "returnType" .}}] {
+{{- if .NoContext}}
+	ctx := context.Background()
+{{- end}}
+	req, err := {{template "clientRef" .}}.new{{.StructName}}Request({{template "requestArgs" .}})
+	return &PageIterator[{{template "returnType" .}}]{
+		client: {{template "clientRef" .}},
+		req:    req,
+		decode: decode{{.StructName}}Response,
+		err:    err,
+	}
+}
+{{- end}}
+
+{{- end}}
+
+{{- end}}
+
+{{- define "models"}}
+{{- range .Models}}
+
+// {{.Name}} is a model of the array and map bodies of operations
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}{{if .OmitEmpty}},omitempty{{end}}"{{if and $.Options.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+{{- end}}
+
+{{- define "operationModels"}}
+{{- range .NestedStructs}}
+// {{.Name}} is a nested object of {{$.OperationId}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}{{if .OmitEmpty}},omitempty{{end}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+
+{{- if .HasPathParams}}
+// {{.StructName}}PathParams contains path parameters for {{.OperationId}}
+type {{.StructName}}PathParams struct {
+{{- range .PathParams}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+
+{{- if .HasQueryParams}}
+// {{.QueryType}} contains query parameters for {{.OperationId}}
+type {{.QueryType}} struct {
+{{- range .QueryParams}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+
+{{- if .HasHeaderParams}}
+// {{.StructName}}HeaderParams contains header parameters for {{.OperationId}}
+type {{.StructName}}HeaderParams struct {
+{{- range .HeaderParams}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+
+{{- if .HasRequestBody}}
+// {{.StructName}}RequestBody contains the request body for {{.OperationId}}
+{{- if .RequestBodyType}}
+type {{.StructName}}RequestBody {{.RequestBodyType}}
+{{- else}}
+type {{.StructName}}RequestBody struct {
+{{- range .RequestBodyFields}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}{{if .OmitEmpty}},omitempty{{end}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+}
+{{- end}}
+{{- end}}
+
+{{- if .HasOptions}}
+// {{.StructName}}Options contains all parameters for {{.OperationId}}{{if .QueryStruct}} except the query, passed as a {{.QueryType}}{{end}}
+type {{.StructName}}Options struct {
+{{- if .HasPathParams}}
+	Path   *{{.StructName}}PathParams   `json:"path,omitempty"`
+{{- end}}
+{{- if and .HasQueryParams (not .QueryStruct)}}
+	Query  *{{.QueryType}}  `json:"query,omitempty"`
+{{- end}}
+{{- if .HasHeaderParams}}
+	Headers *{{.StructName}}HeaderParams `json:"headers,omitempty"`
+{{- end}}
+{{- if .HasRequestBody}}
+	Body   *{{.StructName}}RequestBody   `json:"body,omitempty"`
+{{- end}}
+}
+{{- end}}
+
+{{- if and .HasQueryDefaults .QueryStruct}}
+
+// Default{{.QueryType}} returns a query for {{.OperationId}} with its
+// parameters set to their declared defaults
+func Default{{.QueryType}}() *{{.QueryType}} {
+	return &{{.QueryType}}{
+{{- range .QueryParams}}
+{{- if .Default}}
+		{{.GoName}}: {{.Default}},
+{{- end}}
+{{- end}}
+	}
+}
+{{- else if .HasQueryDefaults}}
+
+// Default{{.StructName}}Options returns options for {{.OperationId}} with query
+// parameters set to their declared defaults
+func Default{{.StructName}}Options() *{{.StructName}}Options {
+	return &{{.StructName}}Options{
+		Query: &{{.QueryType}}{
+{{- range .QueryParams}}
+{{- if .Default}}
+			{{.GoName}}: {{.Default}},
+{{- end}}
+{{- end}}
+		},
+	}
+}
+{{- end}}
+
+{{- if and .HasResponseBody (gt (len .ResponseFields) 0)}}
+// {{.StructName}}Response represents the response from {{.OperationId}}
+type {{.StructName}}Response struct {
+{{- range .ResponseFields}}
+	{{.GoName}} {{.GoType}} `json:"{{.Name}}{{if .OmitEmpty}},omitempty{{end}}"{{if and $.ValidatorTags .Validate}} validate:"{{.Validate}}"{{end}}`
+{{- end}}
+{{- if .HeaderGetters}}
+
+	// Header holds the headers of the HTTP response
+	Header http.Header `json:"-"`
+{{- end}}
+}
+{{- range .HeaderGetters}}
+
+// {{.GoName}} returns the {{.Name}} response header as {{.GoType}},
+// or the zero value when it is absent or malformed
+func (r *{{$.StructName}}Response) {{.GoName}}() {{.GoType}} {
+	{{.ParseHeader}}
+}
+{{- end}}
+{{- end}}
+{{- end}}
+
+{{- define "methodParams"}}
+{{- if not .NoContext}}ctx context.Context{{if or .QueryStruct .HasOptions}}, {{end}}{{end}}
+{{- if .QueryStruct}}query *{{.ModelsQualifier}}{{.QueryType}}{{if .HasOptions}}, {{end}}{{end}}
+{{- if .HasOptions}}opts *{{.ModelsQualifier}}{{.StructName}}Options{{end}}
+{{- end}}
+
+{{- define "requestArgs"}}ctx{{if .QueryStruct}}, query{{end}}{{if .HasOptions}}, opts{{end}}{{end}}
+
+{{- define "receiver"}}
+{{- if .TagClient}}{{.TagClient}}Client{{else}}Client{{end}}
+{{- end}}
+
+{{- define "clientRef"}}
+{{- if .TagClient}}c.client{{else}}c{{end}}
+{{- end}}
+
+{{- define "returnType"}}
+{{- if and .HasResponseBody (gt (len .ResponseFields) 0)}}*{{.ModelsQualifier}}{{.StructName}}Response
+{{- else if .ResponseType}}{{.GoResponseType}}
+{{- else}}interface{}
+{{- end}}
+{{- end}}
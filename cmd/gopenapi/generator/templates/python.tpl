@@ -1,8 +1,14 @@
 # Code generated by gopenapi. DO NOT EDIT.
+from __future__ import annotations
+
 import json
+{{- if .Options.PythonAsync}}
+import aiohttp
+{{- else}}
 import requests
-from dataclasses import dataclass, asdict
-from typing import Optional, Dict, Any, Union
+{{- end}}
+from dataclasses import dataclass
+from typing import Optional, Dict, List, Any, Union
 from urllib.parse import urljoin
 
 
@@ -16,7 +22,44 @@ class APIError(Exception):
         super().__init__(f"API error {status_code}: {message}")
 
 
+def _to_json(value: Any) -> Any:
+    """Convert models, and lists and dicts of them, to JSON-compatible values"""
+    if hasattr(value, "to_dict"):
+        return value.to_dict()
+    if isinstance(value, list):
+        return [_to_json(item) for item in value]
+    if isinstance(value, dict):
+        return {key: _to_json(item) for key, item in value.items()}
+    return value
+
+
 {{- range .Operations}}
+{{- $op := .}}
+{{- range .NestedStructs}}
+@dataclass
+class {{.Name}}:
+    """Nested object of {{$op.OperationId}}"""
+{{- range .Fields}}
+    {{.Name | snake_case}}: {{.GoType | python_optional_type}} = None
+{{- end}}
+    
+    def to_dict(self) -> Dict[str, Any]:
+        """Convert to dictionary for JSON serialization"""
+        return {
+{{- range .Fields}}
+            "{{.Name}}": _to_json(self.{{.Name | snake_case}}),
+{{- end}}
+        }
+    
+    @classmethod
+    def from_dict(cls, data: Dict[str, Any]) -> "{{.Name}}":
+        """Create instance from dictionary"""
+        return cls(
+{{- range .Fields}}
+            {{.Name | snake_case}}={{python_from_json .Name .GoType}},
+{{- end}}
+        )
+{{- end}}
 {{- if .HasPathParams}}
 @dataclass
 class {{.StructName}}PathParams:
@@ -31,7 +74,7 @@ class {{.StructName}}PathParams:
 class {{.StructName}}QueryParams:
     """Query parameters for {{.OperationId}}"""
 {{- range .QueryParams}}
-    {{.Name | snake_case}}: Optional[{{.GoType | python_type}}] = None
+    {{.Name | snake_case}}: {{.GoType | python_optional_type}} = None
 {{- end}}
     
     def to_dict(self) -> Dict[str, str]:
@@ -49,7 +92,7 @@ class {{.StructName}}QueryParams:
 class {{.StructName}}HeaderParams:
     """Header parameters for {{.OperationId}}"""
 {{- range .HeaderParams}}
-    {{.Name | snake_case}}: Optional[{{.GoType | python_type}}] = None
+    {{.Name | snake_case}}: {{.GoType | python_optional_type}} = None
 {{- end}}
     
     def to_dict(self) -> Dict[str, str]:
@@ -72,11 +115,10 @@ class {{.StructName}}RequestBody:
     
     def to_dict(self) -> Dict[str, Any]:
         """Convert to dictionary for JSON serialization"""
-        data = asdict(self)
         # Convert snake_case field names back to original JSON field names
         return {
 {{- range .RequestBodyFields}}
-            "{{.Name}}": data["{{.Name | snake_case}}"],
+            "{{.Name}}": _to_json(self.{{.Name | snake_case}}),
 {{- end}}
         }
 {{- end}}
@@ -86,7 +128,7 @@ class {{.StructName}}RequestBody:
 class {{.StructName}}Response:
     """Response from {{.OperationId}}"""
 {{- range .ResponseFields}}
-    {{.Name | snake_case}}: Optional[{{.GoType | python_type}}] = None
+    {{.Name | snake_case}}: {{.GoType | python_optional_type}} = None
 {{- end}}
     
     @classmethod
@@ -94,7 +136,7 @@ class {{.StructName}}Response:
         """Create instance from dictionary"""
         return cls(
 {{- range .ResponseFields}}
-            {{.Name | snake_case}}=data.get("{{.Name}}"),
+            {{.Name | snake_case}}={{python_from_json .Name .GoType}},
 {{- end}}
         )
 {{- end}}
@@ -102,6 +144,88 @@ class {{.StructName}}Response:
 {{- end}}
 
 
+{{- if .Options.PythonAsync}}
+
+
+class {{.ClientName}}AsyncClient:
+    """Asynchronous HTTP client for the API, built on aiohttp"""
+    
+    def __init__(self, base_url: str, session: Optional[aiohttp.ClientSession] = None):
+        self.base_url = base_url.rstrip('/')
+        self.session = session
+        self._owns_session = session is None
+        self.default_headers = {}
+    
+    async def __aenter__(self) -> "{{.ClientName}}AsyncClient":
+        return self
+    
+    async def __aexit__(self, *exc_info):
+        await self.close()
+    
+    async def close(self):
+        """Close the session if it was created by the client"""
+        if self.session is not None and self._owns_session:
+            await self.session.close()
+            self.session = None
+    
+    def set_header(self, key: str, value: str):
+        """Set a default header for all requests"""
+        self.default_headers[key] = value
+    
+    async def _make_request(self, method: str, path: str, params: Dict[str, Any] = None, 
+                            headers: Dict[str, str] = None, json_data: Any = None) -> bytes:
+        """Make an HTTP request and return the response body"""
+        url = urljoin(self.base_url, path)
+        
+        # Merge headers
+        request_headers = self.default_headers.copy()
+        if headers:
+            request_headers.update(headers)
+        
+        if self.session is None:
+            self.session = aiohttp.ClientSession()
+        
+        async with self.session.request(
+            method=method,
+            url=url,
+            params=params,
+            headers=request_headers,
+            json=json_data
+        ) as response:
+            content = await response.read()
+            if response.status >= 400:
+                raise APIError(
+                    status_code=response.status,
+                    message=content.decode(errors="replace"),
+                    response_body=content
+                )
+            return content
+
+{{- range .Operations}}
+    async def {{.OperationId | snake_case}}({{template "pythonArgs" .}}:
+        """{{.Description}}"""
+        {{template "pythonBuildRequest" .}}
+        
+        content = await self._make_request(
+            method="{{.Method}}",
+            path=path_str,
+            params=params if params else None,
+            headers=request_headers if request_headers else None,
+            json_data=json_data
+        )
+        
+{{- if .HasResponseBody}}
+        if content:
+            return {{.StructName}}Response.from_dict(json.loads(content))
+        return {{.StructName}}Response.from_dict({})
+{{- else}}
+        return content.decode()
+{{- end}}
+
+{{- end}}
+{{- else}}
+
+
 class {{.ClientName}}Client:
     """HTTP client for the API"""
     
@@ -142,9 +266,33 @@ class {{.ClientName}}Client:
         return response
 
 {{- range .Operations}}
-    def {{.OperationId | snake_case}}(self{{- if .HasPathParams}}, path: {{.StructName}}PathParams{{- end}}{{- if .HasQueryParams}}, query: Optional[{{.StructName}}QueryParams] = None{{- end}}{{- if .HasHeaderParams}}, headers: Optional[{{.StructName}}HeaderParams] = None{{- end}}{{- if .HasRequestBody}}, body: Optional[{{.StructName}}RequestBody] = None{{- end}}) -> {{- if .HasResponseBody}}{{.StructName}}Response{{- else}}str{{- end}}:
+    def {{.OperationId | snake_case}}({{template "pythonArgs" .}}:
         """{{.Description}}"""
+        {{template "pythonBuildRequest" .}}
         
+        response = self._make_request(
+            method="{{.Method}}",
+            path=path_str,
+            params=params if params else None,
+            headers=request_headers if request_headers else None,
+            json_data=json_data
+        )
+        
+{{- if .HasResponseBody}}
+        if response.content:
+            return {{.StructName}}Response.from_dict(response.json())
+        return {{.StructName}}Response.from_dict({})
+{{- else}}
+        return response.text
+{{- end}}
+
+{{- end}}
+{{- end}}
+
+{{- define "pythonArgs"}}self{{- if .HasPathParams}}, path: {{.StructName}}PathParams{{- end}}{{- if .HasQueryParams}}, query: Optional[{{.StructName}}QueryParams] = None{{- end}}{{- if .HasHeaderParams}}, headers: Optional[{{.StructName}}HeaderParams] = None{{- end}}{{- if .HasRequestBody}}, body: Optional[{{.StructName}}RequestBody] = None{{- end}}) -> {{- if .HasResponseBody}}{{.StructName}}Response{{- else}}str{{- end}}
+{{- end}}
+
+{{- define "pythonBuildRequest"}}
         # Build path
         path_str = "{{.Path}}"
 {{- if .HasPathParams}}
@@ -174,20 +322,4 @@ class {{.ClientName}}Client:
             json_data = body.to_dict()
 {{- end}}
         
-        response = self._make_request(
-            method="{{.Method}}",
-            path=path_str,
-            params=params if params else None,
-            headers=request_headers if request_headers else None,
-            json_data=json_data
-        )
-        
-{{- if .HasResponseBody}}
-        if response.content:
-            return {{.StructName}}Response.from_dict(response.json())
-        return {{.StructName}}Response.from_dict({})
-{{- else}}
-        return response.text
-{{- end}}
-
-{{- end}} 
\ No newline at end of file
+{{- end}}
\ No newline at end of file
@@ -1,10 +1,41 @@
 // Code generated by gopenapi. DO NOT EDIT.
 
+{{- range .Models }}
+/** {{ .Name }} is a model of the array and map bodies of operations */
+export interface {{ .Name }} {
+  {{- range .Fields }}
+  {{ .Name }}{{ if .OmitEmpty }}?{{ end }}: {{ .GoType | typescript_type }};
+  {{- end }}
+}
+{{- end }}
+
 {{- range .Operations }}
+{{- range .Enums }}
+{{- if eq $.Options.TypeScriptEnumStyle "enum" }}
+export enum {{ .TypeName }} {
+  {{- range .Values }}
+  {{ .Name }} = {{ .Literal }},
+  {{- end }}
+}
+{{- else }}
+export type {{ .TypeName }} = {{ range $i, $value := .Values }}{{ if $i }} | {{ end }}{{ $value.Literal }}{{ end }};
+{{- end }}
+{{- end }}
+
+{{- $op := . }}
+{{- range .NestedStructs }}
+/** {{ .Name }} is a nested object of {{ $op.OperationId }} */
+export interface {{ .Name }} {
+  {{- range .Fields }}
+  {{ .Name }}{{ if .OmitEmpty }}?{{ end }}: {{ .GoType | typescript_type }};
+  {{- end }}
+}
+{{- end }}
+
 {{- if .HasPathParams }}
 export interface {{ .StructName }}PathParams {
   {{- range .PathParams }}
-  {{ .Name }}: {{ .GoType | typescript_type }};
+  {{ .Name }}: {{ if .EnumType }}{{ .EnumType }}{{ else }}{{ .GoType | typescript_type }}{{ end }};
   {{- end }}
 }
 {{- end }}
@@ -12,7 +43,7 @@ export interface {{ .StructName }}PathParams {
 {{- if .HasQueryParams }}
 export interface {{ .StructName }}QueryParams {
   {{- range .QueryParams }}
-  {{ .Name }}?: {{ .GoType | typescript_type }};
+  {{ .Name }}?: {{ if .EnumType }}{{ .EnumType }}{{ else }}{{ .GoType | typescript_type }}{{ end }};
   {{- end }}
 }
 {{- end }}
@@ -20,23 +51,27 @@ export interface {{ .StructName }}QueryParams {
 {{- if .HasHeaderParams }}
 export interface {{ .StructName }}HeaderParams {
   {{- range .HeaderParams }}
-  {{ .Name }}?: {{ .GoType | typescript_type }};
+  {{ .Name }}?: {{ if .EnumType }}{{ .EnumType }}{{ else }}{{ .GoType | typescript_type }}{{ end }};
   {{- end }}
 }
 {{- end }}
 
 {{- if .HasRequestBody }}
+{{- if .RequestBodyType }}
+export type {{ .StructName }}RequestBody = {{ .RequestBodyType | typescript_type }};
+{{- else }}
 export interface {{ .StructName }}RequestBody {
   {{- range .RequestBodyFields }}
-  {{ .Name }}: {{ .GoType | typescript_type }};
+  {{ .Name }}{{ if .OmitEmpty }}?{{ end }}: {{ .GoType | typescript_type }};
   {{- end }}
 }
 {{- end }}
+{{- end }}
 
 {{- if and .HasResponseBody (gt (len .ResponseFields) 0) }}
 export interface {{ .StructName }}Response {
   {{- range .ResponseFields }}
-  {{ .Name }}: {{ .GoType | typescript_type }};
+  {{ .Name }}{{ if .OmitEmpty }}?{{ end }}: {{ .GoType | typescript_type }};
   {{- end }}
 }
 {{- end }}
@@ -49,6 +84,9 @@ export interface ApiClientConfig {
   timeout?: number;
 }
 
+/** How a response body is decoded when the server sends no Content-Type */
+export type ResponseFormat = 'json' | 'text' | 'binary';
+
 export class ApiError extends Error {
   constructor(
     public statusCode: number,
@@ -78,6 +116,7 @@ export class {{ .ClientName }}Client {
       params?: Record<string, any>;
       headers?: Record<string, string>;
       body?: any;
+      format?: ResponseFormat;
     } = {}
   ): Promise<T> {
     const url = new URL(path, this.baseURL);
@@ -120,9 +159,22 @@ export class {{ .ClientName }}Client {
         throw new ApiError(response.status, response.statusText, errorBody);
       }
 
+      // Decode by Content-Type, falling back to the format declared by the
+      // spec when the server sends none
       const contentType = response.headers.get('content-type');
-      if (contentType && contentType.includes('application/json')) {
+      let format: ResponseFormat = options.format ?? 'text';
+      if (contentType) {
+        if (/[/+]json\b/.test(contentType)) {
+          format = 'json';
+        } else if (format !== 'binary') {
+          format = 'text';
+        }
+      }
+
+      if (format === 'json') {
         return await response.json();
+      } else if (format === 'binary') {
+        return await response.arrayBuffer() as unknown as T;
       } else {
         return await response.text() as unknown as T;
       }
@@ -194,6 +246,9 @@ export class {{ .ClientName }}Client {
         {{- if .HasRequestBody }}
         body,
         {{- end }}
+        {{- if .ResponseFormat }}
+        format: '{{ .ResponseFormat }}',
+        {{- end }}
       }
     );
   }
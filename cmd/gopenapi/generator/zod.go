@@ -0,0 +1,246 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runpod/gopenapi"
+)
+
+// ZodData is the template data for generated zod schemas
+type ZodData struct {
+	Schemas []ZodSchemaData
+}
+
+// ZodSchemaData is a zod schema generated for one component schema
+type ZodSchemaData struct {
+	Name string // Component name, used for the NameSchema constant and Name type
+	Expr string // zod expression, e.g. z.object({ ... })
+}
+
+// zodFormats maps string formats to zod refinements
+var zodFormats = map[string]string{
+	"date-time": ".datetime()",
+	"email":     ".email()",
+	"uri":       ".url()",
+	"url":       ".url()",
+	"uuid":      ".uuid()",
+}
+
+// GenerateZod writes TypeScript zod schemas for the component schemas of spec to outputFile
+func GenerateZod(spec *gopenapi.Spec, outputFile string) error {
+	outFile, err := createOutputFile(outputFile)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	return GenerateZodToWriter(spec, outFile)
+}
+
+// GenerateZodToWriter writes TypeScript zod schemas for the component schemas
+// of spec to the provided writer, one exported NameSchema constant and inferred
+// Name type per component
+func GenerateZodToWriter(spec *gopenapi.Spec, writer io.Writer) error {
+	return renderToWriter(writer, "templates/zod.tpl", "zod", generateZodData(spec))
+}
+
+func generateZodData(spec *gopenapi.Spec) *ZodData {
+	names := make([]string, 0, len(spec.Components.Schemas))
+	for name := range spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Struct types declared as components are referenced rather than inlined
+	components := map[reflect.Type]string{}
+	for _, name := range names {
+		if t := spec.Components.Schemas[name].Type; t != nil && t.Kind() == reflect.Struct && t.Name() != "" {
+			components[t] = name
+		}
+	}
+
+	data := &ZodData{}
+	for _, name := range names {
+		schema := spec.Components.Schemas[name]
+		z := zodBuilder{components: components, self: schema.Type}
+		data.Schemas = append(data.Schemas, ZodSchemaData{
+			Name: name,
+			Expr: z.schema(schema, ""),
+		})
+	}
+	return data
+}
+
+// zodBuilder renders schemas as zod expressions
+type zodBuilder struct {
+	components map[reflect.Type]string
+	self       reflect.Type          // Type of the component being rendered, inlined at the top level
+	visiting   map[reflect.Type]bool // Anonymous struct types being rendered, to stop on cycles
+}
+
+// schema renders a gopenapi.Schema, indenting nested object fields by indent
+func (z *zodBuilder) schema(schema gopenapi.Schema, indent string) string {
+	if name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/"); ok {
+		return fmt.Sprintf("z.lazy(() => %sSchema)", name)
+	}
+	if len(schema.Enum) > 0 {
+		return zodEnum(schema.Enum)
+	}
+
+	var expr string
+	switch {
+	case schema.Type == nil:
+		return "z.unknown()"
+	case schema.Items != nil && (schema.Type.Kind() == reflect.Slice || schema.Type.Kind() == reflect.Array):
+		expr = fmt.Sprintf("z.array(%s)", z.schema(*schema.Items, indent))
+	default:
+		expr = z.goType(schema.Type, indent)
+	}
+	if schema.Type.Kind() == reflect.String {
+		expr += zodFormats[schema.Format]
+	}
+	return expr + zodConstraints(schema)
+}
+
+// zodConstraints renders the range, length and pattern constraints of a string
+// or number schema as zod refinements, e.g. .min(1).max(100)
+func zodConstraints(schema gopenapi.Schema) string {
+	var b strings.Builder
+	switch schema.Type.Kind() {
+	case reflect.String:
+		if schema.MinLength != nil {
+			fmt.Fprintf(&b, ".min(%d)", *schema.MinLength)
+		}
+		if schema.MaxLength != nil {
+			fmt.Fprintf(&b, ".max(%d)", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			pattern, _ := json.Marshal(schema.Pattern)
+			fmt.Fprintf(&b, ".regex(new RegExp(%s))", pattern)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if schema.Minimum != nil {
+			method := "min"
+			if schema.ExclusiveMinimum {
+				method = "gt"
+			}
+			fmt.Fprintf(&b, ".%s(%v)", method, *schema.Minimum)
+		}
+		if schema.Maximum != nil {
+			method := "max"
+			if schema.ExclusiveMaximum {
+				method = "lt"
+			}
+			fmt.Fprintf(&b, ".%s(%v)", method, *schema.Maximum)
+		}
+	}
+	return b.String()
+}
+
+// goType renders a Go type, referencing component struct types by name
+func (z *zodBuilder) goType(t reflect.Type, indent string) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "z.string().datetime()"
+	}
+	if name, ok := z.components[t]; ok && t != z.self {
+		return fmt.Sprintf("z.lazy(() => %sSchema)", name)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "z.string()"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "z.number().int()"
+	case reflect.Float32, reflect.Float64:
+		return "z.number()"
+	case reflect.Bool:
+		return "z.boolean()"
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("z.array(%s)", z.goType(t.Elem(), indent))
+	case reflect.Map:
+		return fmt.Sprintf("z.record(z.string(), %s)", z.goType(t.Elem(), indent))
+	case reflect.Ptr:
+		return z.goType(t.Elem(), indent) + ".nullable()"
+	case reflect.Struct:
+		return z.object(t, indent)
+	default:
+		return "z.unknown()"
+	}
+}
+
+// object renders a struct type as z.object. Fields tagged omitempty are optional,
+// matching the required properties of the emitted OpenAPI schema.
+func (z *zodBuilder) object(t reflect.Type, indent string) string {
+	if z.visiting[t] {
+		return "z.unknown()"
+	}
+	if z.visiting == nil {
+		z.visiting = map[reflect.Type]bool{}
+	}
+	z.visiting[t] = true
+	defer delete(z.visiting, t)
+
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, field := range gopenapi.JSONFields(t) {
+		name := field.JSONName
+
+		var expr string
+		if enum := field.Tag.Get("enum"); enum != "" {
+			values := []any{}
+			for _, value := range strings.Split(enum, ",") {
+				values = append(values, value)
+			}
+			expr = zodEnum(values)
+		} else {
+			expr = z.goType(field.Type, indent+"  ")
+			if format := field.Tag.Get("format"); format != "" && field.Type.Kind() == reflect.String {
+				expr += zodFormats[format]
+			}
+		}
+		if strings.Contains(field.Tag.Get("json"), "omitempty") {
+			expr += ".optional()"
+		}
+
+		key, _ := json.Marshal(name)
+		if isIdentifier(name) {
+			key = []byte(name)
+		}
+		fmt.Fprintf(&b, "%s  %s: %s,\n", indent, key, expr)
+	}
+	b.WriteString(indent + "})")
+	return b.String()
+}
+
+// zodEnum renders enum values as z.enum for strings or a union of literals otherwise
+func zodEnum(values []any) string {
+	literals := make([]string, len(values))
+	allStrings := true
+	for i, value := range values {
+		encoded, _ := json.Marshal(value)
+		literals[i] = string(encoded)
+		if _, ok := value.(string); !ok {
+			allStrings = false
+		}
+	}
+
+	if allStrings {
+		return fmt.Sprintf("z.enum([%s])", strings.Join(literals, ", "))
+	}
+	if len(literals) == 1 {
+		return fmt.Sprintf("z.literal(%s)", literals[0])
+	}
+	for i, literal := range literals {
+		literals[i] = fmt.Sprintf("z.literal(%s)", literal)
+	}
+	return fmt.Sprintf("z.union([%s])", strings.Join(literals, ", "))
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/runpod/gopenapi"
+)
+
+type zodAddress struct {
+	City string `json:"city"`
+}
+
+type zodUser struct {
+	ID      string            `json:"id" format:"uuid"`
+	Name    string            `json:"name"`
+	Status  string            `json:"status" enum:"active,inactive"`
+	Age     int               `json:"age,omitempty"`
+	Address *zodAddress       `json:"address"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func TestGenerateZod(t *testing.T) {
+	spec := gopenapi.Spec{
+		Components: gopenapi.Components{
+			Schemas: gopenapi.Schemas{
+				"User":    {Type: gopenapi.Object[zodUser]()},
+				"Address": {Type: gopenapi.Object[zodAddress]()},
+				"Role":    {Type: gopenapi.String, Enum: []any{"admin", "member"}},
+				"Level":   {Type: gopenapi.Integer, Enum: []any{1, 2}},
+				"UserIds": {Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.String, Format: "uuid"}},
+				"Owner":   {Ref: "#/components/schemas/User"},
+				"Page":    gopenapi.IntRange(1, 100),
+				"Ratio":   {Type: gopenapi.Number, Minimum: gopenapi.Ptr(0.0), Maximum: gopenapi.Ptr(1.5), ExclusiveMaximum: true},
+				"Slug":    {Type: gopenapi.String, MinLength: gopenapi.Ptr(3), MaxLength: gopenapi.Ptr(32), Pattern: `^[a-z0-9-]+/\d$`},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateZodToWriter(&spec, &buf); err != nil {
+		t.Fatalf("GenerateZodToWriter() error = %v", err)
+	}
+	output := buf.String()
+
+	expected := []string{
+		`import { z } from "zod";`,
+		"export const UserSchema = z.object({\n" +
+			"  id: z.string().uuid(),\n" +
+			"  name: z.string(),\n" +
+			"  status: z.enum([\"active\", \"inactive\"]),\n" +
+			"  age: z.number().int().optional(),\n" +
+			"  address: z.lazy(() => AddressSchema).nullable(),\n" +
+			"  tags: z.array(z.string()),\n" +
+			"  labels: z.record(z.string(), z.string()).optional(),\n" +
+			"});",
+		"export type User = z.infer<typeof UserSchema>;",
+		"export const AddressSchema = z.object({\n  city: z.string(),\n});",
+		`export const RoleSchema = z.enum(["admin", "member"]);`,
+		`export const LevelSchema = z.union([z.literal(1), z.literal(2)]);`,
+		`export const UserIdsSchema = z.array(z.string().uuid());`,
+		`export const OwnerSchema = z.lazy(() => UserSchema);`,
+		`export const PageSchema = z.number().int().min(1).max(100);`,
+		`export const RatioSchema = z.number().min(0).lt(1.5);`,
+		`export const SlugSchema = z.string().min(3).max(32).regex(new RegExp("^[a-z0-9-]+/\\d$"));`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected zod output to contain:\n%s\ngot:\n%s", want, output)
+		}
+	}
+
+	// Components are emitted in name order so output is stable
+	if strings.Index(output, "AddressSchema =") > strings.Index(output, "UserSchema =") {
+		t.Errorf("Expected schemas to be sorted by name, got:\n%s", output)
+	}
+}
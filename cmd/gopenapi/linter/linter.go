@@ -0,0 +1,130 @@
+package linter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/runpod/gopenapi"
+)
+
+// Finding is a single problem reported by a rule
+type Finding struct {
+	Rule     string // Name of the rule that produced the finding
+	Location string // Where the problem was found, e.g. "GET /users responses.200"
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s]", f.Location, f.Message, f.Rule)
+}
+
+// Rule checks a spec for one kind of problem
+type Rule struct {
+	Name        string
+	Description string
+	Check       func(spec *gopenapi.Spec) []Finding
+}
+
+// DefaultRules returns the rules run by `gopenapi validate`
+func DefaultRules() []Rule {
+	return []Rule{
+		ResponseSchemaRule,
+		RequestBodyMethodRule,
+		OperationResponsesRule,
+		EnumTypeRule,
+	}
+}
+
+// Lint runs the given rules over spec and returns their findings ordered by location
+func Lint(spec *gopenapi.Spec, rules []Rule) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		for _, finding := range rule.Check(spec) {
+			finding.Rule = rule.Name
+			findings = append(findings, finding)
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		return findings[i].Rule < findings[j].Rule
+	})
+	return findings
+}
+
+// operationRef identifies an operation within a spec
+type operationRef struct {
+	Method    string
+	Path      string
+	Operation *gopenapi.Operation
+	// Item is the path item declaring the operation
+	Item gopenapi.Path
+}
+
+// parameters returns the parameters of the operation, including those shared
+// by its path item
+func (o operationRef) parameters() gopenapi.Parameters {
+	return o.Item.OperationParameters(o.Operation)
+}
+
+func (o operationRef) String() string {
+	return o.Method + " " + o.Path
+}
+
+// operations returns every operation in spec, ordered by path and then method
+func operations(spec *gopenapi.Spec) []operationRef {
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operationRef
+	for _, path := range paths {
+		item := spec.Paths[path]
+		for _, op := range []operationRef{
+			{Method: "GET", Operation: item.Get},
+			{Method: "PUT", Operation: item.Put},
+			{Method: "POST", Operation: item.Post},
+			{Method: "DELETE", Operation: item.Delete},
+			{Method: "OPTIONS", Operation: item.Options},
+			{Method: "HEAD", Operation: item.Head},
+			{Method: "PATCH", Operation: item.Patch},
+			{Method: "TRACE", Operation: item.Trace},
+		} {
+			if op.Operation == nil {
+				continue
+			}
+			op.Path = path
+			op.Item = item
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// resolveSchema follows local component references until it reaches a schema
+// without one, reporting an error for references that cannot be resolved
+func resolveSchema(spec *gopenapi.Spec, schema gopenapi.Schema) (gopenapi.Schema, error) {
+	seen := map[string]bool{}
+	for schema.Ref != "" {
+		if seen[schema.Ref] {
+			return schema, fmt.Errorf("circular reference %s", schema.Ref)
+		}
+		seen[schema.Ref] = true
+
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return schema, fmt.Errorf("unsupported reference %s", schema.Ref)
+		}
+		target, ok := spec.Components.Schemas[name]
+		if !ok {
+			return schema, fmt.Errorf("dangling reference %s", schema.Ref)
+		}
+		schema = target
+	}
+	return schema, nil
+}
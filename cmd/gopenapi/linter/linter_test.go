@@ -0,0 +1,282 @@
+package linter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/runpod/gopenapi"
+)
+
+func TestResponseSchemaRule(t *testing.T) {
+	spec := gopenapi.Spec{
+		Components: gopenapi.Components{
+			Schemas: gopenapi.Schemas{
+				"User":  {Type: gopenapi.Object[struct{ Name string }]()},
+				"Empty": {},
+			},
+		},
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Users",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{}},
+							},
+						},
+						// Non-2xx responses are not checked
+						404: {
+							Description: "Not found",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{}},
+							},
+						},
+					},
+				},
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Responses: gopenapi.Responses{
+						201: {
+							Description: "Created",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: "#/components/schemas/User"}},
+							},
+						},
+						// Composed schemas are described by their members
+						200: {
+							Description: "Created or existing",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{OneOf: []gopenapi.Schema{{Ref: "#/components/schemas/User"}}}},
+							},
+						},
+						// Responses without content are allowed
+						204: {Description: "No content"},
+					},
+				},
+			},
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "User",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: "#/components/schemas/Missing"}},
+							},
+						},
+						202: {
+							Description: "Accepted",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: "#/components/schemas/Empty"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []Finding{
+		{
+			Rule:     "response-schema",
+			Location: "GET /users responses.200.content[application/json]",
+			Message:  "schema is empty",
+		},
+		{
+			Rule:     "response-schema",
+			Location: "GET /users/{id} responses.200.content[application/json]",
+			Message:  "dangling reference #/components/schemas/Missing",
+		},
+		{
+			Rule:     "response-schema",
+			Location: "GET /users/{id} responses.202.content[application/json]",
+			Message:  "schema referenced by #/components/schemas/Empty is empty",
+		},
+	}
+
+	findings := Lint(&spec, []Rule{ResponseSchemaRule})
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected findings:\n%v\ngot:\n%v", expected, findings)
+	}
+}
+
+func TestRequestBodyMethodRule(t *testing.T) {
+	body := gopenapi.RequestBody{
+		Content: gopenapi.Content{
+			gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[struct{ Query string }]()}},
+		},
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get:  &gopenapi.Operation{OperationId: "searchUsers", RequestBody: body},
+				Post: &gopenapi.Operation{OperationId: "createUser", RequestBody: body},
+			},
+			"/users/{id}": gopenapi.Path{
+				Get:    &gopenapi.Operation{OperationId: "getUser"},
+				Delete: &gopenapi.Operation{OperationId: "deleteUser", RequestBody: body},
+			},
+		},
+	}
+
+	expected := []Finding{
+		{
+			Rule:     "request-body-method",
+			Location: "DELETE /users/{id} requestBody",
+			Message:  "DELETE operations should not declare a request body",
+		},
+		{
+			Rule:     "request-body-method",
+			Location: "GET /users requestBody",
+			Message:  "GET operations should not declare a request body",
+		},
+	}
+
+	findings := Lint(&spec, []Rule{RequestBodyMethodRule})
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected findings:\n%v\ngot:\n%v", expected, findings)
+	}
+}
+
+func TestOperationResponsesRule(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Responses:   gopenapi.Responses{200: {Description: "Users"}},
+				},
+				Post: &gopenapi.Operation{OperationId: "createUser"},
+			},
+		},
+	}
+
+	expected := []Finding{
+		{
+			Rule:     "operation-responses",
+			Location: "POST /users responses",
+			Message:  "operation declares no responses",
+		},
+	}
+
+	findings := Lint(&spec, []Rule{OperationResponsesRule})
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected findings:\n%v\ngot:\n%v", expected, findings)
+	}
+}
+
+func TestEnumTypeRule(t *testing.T) {
+	spec := gopenapi.Spec{
+		Components: gopenapi.Components{
+			Schemas: gopenapi.Schemas{
+				"Status": {Type: gopenapi.String, Enum: []any{"active", 1}},
+			},
+		},
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				// Parameters shared by the path are checked for each operation
+				Parameters: gopenapi.Parameters{
+					{Name: "region", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Enum: []any{"eu", 2}}},
+				},
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer, Enum: []any{10, "a", 50.0}}},
+						{Name: "ratio", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Number, Enum: []any{0.5, 1}}},
+						{Name: "tags", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.Boolean, Enum: []any{true, "false"}}}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []Finding{
+		{
+			Rule:     "enum-type",
+			Location: "GET /users parameters[limit].schema.enum[1]",
+			Message:  `enum value "a" is not of type integer`,
+		},
+		{
+			Rule:     "enum-type",
+			Location: "GET /users parameters[region].schema.enum[1]",
+			Message:  "enum value 2 is not of type string",
+		},
+		{
+			Rule:     "enum-type",
+			Location: "GET /users parameters[tags].schema.items.enum[1]",
+			Message:  `enum value "false" is not of type boolean`,
+		},
+		{
+			Rule:     "enum-type",
+			Location: "components.schemas.Status.enum[1]",
+			Message:  "enum value 1 is not of type string",
+		},
+	}
+
+	findings := Lint(&spec, []Rule{EnumTypeRule})
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected findings:\n%v\ngot:\n%v", expected, findings)
+	}
+}
+
+func TestQueryParamCaseRule(t *testing.T) {
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				// Parameters shared by the path are checked for each operation
+				Parameters: gopenapi.Parameters{
+					{Name: "include_deleted", In: gopenapi.InQuery},
+				},
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Parameters: gopenapi.Parameters{
+						{Name: "pageSize", In: gopenapi.InQuery},
+						{Name: "sort_order", In: gopenapi.InQuery},
+						// Only query parameters are checked
+						{Name: "X-Request_ID", In: gopenapi.InHeader},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		casing   Casing
+		expected []Finding
+	}{
+		{
+			casing: CasingCamel,
+			expected: []Finding{
+				{
+					Rule:     "query-param-case",
+					Location: "GET /users parameters[include_deleted]",
+					Message:  `query parameter "include_deleted" is not camel case`,
+				},
+				{
+					Rule:     "query-param-case",
+					Location: "GET /users parameters[sort_order]",
+					Message:  `query parameter "sort_order" is not camel case`,
+				},
+			},
+		},
+		{
+			casing: CasingSnake,
+			expected: []Finding{{
+				Rule:     "query-param-case",
+				Location: "GET /users parameters[pageSize]",
+				Message:  `query parameter "pageSize" is not snake case`,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.casing), func(t *testing.T) {
+			findings := Lint(&spec, []Rule{QueryParamCaseRule(tt.casing)})
+			if !reflect.DeepEqual(findings, tt.expected) {
+				t.Errorf("Expected findings:\n%v\ngot:\n%v", tt.expected, findings)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package linter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/runpod/gopenapi"
+)
+
+// ResponseSchemaRule requires every 2xx response that declares content to have
+// a schema with a type or composed members, or a reference that resolves to one
+var ResponseSchemaRule = Rule{
+	Name:        "response-schema",
+	Description: "2xx response content must declare a non-empty, resolvable schema",
+	Check: func(spec *gopenapi.Spec) []Finding {
+		var findings []Finding
+		for _, op := range operations(spec) {
+			for status, response := range op.Operation.Responses {
+				if status < 200 || status > 299 {
+					continue
+				}
+
+				mediaTypes := make([]string, 0, len(response.Content))
+				for mediaType := range response.Content {
+					mediaTypes = append(mediaTypes, string(mediaType))
+				}
+				sort.Strings(mediaTypes)
+
+				for _, mediaType := range mediaTypes {
+					location := fmt.Sprintf("%s responses.%d.content[%s]", op, status, mediaType)
+					schema := response.Content[gopenapi.MediaType(mediaType)].Schema
+					if schema.Type == nil && schema.Ref == "" && !composed(schema) {
+						findings = append(findings, Finding{Location: location, Message: "schema is empty"})
+						continue
+					}
+					resolved, err := resolveSchema(spec, schema)
+					if err != nil {
+						findings = append(findings, Finding{Location: location, Message: err.Error()})
+						continue
+					}
+					if resolved.Type == nil && !composed(resolved) {
+						findings = append(findings, Finding{Location: location, Message: fmt.Sprintf("schema referenced by %s is empty", schema.Ref)})
+					}
+				}
+			}
+		}
+		return findings
+	},
+}
+
+// composed reports whether schema is described by allOf, oneOf or anyOf members
+func composed(schema gopenapi.Schema) bool {
+	return len(schema.AllOf) > 0 || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0
+}
+
+// RequestBodyMethodRule flags request bodies declared on GET, HEAD and DELETE
+// operations, whose bodies have no defined semantics and are often dropped by
+// clients and proxies
+var RequestBodyMethodRule = Rule{
+	Name:        "request-body-method",
+	Description: "GET, HEAD and DELETE operations must not declare a request body",
+	Check: func(spec *gopenapi.Spec) []Finding {
+		var findings []Finding
+		for _, op := range operations(spec) {
+			switch op.Method {
+			case "GET", "HEAD", "DELETE":
+			default:
+				continue
+			}
+			if len(op.Operation.RequestBody.Content) == 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				Location: fmt.Sprintf("%s requestBody", op),
+				Message:  fmt.Sprintf("%s operations should not declare a request body", op.Method),
+			})
+		}
+		return findings
+	},
+}
+
+// OperationResponsesRule requires every operation to declare at least one
+// response, as an empty responses object is invalid OpenAPI
+var OperationResponsesRule = Rule{
+	Name:        "operation-responses",
+	Description: "operations must declare at least one response",
+	Check: func(spec *gopenapi.Spec) []Finding {
+		var findings []Finding
+		for _, op := range operations(spec) {
+			if len(op.Operation.Responses) > 0 {
+				continue
+			}
+			findings = append(findings, Finding{
+				Location: fmt.Sprintf("%s responses", op),
+				Message:  "operation declares no responses",
+			})
+		}
+		return findings
+	},
+}
+
+// EnumTypeRule requires the enum values of string, integer, number and boolean
+// schemas to be of the schema's type, e.g. no "a" in an Integer enum
+var EnumTypeRule = Rule{
+	Name:        "enum-type",
+	Description: "enum values must match the schema type",
+	Check: func(spec *gopenapi.Spec) []Finding {
+		var findings []Finding
+		check := func(location string, schema gopenapi.Schema) {
+			findings = append(findings, enumTypeFindings(location, schema)...)
+		}
+
+		names := make([]string, 0, len(spec.Components.Schemas))
+		for name := range spec.Components.Schemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			check("components.schemas."+name, spec.Components.Schemas[name])
+		}
+
+		for _, op := range operations(spec) {
+			for _, param := range op.parameters() {
+				check(fmt.Sprintf("%s parameters[%s].schema", op, param.Name), param.Schema)
+			}
+			for _, mediaType := range sortedMediaTypes(op.Operation.RequestBody.Content) {
+				check(fmt.Sprintf("%s requestBody.content[%s].schema", op, mediaType), op.Operation.RequestBody.Content[mediaType].Schema)
+			}
+
+			statuses := make([]int, 0, len(op.Operation.Responses))
+			for status := range op.Operation.Responses {
+				statuses = append(statuses, status)
+			}
+			sort.Ints(statuses)
+			for _, status := range statuses {
+				response := op.Operation.Responses[status]
+				headers := make([]string, 0, len(response.Headers))
+				for name := range response.Headers {
+					headers = append(headers, name)
+				}
+				sort.Strings(headers)
+				for _, name := range headers {
+					check(fmt.Sprintf("%s responses.%d.headers[%s].schema", op, status, name), response.Headers[name].Schema)
+				}
+				for _, mediaType := range sortedMediaTypes(response.Content) {
+					check(fmt.Sprintf("%s responses.%d.content[%s].schema", op, status, mediaType), response.Content[mediaType].Schema)
+				}
+			}
+		}
+		return findings
+	},
+}
+
+// enumTypeFindings checks the enum values of schema and of its array items
+func enumTypeFindings(location string, schema gopenapi.Schema) []Finding {
+	var findings []Finding
+	if expected, ok := enumKind(schema.Type); ok {
+		for i, value := range schema.Enum {
+			if !enumValueMatches(expected, value) {
+				findings = append(findings, Finding{
+					Location: fmt.Sprintf("%s.enum[%d]", location, i),
+					Message:  fmt.Sprintf("enum value %#v is not of type %s", value, expected),
+				})
+			}
+		}
+	}
+	if schema.Items != nil {
+		findings = append(findings, enumTypeFindings(location+".items", *schema.Items)...)
+	}
+	for i, item := range schema.PrefixItems {
+		findings = append(findings, enumTypeFindings(fmt.Sprintf("%s.prefixItems[%d]", location, i), item)...)
+	}
+	for i, member := range schema.AllOf {
+		findings = append(findings, enumTypeFindings(fmt.Sprintf("%s.allOf[%d]", location, i), member)...)
+	}
+	for i, member := range schema.OneOf {
+		findings = append(findings, enumTypeFindings(fmt.Sprintf("%s.oneOf[%d]", location, i), member)...)
+	}
+	for i, member := range schema.AnyOf {
+		findings = append(findings, enumTypeFindings(fmt.Sprintf("%s.anyOf[%d]", location, i), member)...)
+	}
+	return findings
+}
+
+// enumKind returns the OpenAPI type whose enum values can be checked for t
+func enumKind(t reflect.Type) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer", true
+	case reflect.Float32, reflect.Float64:
+		return "number", true
+	case reflect.Bool:
+		return "boolean", true
+	}
+	return "", false
+}
+
+// enumValueMatches reports whether value is a Go value of the OpenAPI type
+// expected. Integral floats are integers, as decoded JSON numbers are float64.
+func enumValueMatches(expected string, value any) bool {
+	v := reflect.ValueOf(value)
+	switch expected {
+	case "string":
+		return v.Kind() == reflect.String
+	case "boolean":
+		return v.Kind() == reflect.Bool
+	case "integer":
+		return v.CanInt() || v.CanUint() || (v.CanFloat() && v.Float() == math.Trunc(v.Float()))
+	case "number":
+		return v.CanInt() || v.CanUint() || v.CanFloat()
+	}
+	return true
+}
+
+// sortedMediaTypes returns the media types declared in content in sorted order
+func sortedMediaTypes(content gopenapi.Content) []gopenapi.MediaType {
+	mediaTypes := make([]gopenapi.MediaType, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool { return mediaTypes[i] < mediaTypes[j] })
+	return mediaTypes
+}
+
+// Casing is a naming convention for identifiers in the API surface
+type Casing string
+
+const (
+	CasingCamel Casing = "camel" // e.g. pageSize
+	CasingSnake Casing = "snake" // e.g. page_size
+)
+
+var casingPatterns = map[Casing]*regexp.Regexp{
+	CasingCamel: regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	CasingSnake: regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`),
+}
+
+// ParseCasing parses a casing name as accepted by the -query-param-case flag
+func ParseCasing(s string) (Casing, error) {
+	switch strings.ToLower(s) {
+	case "camel", "camelcase":
+		return CasingCamel, nil
+	case "snake", "snake_case":
+		return CasingSnake, nil
+	default:
+		return "", fmt.Errorf("unknown casing %q (expected camel or snake)", s)
+	}
+}
+
+// QueryParamCaseRule requires every query parameter name to follow casing
+func QueryParamCaseRule(casing Casing) Rule {
+	pattern := casingPatterns[casing]
+	return Rule{
+		Name:        "query-param-case",
+		Description: fmt.Sprintf("query parameter names must be %s case", casing),
+		Check: func(spec *gopenapi.Spec) []Finding {
+			var findings []Finding
+			for _, op := range operations(spec) {
+				for _, param := range op.parameters() {
+					if param.In != gopenapi.InQuery || pattern.MatchString(param.Name) {
+						continue
+					}
+					findings = append(findings, Finding{
+						Location: fmt.Sprintf("%s parameters[%s]", op, param.Name),
+						Message:  fmt.Sprintf("query parameter %q is not %s case", param.Name, casing),
+					})
+				}
+			}
+			return findings
+		},
+	}
+}
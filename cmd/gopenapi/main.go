@@ -5,12 +5,18 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/runpod/gopenapi"
 	"github.com/runpod/gopenapi/cmd/gopenapi/generator"
 	"github.com/runpod/gopenapi/cmd/gopenapi/parser"
 )
 
+// loader type-checks the spec's package once per invocation, however many
+// times the spec is parsed
+var loader = parser.NewLoader()
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -30,11 +36,21 @@ func main() {
 			generateSpecCommand()
 		case "client":
 			generateClientCommand()
+		case "zod":
+			generateZodCommand()
+		case "all":
+			generateAllCommand()
 		default:
 			fmt.Fprintf(os.Stderr, "Unknown generate subcommand: %s\n\n", subcommand)
 			printGenerateUsage()
 			os.Exit(1)
 		}
+	case "validate":
+		validateCommand()
+	case "verify":
+		verifyCommand()
+	case "explain":
+		explainCommand()
 	case "help", "-h", "--help":
 		printUsage()
 	default:
@@ -50,6 +66,11 @@ func printUsage() {
 Usage:
   gopenapi generate spec [flags]    Generate OpenAPI JSON specification
   gopenapi generate client [flags]  Generate API clients
+  gopenapi generate zod [flags]     Generate TypeScript zod schemas for component schemas
+  gopenapi generate all [flags]     Generate the OpenAPI JSON specification and API clients
+  gopenapi validate [flags]         Check the spec for contract problems
+  gopenapi verify [flags]           Verify a live server's OpenAPI JSON against the spec
+  gopenapi explain [flags]          Describe a single operation of the spec
   gopenapi help                     Show this help message
 
 Use "gopenapi generate <subcommand> -help" for more information about a subcommand.
@@ -60,6 +81,8 @@ func printGenerateUsage() {
 	fmt.Fprintf(os.Stderr, `Usage:
   gopenapi generate spec [flags]    Generate OpenAPI JSON specification
   gopenapi generate client [flags]  Generate API clients
+  gopenapi generate zod [flags]     Generate TypeScript zod schemas for component schemas
+  gopenapi generate all [flags]     Generate the OpenAPI JSON specification and API clients
 
 Use "gopenapi generate <subcommand> -help" for more information about a subcommand.
 `)
@@ -69,12 +92,15 @@ func generateSpecCommand() {
 	fs := flag.NewFlagSet("generate spec", flag.ExitOnError)
 	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
 	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
-	output := fs.String("output", "", "Output file for OpenAPI JSON (if empty, outputs to stdout)")
+	output := fs.String("output", "", "Output file for OpenAPI document (if empty, outputs to stdout)")
 	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	operationIdCase := fs.String("operationid-case", "preserve", "Casing policy for emitted operationIds (preserve, camel, pascal)")
+	format := fs.String("format", "json", "Output format for the OpenAPI document (json, yaml)")
+	emptyAnySchema := fs.Bool("empty-any-schema", false, "Describe interface{} and any values with the empty schema {} instead of an object")
 	help := fs.Bool("help", false, "Show help information")
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `Generate OpenAPI JSON specification from Go code
+		fmt.Fprintf(os.Stderr, `Generate OpenAPI JSON or YAML specification from Go code
 
 Usage:
   gopenapi generate spec [flags]
@@ -85,15 +111,24 @@ Flags:
   -var string
         Variable name containing the spec (required, e.g., 'ExampleSpec')
   -output string
-        Output file for OpenAPI JSON (if empty, outputs to stdout)
+        Output file for OpenAPI document (if empty, outputs to stdout)
   -path string
         Working directory for package resolution (defaults to current directory)
+  -operationid-case string
+        Casing policy for emitted operationIds: preserve, camel, pascal (default "preserve")
+  -format string
+        Output format for the OpenAPI document: json, yaml (default "json")
+  -empty-any-schema
+        Describe interface{} and any values, which may hold any JSON value, with
+        the empty schema {} instead of "type": "object"
   -help
         Show this help message
 
 Examples:
   gopenapi generate spec -spec examples/spec/spec.go -var ExampleSpec -output openapi.json
   gopenapi generate spec -spec examples/spec/spec.go -var ExampleSpec -path /path/to/project
+  gopenapi generate spec -spec examples/spec/spec.go -var ExampleSpec -operationid-case camel
+  gopenapi generate spec -spec examples/spec/spec.go -var ExampleSpec -format yaml -output openapi.yaml
 `)
 	}
 
@@ -113,36 +148,58 @@ Examples:
 		os.Exit(1)
 	}
 
+	idCase, err := parser.ParseOperationIdCase(*operationIdCase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	if *format != "json" && *format != "yaml" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported format %q (expected json or yaml)\n\n", *format)
+		fs.Usage()
+		os.Exit(1)
+	}
+
 	// Use current directory if path not specified
 	workingDir := *path
 	if workingDir == "" {
-		var err error
 		workingDir, err = os.Getwd()
 		if err != nil {
 			log.Fatalf("Failed to get current directory: %v", err)
 		}
 	}
 
-	spec, err := parser.ParseSpecFromFileWithPath(*specFile, *specVar, workingDir)
+	spec, err := loader.ParseSpec(*specFile, *specVar, workingDir)
 	if err != nil {
 		log.Fatalf("Failed to parse spec from file: %v", err)
 	}
 
-	// Convert spec to OpenAPI JSON
-	jsonData, err := parser.SpecToOpenAPIJSON(&spec)
+	// Convert spec to the requested OpenAPI format
+	specOpts := parser.SpecOptions{
+		OperationIdCase: idCase,
+		EmptyAnySchema:  *emptyAnySchema,
+	}
+	var data []byte
+	formatName := strings.ToUpper(*format)
+	if *format == "yaml" {
+		data, err = parser.SpecToOpenAPIYAMLWithOptions(&spec, specOpts)
+	} else {
+		data, err = parser.SpecToOpenAPIJSONWithOptions(&spec, specOpts)
+	}
 	if err != nil {
-		log.Fatalf("Failed to convert spec to OpenAPI JSON: %v", err)
+		log.Fatalf("Failed to convert spec to OpenAPI %s: %v", formatName, err)
 	}
 
 	// Output to file or stdout
 	if *output == "" {
-		fmt.Print(string(jsonData))
+		fmt.Print(string(data))
 	} else {
-		err := os.WriteFile(*output, jsonData, 0644)
+		err := os.WriteFile(*output, data, 0644)
 		if err != nil {
-			log.Fatalf("Failed to write OpenAPI JSON to file: %v", err)
+			log.Fatalf("Failed to write OpenAPI %s to file: %v", formatName, err)
 		}
-		fmt.Printf("Generated OpenAPI JSON specification: %s\n", *output)
+		fmt.Printf("Generated OpenAPI %s specification: %s\n", formatName, *output)
 	}
 }
 
@@ -154,6 +211,15 @@ func generateClientCommand() {
 	packageName := fs.String("package", "client", "Package name for generated code")
 	languages := fs.String("languages", "go", "Comma-separated list of languages to generate (go,python,typescript)")
 	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	tsEnumStyle := fs.String("ts-enum-style", "union", "How schema enums are rendered in TypeScript (union, enum)")
+	splitModels := fs.Bool("split-models", false, "Write Go model structs to models.go instead of client.go (requires -output)")
+	tagClients := fs.Bool("tag-clients", false, "Group Go client methods into sub-clients by their first tag, e.g. client.Users().ListUsers")
+	pythonAsync := fs.Bool("python-async", false, "Generate an asyncio Python client (AsyncClient) using aiohttp instead of requests")
+	modelsPackage := fs.String("models-package", "", "Import path of a package to write Go model structs to, in the -output subdirectory named after its last element (requires -output)")
+	allowDuplicateIds := fs.Bool("allow-duplicate-ids", false, "Suffix duplicate operationIds with 2, 3, ... instead of failing")
+	noContext := fs.Bool("no-context", false, "Generate Go client methods without a ctx parameter, using context.Background()")
+	validatorTags := fs.Bool("validator-tags", false, "Add go-playground/validator validate tags derived from schema constraints to Go struct fields")
+	queryStructs := fs.Bool("query-structs", false, "Pass the query parameters of Go client methods as a separate <Operation>Query argument")
 	help := fs.Bool("help", false, "Show help information")
 
 	fs.Usage = func() {
@@ -176,6 +242,27 @@ Flags:
         Supported languages: go, python, typescript
   -path string
         Working directory for package resolution (defaults to current directory)
+  -ts-enum-style string
+        How schema enums are rendered in TypeScript: union, enum (default "union")
+  -split-models
+        Write Go model structs to models.go instead of client.go (requires -output)
+  -models-package string
+        Import path of a package to write Go model structs to, in the -output
+        subdirectory named after its last element (requires -output)
+  -tag-clients
+        Group Go client methods into sub-clients by their first tag, e.g. client.Users().ListUsers
+  -python-async
+        Generate an asyncio Python client (AsyncClient) using aiohttp instead of requests
+  -allow-duplicate-ids
+        Suffix duplicate operationIds with 2, 3, ... instead of failing
+  -no-context
+        Generate Go client methods without a ctx parameter, using context.Background()
+  -validator-tags
+        Add go-playground/validator validate tags derived from schema constraints
+        to Go struct fields, e.g. validate:"required,min=1"
+  -query-structs
+        Pass the query parameters of Go client methods as a separate <Operation>Query
+        argument, e.g. client.ListUsers(ctx, &ListUsersQuery{Limit: 10}, nil)
   -help
         Show this help message
 
@@ -183,6 +270,7 @@ Examples:
   gopenapi generate client -spec examples/spec/spec.go -var ExampleSpec -output ./clients
   gopenapi generate client -spec examples/spec/spec.go -var ExampleSpec -languages go,python
   gopenapi generate client -spec examples/spec/spec.go -var ExampleSpec -package myclient -path /path/to/project
+  gopenapi generate client -spec examples/spec/spec.go -var ExampleSpec -output ./client -split-models
 `)
 	}
 
@@ -212,7 +300,7 @@ Examples:
 		}
 	}
 
-	spec, err := parser.ParseSpecFromFileWithPath(*specFile, *specVar, workingDir)
+	spec, err := loader.ParseSpec(*specFile, *specVar, workingDir)
 	if err != nil {
 		log.Fatalf("Failed to parse spec from file: %v", err)
 	}
@@ -230,12 +318,37 @@ Examples:
 		}
 	}
 
+	enumStyle := generator.EnumStyle(*tsEnumStyle)
+	if enumStyle != generator.EnumStyleUnion && enumStyle != generator.EnumStyleEnum {
+		log.Fatalf("Unsupported TypeScript enum style: %s. Supported styles: union, enum", *tsEnumStyle)
+	}
+
+	opts := generator.Options{
+		PackageName:         *packageName,
+		TypeScriptEnumStyle: enumStyle,
+		SplitModels:         *splitModels,
+		TagClients:          *tagClients,
+		PythonAsync:         *pythonAsync,
+		AllowDuplicateIds:   *allowDuplicateIds,
+		ModelsPackage:       *modelsPackage,
+		NoContext:           *noContext,
+		ValidatorTags:       *validatorTags,
+		QueryStructs:        *queryStructs,
+		TypeName:            parser.StructTypeName,
+	}
+
 	// If output directory is not specified, output to stdout (only works for single language)
 	if *outputDir == "" {
+		if *splitModels {
+			log.Fatal("-split-models writes two files. Please specify -output directory.")
+		}
+		if *modelsPackage != "" {
+			log.Fatal("-models-package writes two packages. Please specify -output directory.")
+		}
 		if len(langs) > 1 {
 			log.Fatal("Cannot output multiple languages to stdout. Please specify -output directory or use single language.")
 		}
-		err := generator.GenerateClientToStdout(&spec, langs[0], *packageName)
+		err := generator.GenerateClientToStdoutWithOptions(&spec, langs[0], opts)
 		if err != nil {
 			log.Fatalf("Failed to generate %s client: %v", langs[0], err)
 		}
@@ -244,10 +357,195 @@ Examples:
 
 	// Generate clients for each language to files
 	for _, lang := range langs {
-		err := generator.GenerateClientForLanguage(&spec, lang, *outputDir, *packageName)
+		err := generator.GenerateClientForLanguageWithOptions(&spec, lang, *outputDir, opts)
 		if err != nil {
 			log.Fatalf("Failed to generate %s client: %v", lang, err)
 		}
 		fmt.Printf("Generated %s client in %s\n", lang, *outputDir)
 	}
 }
+
+func generateZodCommand() {
+	fs := flag.NewFlagSet("generate zod", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
+	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
+	output := fs.String("output", "", "Output file for the zod schemas (if empty, outputs to stdout)")
+	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	help := fs.Bool("help", false, "Show help information")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Generate TypeScript zod schemas for the component schemas of the spec
+
+Usage:
+  gopenapi generate zod [flags]
+
+Flags:
+  -spec string
+        Go file containing the OpenAPI spec (required)
+  -var string
+        Variable name containing the spec (required, e.g., 'ExampleSpec')
+  -output string
+        Output file for the zod schemas (if empty, outputs to stdout)
+  -path string
+        Working directory for package resolution (defaults to current directory)
+  -help
+        Show this help message
+
+Examples:
+  gopenapi generate zod -spec examples/spec/spec.go -var ExampleSpec -output schemas.ts
+`)
+	}
+
+	if err := fs.Parse(os.Args[3:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *help {
+		fs.Usage()
+		return
+	}
+
+	if *specFile == "" || *specVar == "" {
+		fmt.Fprintf(os.Stderr, "Error: Both -spec and -var flags are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Use current directory if path not specified
+	workingDir := *path
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get current directory: %v", err)
+		}
+	}
+
+	spec, err := loader.ParseSpec(*specFile, *specVar, workingDir)
+	if err != nil {
+		log.Fatalf("Failed to parse spec from file: %v", err)
+	}
+
+	if *output == "" {
+		if err := generator.GenerateZodToWriter(&spec, os.Stdout); err != nil {
+			log.Fatalf("Failed to generate zod schemas: %v", err)
+		}
+		return
+	}
+
+	if err := generator.GenerateZod(&spec, *output); err != nil {
+		log.Fatalf("Failed to generate zod schemas: %v", err)
+	}
+	fmt.Printf("Generated zod schemas: %s\n", *output)
+}
+
+func generateAllCommand() {
+	fs := flag.NewFlagSet("generate all", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
+	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
+	outputDir := fs.String("output", "", "Output directory for openapi.json and the generated clients (required)")
+	packageName := fs.String("package", "client", "Package name for generated code")
+	languages := fs.String("languages", "go", "Comma-separated list of languages to generate (go,python,typescript)")
+	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	help := fs.Bool("help", false, "Show help information")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Generate the OpenAPI JSON specification and API clients in one pass
+
+Usage:
+  gopenapi generate all [flags]
+
+Flags:
+  -spec string
+        Go file containing the OpenAPI spec (required)
+  -var string
+        Variable name containing the spec (required, e.g., 'ExampleSpec')
+  -output string
+        Output directory for openapi.json and the generated clients (required)
+  -package string
+        Package name for generated code (default "client")
+  -languages string
+        Comma-separated list of languages to generate (default "go")
+        Supported languages: go, python, typescript
+  -path string
+        Working directory for package resolution (defaults to current directory)
+  -help
+        Show this help message
+
+Examples:
+  gopenapi generate all -spec examples/spec/spec.go -var ExampleSpec -output ./api
+  gopenapi generate all -spec examples/spec/spec.go -var ExampleSpec -output ./api -languages go,typescript
+`)
+	}
+
+	if err := fs.Parse(os.Args[3:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *help {
+		fs.Usage()
+		return
+	}
+
+	if *specFile == "" || *specVar == "" || *outputDir == "" {
+		fmt.Fprintf(os.Stderr, "Error: The -spec, -var and -output flags are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Use current directory if path not specified
+	workingDir := *path
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get current directory: %v", err)
+		}
+	}
+
+	spec, err := loader.ParseSpec(*specFile, *specVar, workingDir)
+	if err != nil {
+		log.Fatalf("Failed to parse spec from file: %v", err)
+	}
+
+	langs := strings.Split(*languages, ",")
+	for i, lang := range langs {
+		langs[i] = strings.TrimSpace(lang)
+	}
+
+	opts := generator.Options{PackageName: *packageName, TypeName: parser.StructTypeName}
+	if err := generateAll(&spec, *outputDir, langs, opts); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Generated OpenAPI JSON specification and %s clients in %s\n", strings.Join(langs, ", "), *outputDir)
+}
+
+// generateAll writes the OpenAPI JSON for spec to outputDir/openapi.json and
+// a client for each language to outputDir, reusing the already parsed spec
+func generateAll(spec *gopenapi.Spec, outputDir string, langs []string, opts generator.Options) error {
+	for _, lang := range langs {
+		if lang != "go" && lang != "python" && lang != "typescript" {
+			return fmt.Errorf("unsupported language: %s. Supported languages: go, python, typescript", lang)
+		}
+	}
+
+	data, err := parser.SpecToOpenAPIJSON(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert spec to OpenAPI JSON: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "openapi.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI JSON to file: %w", err)
+	}
+
+	for _, lang := range langs {
+		if err := generator.GenerateClientForLanguageWithOptions(spec, lang, outputDir, opts); err != nil {
+			return fmt.Errorf("failed to generate %s client: %w", lang, err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +13,7 @@ import (
 	"github.com/runpod/gopenapi"
 	"github.com/runpod/gopenapi/cmd/gopenapi/generator"
 	"github.com/runpod/gopenapi/cmd/gopenapi/parser"
+	"github.com/runpod/gopenapi/examples/spec"
 )
 
 // Test spec for integration testing
@@ -153,6 +157,43 @@ func TestIntegrationMultipleLanguages(t *testing.T) {
 	}
 }
 
+// TestGenerateAll tests that generate all writes the spec JSON and clients in one pass
+func TestGenerateAll(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "api")
+
+	err := generateAll(&integrationTestSpec, outputDir, []string{"go", "typescript"}, generator.Options{PackageName: "testclient"})
+	if err != nil {
+		t.Fatalf("generateAll() error = %v", err)
+	}
+
+	jsonData, err := os.ReadFile(filepath.Join(outputDir, "openapi.json"))
+	if err != nil {
+		t.Fatalf("Failed to read openapi.json: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		t.Fatalf("openapi.json is invalid: %v", err)
+	}
+	if _, ok := doc["paths"].(map[string]any)["/users/{id}"]; !ok {
+		t.Errorf("openapi.json should contain the /users/{id} path, got %s", jsonData)
+	}
+
+	goClient, err := os.ReadFile(filepath.Join(outputDir, "client.go"))
+	if err != nil {
+		t.Fatalf("Failed to read client.go: %v", err)
+	}
+	if !strings.Contains(string(goClient), "package testclient") || !strings.Contains(string(goClient), "func (c *Client) GetUserById") {
+		t.Errorf("client.go should contain the testclient package and GetUserById method")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "client.ts")); err != nil {
+		t.Errorf("client.ts was not created: %v", err)
+	}
+
+	if err := generateAll(&integrationTestSpec, outputDir, []string{"rust"}, generator.Options{}); err == nil {
+		t.Error("Expected an error for an unsupported language")
+	}
+}
+
 // TestIntegrationErrorHandling tests that error handling is properly integrated
 func TestIntegrationErrorHandling(t *testing.T) {
 	// Test with an invalid template file
@@ -305,3 +346,144 @@ func TestIntegrationComplexSpec(t *testing.T) {
 		t.Error("Generated client should contain response struct")
 	}
 }
+
+// TestVerifyLiveSpec tests comparing a served OpenAPI document against the generated one
+func TestVerifyLiveSpec(t *testing.T) {
+	expected, err := parser.SpecToOpenAPIJSON(&integrationTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to generate spec JSON: %v", err)
+	}
+
+	// Re-encode compactly so the live document differs only in formatting
+	var doc map[string]any
+	if err := json.Unmarshal(expected, &doc); err != nil {
+		t.Fatalf("Failed to decode spec JSON: %v", err)
+	}
+	matching, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode spec JSON: %v", err)
+	}
+
+	doc["info"].(map[string]any)["version"] = "2.0.0"
+	delete(doc["paths"].(map[string]any)["/users/{id}"].(map[string]any)["get"].(map[string]any), "summary")
+	mismatching, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to encode spec JSON: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []string
+	}{
+		{
+			name: "matching spec",
+			body: matching,
+		},
+		{
+			name: "mismatching spec",
+			body: mismatching,
+			expected: []string{
+				`$.info.version: expected "1.0.0", got "2.0.0"`,
+				`$.paths["/users/{id}"].get.summary: missing from live spec`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			actual, err := fetchOpenAPIJSON(server.Client(), server.URL+"/openapi.json")
+			if err != nil {
+				t.Fatalf("Failed to fetch live spec: %v", err)
+			}
+
+			differences, err := diffOpenAPIJSON(expected, actual)
+			if err != nil {
+				t.Fatalf("Failed to diff specs: %v", err)
+			}
+
+			if strings.Join(differences, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected differences:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(differences, "\n"))
+			}
+		})
+	}
+}
+
+// TestVerifyServedSpec tests that a server serves the document the CLI
+// generates from the source of its spec
+func TestVerifyServedSpec(t *testing.T) {
+	parsed, err := parser.ParseSpecFromFileWithPath("examples/spec/spec.go", "ExampleSpec", "../..")
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	expected, err := parser.SpecToOpenAPIJSON(&parsed)
+	if err != nil {
+		t.Fatalf("Failed to generate spec JSON: %v", err)
+	}
+
+	served := spec.ExampleSpec
+	served.OpenAPIJSONPath = "/openapi.json"
+	mux, err := gopenapi.NewServerMux(&served)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	actual, err := fetchOpenAPIJSON(server.Client(), server.URL+"/openapi.json")
+	if err != nil {
+		t.Fatalf("Failed to fetch live spec: %v", err)
+	}
+
+	differences, err := diffOpenAPIJSON(expected, actual)
+	if err != nil {
+		t.Fatalf("Failed to diff specs: %v", err)
+	}
+	if len(differences) > 0 {
+		t.Errorf("Expected the served spec to match the generated one, got:\n%s", strings.Join(differences, "\n"))
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("Expected the served spec to be byte for byte the generated one\nexpected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+// TestVerifyLiveSpecErrorStatus tests that a non-200 response from the live server is reported
+func TestVerifyLiveSpecErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	if _, err := fetchOpenAPIJSON(server.Client(), server.URL+"/openapi.json"); err == nil {
+		t.Error("Expected error for non-200 response")
+	}
+}
+
+func TestExplainOperation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := explainOperation(&buf, &integrationTestSpec, "getUserById"); err != nil {
+		t.Fatalf("explainOperation() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		"GET /users/{id}",
+		"Get a user by ID",
+		"id (path, integer, required) - User ID",
+		"200 User found",
+		"application/json",
+		"none (public)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	if err := explainOperation(&buf, &integrationTestSpec, "missing"); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
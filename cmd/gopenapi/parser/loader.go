@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/runpod/gopenapi"
+	"golang.org/x/tools/go/packages"
+)
+
+// Loader loads and type-checks the packages of spec files with go/packages,
+// which is expensive, once, and reuses them and the specs parsed from them
+// for later calls. Use one Loader per run, e.g. a CLI invocation generating the
+// spec and several clients, as it does not notice files changing on disk. A
+// Loader is safe for concurrent use.
+type Loader struct {
+	mu       sync.Mutex
+	packages map[string]*packages.Package
+	specs    map[string]gopenapi.Spec
+	// loads counts the calls of packages.Load
+	loads int
+}
+
+// NewLoader returns a Loader with empty caches
+func NewLoader() *Loader {
+	return &Loader{
+		packages: map[string]*packages.Package{},
+		specs:    map[string]gopenapi.Spec{},
+	}
+}
+
+// ParseSpec extracts the gopenapi.Spec variable varName from a Go file,
+// resolving its package from workingDir. Specs are cached, so the returned
+// spec shares its maps with those returned by earlier calls for the same
+// variable.
+func (l *Loader) ParseSpec(filename, varName, workingDir string) (gopenapi.Spec, error) {
+	pkg, absFilename, err := l.load(filename, workingDir)
+	if err != nil {
+		return gopenapi.Spec{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := absFilename + "\x00" + varName
+	if spec, ok := l.specs[key]; ok {
+		return spec, nil
+	}
+	spec, err := parseSpecFromPackage(pkg, filename, absFilename, varName)
+	if err != nil {
+		return gopenapi.Spec{}, err
+	}
+	l.specs[key] = spec
+	return spec, nil
+}
+
+// Load returns the type-checked package containing the Go file filename,
+// resolved from workingDir, loading it on first use
+func (l *Loader) Load(filename, workingDir string) (*packages.Package, error) {
+	pkg, _, err := l.load(filename, workingDir)
+	return pkg, err
+}
+
+// load returns the package containing filename and the absolute path of filename
+func (l *Loader) load(filename, workingDir string) (*packages.Package, string, error) {
+	// Ensure working directory is absolute
+	absWorkingDir, err := filepath.Abs(workingDir)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get absolute working directory: %w", err)
+	}
+
+	// Calculate the package pattern
+	// First, check if filename is already absolute
+	var absFilename string
+	if filepath.IsAbs(filename) {
+		absFilename = filename
+	} else {
+		// Make it absolute relative to working directory
+		absFilename = filepath.Join(absWorkingDir, filename)
+	}
+
+	// Get the directory of the file
+	fileDir := filepath.Dir(absFilename)
+
+	// Calculate the relative path from the working directory to the file directory
+	relPath, err := filepath.Rel(absWorkingDir, fileDir)
+	if err != nil {
+		// If we can't calculate relative path, try loading with the file directory
+		relPath = fileDir
+	}
+
+	// Convert to package pattern (use forward slashes even on Windows)
+	packagePattern := filepath.ToSlash(relPath)
+	if packagePattern == "" || packagePattern == "." {
+		packagePattern = "."
+	} else if !strings.HasPrefix(packagePattern, "./") && !strings.HasPrefix(packagePattern, "../") {
+		packagePattern = "./" + packagePattern
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := absWorkingDir + "\x00" + packagePattern
+	if pkg, ok := l.packages[key]; ok {
+		return pkg, absFilename, nil
+	}
+
+	// Load the package with type information
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedCompiledGoFiles |
+			packages.NeedImports |
+			packages.NeedDeps |
+			packages.NeedTypes |
+			packages.NeedTypesSizes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Dir:   absWorkingDir,
+		Fset:  token.NewFileSet(),
+		Tests: false,
+	}
+
+	// Load from the working directory to ensure all dependencies are available
+	l.loads++
+	pkgs, err := packages.Load(cfg, packagePattern)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load package: %w", err)
+	}
+
+	if len(pkgs) == 0 {
+		return nil, "", fmt.Errorf("no packages found")
+	}
+
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, "", fmt.Errorf("package has errors: %v", pkg.Errors)
+	}
+
+	l.packages[key] = pkg
+	return pkg, absFilename, nil
+}
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/runpod/gopenapi/cmd/gopenapi/generator"
+)
+
+func TestLoaderLoadsPackageOnce(t *testing.T) {
+	loader := NewLoader()
+
+	spec, err := loader.ParseSpec("testdata/builtspec/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	if _, err := SpecToOpenAPIJSON(&spec); err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+	outputDir := t.TempDir()
+	for _, lang := range []string{"go", "python", "typescript"} {
+		// Each language parses the spec again, as separate generate runs sharing
+		// the loader would
+		spec, err := loader.ParseSpec("testdata/builtspec/spec.go", "Spec", ".")
+		if err != nil {
+			t.Fatalf("ParseSpec() error = %v", err)
+		}
+		if err := generator.GenerateClientForLanguageWithOptions(&spec, lang, outputDir, generator.Options{PackageName: "client"}); err != nil {
+			t.Fatalf("Failed to generate %s client: %v", lang, err)
+		}
+	}
+
+	// Other variables of the package reuse it too
+	pointerSpec, err := loader.ParseSpec("testdata/builtspec/spec.go", "PointerSpec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	if pointerSpec.Info.Title != "Pointer Built API" {
+		t.Errorf("Expected the PointerSpec title, got %q", pointerSpec.Info.Title)
+	}
+	if _, err := loader.Load("testdata/builtspec/spec.go", "."); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if loader.loads != 1 {
+		t.Errorf("Expected the package to be loaded once, got %d loads", loader.loads)
+	}
+}
+
+func BenchmarkLoaderParseSpec(b *testing.B) {
+	loader := NewLoader()
+	for i := 0; i < b.N; i++ {
+		if _, err := loader.ParseSpec("testdata/builtspec/spec.go", "Spec", "."); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package parser
 
 import (
-	"encoding/json"
+	"bytes"
+	"encoding"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"os"
@@ -11,10 +13,13 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/runpod/gopenapi"
 	"golang.org/x/tools/go/packages"
+	"gopkg.in/yaml.v3"
 )
 
 // ParseSpecFromFile parses a Go file and extracts the specified gopenapi.Spec variable
@@ -26,76 +31,12 @@ func ParseSpecFromFile(filename, varName string) (gopenapi.Spec, error) {
 
 // ParseSpecFromFileWithPath parses a Go file and extracts the specified gopenapi.Spec variable using a specific working directory
 func ParseSpecFromFileWithPath(filename, varName, workingDir string) (gopenapi.Spec, error) {
-	return parseSpecViaPackages(filename, varName, workingDir)
+	return NewLoader().ParseSpec(filename, varName, workingDir)
 }
 
-// parseSpecViaPackages parses the Go file using go/packages and extracts the gopenapi.Spec
-func parseSpecViaPackages(filename, varName, workingDir string) (gopenapi.Spec, error) {
-	// Ensure working directory is absolute
-	absWorkingDir, err := filepath.Abs(workingDir)
-	if err != nil {
-		return gopenapi.Spec{}, fmt.Errorf("failed to get absolute working directory: %w", err)
-	}
-
-	// Load the package with type information
-	cfg := &packages.Config{
-		Mode: packages.NeedName |
-			packages.NeedFiles |
-			packages.NeedCompiledGoFiles |
-			packages.NeedImports |
-			packages.NeedDeps |
-			packages.NeedTypes |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedTypesInfo,
-		Dir:   absWorkingDir,
-		Fset:  token.NewFileSet(),
-		Tests: false,
-	}
-
-	// Calculate the package pattern
-	// First, check if filename is already absolute
-	var absFilename string
-	if filepath.IsAbs(filename) {
-		absFilename = filename
-	} else {
-		// Make it absolute relative to working directory
-		absFilename = filepath.Join(absWorkingDir, filename)
-	}
-
-	// Get the directory of the file
-	fileDir := filepath.Dir(absFilename)
-
-	// Calculate the relative path from the working directory to the file directory
-	relPath, err := filepath.Rel(absWorkingDir, fileDir)
-	if err != nil {
-		// If we can't calculate relative path, try loading with the file directory
-		relPath = fileDir
-	}
-
-	// Convert to package pattern (use forward slashes even on Windows)
-	packagePattern := filepath.ToSlash(relPath)
-	if packagePattern == "" || packagePattern == "." {
-		packagePattern = "."
-	} else if !strings.HasPrefix(packagePattern, "./") && !strings.HasPrefix(packagePattern, "../") {
-		packagePattern = "./" + packagePattern
-	}
-
-	// Load from the working directory to ensure all dependencies are available
-	pkgs, err := packages.Load(cfg, packagePattern)
-	if err != nil {
-		return gopenapi.Spec{}, fmt.Errorf("failed to load package: %w", err)
-	}
-
-	if len(pkgs) == 0 {
-		return gopenapi.Spec{}, fmt.Errorf("no packages found")
-	}
-
-	pkg := pkgs[0]
-	if len(pkg.Errors) > 0 {
-		return gopenapi.Spec{}, fmt.Errorf("package has errors: %v", pkg.Errors)
-	}
-
+// parseSpecFromPackage extracts the gopenapi.Spec variable varName declared in
+// absFilename, a file of pkg
+func parseSpecFromPackage(pkg *packages.Package, filename, absFilename, varName string) (gopenapi.Spec, error) {
 	// Find the file in the package
 	var targetFile *ast.File
 
@@ -116,36 +57,49 @@ func parseSpecViaPackages(filename, varName, workingDir string) (gopenapi.Spec,
 	}
 
 	// Find the variable declaration and extract its value
-	var specLiteral *ast.CompositeLit
-	found := false
+	var specValue ast.Expr
+	var specDoc *ast.CommentGroup
 
 	ast.Inspect(targetFile, func(n ast.Node) bool {
-		if genDecl, ok := n.(*ast.GenDecl); ok && genDecl.Tok == token.VAR {
-			for _, spec := range genDecl.Specs {
+		if specValue != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.GenDecl:
+			if node.Tok != token.VAR {
+				return true
+			}
+			for _, spec := range node.Specs {
 				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
 					for i, name := range valueSpec.Names {
-						if name.Name == varName {
-							if i < len(valueSpec.Values) {
-								if compLit, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
-									specLiteral = compLit
-									found = true
-									return false
-								}
+						if name.Name == varName && i < len(valueSpec.Values) {
+							specValue = valueSpec.Values[i]
+							specDoc = valueSpec.Doc
+							if specDoc == nil {
+								specDoc = node.Doc
 							}
+							return false
 						}
 					}
 				}
 			}
+		case *ast.AssignStmt:
+			// spec := gopenapi.Spec{...} inside a function
+			if value, ok := definedValue(node, varName); ok {
+				specValue = value
+				return false
+			}
 		}
 		return true
 	})
 
-	if !found {
+	if specValue == nil {
 		return gopenapi.Spec{}, fmt.Errorf("variable %s not found in file %s", varName, filename)
 	}
 
-	if specLiteral == nil {
-		return gopenapi.Spec{}, fmt.Errorf("variable %s is not a composite literal", varName)
+	specLiteral, ok := resolveSpecLiteral(specValue, pkg, map[*ast.FuncDecl]bool{})
+	if !ok {
+		return gopenapi.Spec{}, fmt.Errorf("variable %s is not a composite literal: specs must be declared as gopenapi.Spec{...}, &gopenapi.Spec{...}, or a call to a function in the same package that returns one", varName)
 	}
 
 	// Parse the composite literal into a gopenapi.Spec with type resolution
@@ -154,9 +108,140 @@ func parseSpecViaPackages(filename, varName, workingDir string) (gopenapi.Spec,
 		return gopenapi.Spec{}, fmt.Errorf("failed to parse spec from AST: %w", err)
 	}
 
+	// The doc comment of the spec variable describes the API unless Info sets a description
+	if spec.Info.Description == "" && specDoc != nil {
+		spec.Info.Description = strings.TrimSpace(specDoc.Text())
+	}
+
 	return spec, nil
 }
 
+// specCompositeLit returns the composite literal a spec variable is initialized
+// with, unwrapping parentheses and the & of specs declared as pointers
+func specCompositeLit(expr ast.Expr) (*ast.CompositeLit, bool) {
+	for {
+		switch e := expr.(type) {
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.UnaryExpr:
+			if e.Op != token.AND {
+				return nil, false
+			}
+			expr = e.X
+		case *ast.CompositeLit:
+			return e, true
+		default:
+			return nil, false
+		}
+	}
+}
+
+// definedValue returns the value assigned to name by a := statement
+func definedValue(assign *ast.AssignStmt, name string) (ast.Expr, bool) {
+	if assign.Tok != token.DEFINE || len(assign.Lhs) != len(assign.Rhs) {
+		return nil, false
+	}
+	for i, lhs := range assign.Lhs {
+		if ident, ok := lhs.(*ast.Ident); ok && ident.Name == name {
+			return assign.Rhs[i], true
+		}
+	}
+	return nil, false
+}
+
+// resolveSpecLiteral returns the composite literal expr evaluates to, following
+// calls to functions of pkg such as buildSpec() to the spec they return, either
+// directly or through a local variable. visiting guards against recursion.
+func resolveSpecLiteral(expr ast.Expr, pkg *packages.Package, visiting map[*ast.FuncDecl]bool) (*ast.CompositeLit, bool) {
+	if lit, ok := specCompositeLit(expr); ok {
+		return lit, true
+	}
+
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil, false
+	}
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return nil, false
+	}
+	decl := funcDecl(ident, pkg)
+	if decl == nil || decl.Body == nil || visiting[decl] {
+		return nil, false
+	}
+	visiting[decl] = true
+	defer delete(visiting, decl)
+
+	var result *ast.CompositeLit
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		if result != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			// Returns inside closures do not return from decl
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != 1 {
+				return true
+			}
+			value := node.Results[0]
+			if name, ok := ast.Unparen(value).(*ast.Ident); ok {
+				if local, ok := localValue(decl.Body, name.Name); ok {
+					value = local
+				}
+			}
+			if lit, ok := resolveSpecLiteral(value, pkg, visiting); ok {
+				result = lit
+			}
+		}
+		return true
+	})
+	return result, result != nil
+}
+
+// funcDecl returns the declaration of the package-level function ident refers to
+func funcDecl(ident *ast.Ident, pkg *packages.Package) *ast.FuncDecl {
+	obj, ok := pkg.TypesInfo.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && pkg.TypesInfo.Defs[fn.Name] == obj {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// localValue returns the value a variable declared in body is initialized with
+func localValue(body *ast.BlockStmt, name string) (ast.Expr, bool) {
+	var value ast.Expr
+	ast.Inspect(body, func(n ast.Node) bool {
+		if value != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.AssignStmt:
+			if v, ok := definedValue(node, name); ok {
+				value = v
+			}
+		case *ast.ValueSpec:
+			for i, ident := range node.Names {
+				if ident.Name == name && i < len(node.Values) {
+					value = node.Values[i]
+				}
+			}
+		}
+		return true
+	})
+	return value, value != nil
+}
+
 // parseSpecFromASTWithTypes converts an AST composite literal to a gopenapi.Spec with type resolution
 func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Spec, error) {
 	spec := gopenapi.Spec{}
@@ -167,7 +252,7 @@ func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (go
 				switch ident.Name {
 				case "OpenAPI":
 					if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-						spec.OpenAPI = strings.Trim(basicLit.Value, `"`)
+						spec.OpenAPI = stringLiteralValue(basicLit)
 					}
 				case "Info":
 					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
@@ -175,8 +260,9 @@ func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (go
 						for _, infoElt := range compLit.Elts {
 							if kv, ok := infoElt.(*ast.KeyValueExpr); ok {
 								if ident, ok := kv.Key.(*ast.Ident); ok {
-									if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-										value := strings.Trim(basicLit.Value, `"`)
+									// Constant expressions allow markdown descriptions to splice
+									// backticks into raw strings
+									if value, ok := parseStringFromAST(kv.Value, pkg); ok {
 										switch ident.Name {
 										case "Title":
 											info.Title = value
@@ -204,7 +290,7 @@ func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (go
 									if kv, ok := serverFieldElt.(*ast.KeyValueExpr); ok {
 										if ident, ok := kv.Key.(*ast.Ident); ok {
 											if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-												value := strings.Trim(basicLit.Value, `"`)
+												value := stringLiteralValue(basicLit)
 												switch ident.Name {
 												case "URL":
 													server.URL = value
@@ -228,6 +314,18 @@ func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (go
 						}
 						spec.Paths = paths
 					}
+				case "Components":
+					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+						components, err := parseComponentsFromASTWithTypes(compLit, pkg)
+						if err != nil {
+							return spec, fmt.Errorf("failed to parse Components: %w", err)
+						}
+						spec.Components = components
+					}
+				case "Security":
+					if security, ok := parseSecurityFromAST(kv.Value, pkg); ok {
+						spec.Security = security
+					}
 				}
 			}
 		}
@@ -236,6 +334,146 @@ func parseSpecFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (go
 	return spec, nil
 }
 
+// parseComponentsFromASTWithTypes parses the Schemas, SecuritySchemes and
+// Examples of gopenapi.Components from AST with type resolution
+func parseComponentsFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Components, error) {
+	components := gopenapi.Components{}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		entries, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		switch ident.Name {
+		case "Schemas":
+			components.Schemas = gopenapi.Schemas{}
+			for _, entry := range entries.Elts {
+				name, value, ok := namedCompositeLit(entry, pkg)
+				if !ok {
+					continue
+				}
+				schema, err := parseSchemaFromASTWithTypes(value, pkg)
+				if err != nil {
+					return components, fmt.Errorf("failed to parse schema %s: %w", name, err)
+				}
+				components.Schemas[name] = schema
+			}
+		case "SecuritySchemes":
+			components.SecuritySchemes = gopenapi.SecuritySchemes{}
+			for _, entry := range entries.Elts {
+				name, value, ok := namedCompositeLit(entry, pkg)
+				if !ok {
+					continue
+				}
+				components.SecuritySchemes[name] = parseSecuritySchemeFromAST(value, pkg)
+			}
+		case "Examples":
+			components.Examples = parseExamplesFromAST(entries, pkg)
+		}
+	}
+
+	return components, nil
+}
+
+// namedCompositeLit returns the key and value of a map entry such as
+// "User": {Type: ...}, whose value may omit its type or be a pointer
+func namedCompositeLit(entry ast.Expr, pkg *packages.Package) (string, *ast.CompositeLit, bool) {
+	kv, ok := entry.(*ast.KeyValueExpr)
+	if !ok {
+		return "", nil, false
+	}
+	name, ok := parseStringFromAST(kv.Key, pkg)
+	if !ok {
+		return "", nil, false
+	}
+	value, ok := specCompositeLit(kv.Value)
+	return name, value, ok
+}
+
+// parseSecuritySchemeFromAST parses the documented fields of a
+// gopenapi.SecurityScheme; its Handler is not needed to describe the API
+func parseSecuritySchemeFromAST(lit *ast.CompositeLit, pkg *packages.Package) gopenapi.SecurityScheme {
+	scheme := gopenapi.SecurityScheme{}
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		value, ok := parseStringFromAST(kv.Value, pkg)
+		if !ok {
+			continue
+		}
+		switch ident.Name {
+		case "Type":
+			scheme.Type = gopenapi.SecuritySchemeType(value)
+		case "Scheme":
+			scheme.Scheme = gopenapi.Scheme(value)
+		case "Name":
+			scheme.Name = value
+		case "In":
+			scheme.In = gopenapi.In(value)
+		}
+	}
+
+	return scheme
+}
+
+// parseSecurityFromAST parses security requirements such as
+// []gopenapi.Security{{"apiKey": []string{}}}, or gopenapi.NoSecurity
+func parseSecurityFromAST(expr ast.Expr, pkg *packages.Package) ([]gopenapi.Security, bool) {
+	if selector, ok := expr.(*ast.SelectorExpr); ok && selector.Sel.Name == "NoSecurity" {
+		return gopenapi.NoSecurity, true
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+
+	security := []gopenapi.Security{}
+	for _, elt := range lit.Elts {
+		requirementLit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		requirement := gopenapi.Security{}
+		for _, entry := range requirementLit.Elts {
+			kv, ok := entry.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			name, ok := parseStringFromAST(kv.Key, pkg)
+			if !ok {
+				continue
+			}
+			scopes := []string{}
+			if values, ok := parseConstantFromAST(kv.Value, pkg); ok {
+				values, _ := values.([]any)
+				for _, value := range values {
+					if scope, ok := value.(string); ok {
+						scopes = append(scopes, scope)
+					}
+				}
+			}
+			requirement[name] = scopes
+		}
+		security = append(security, requirement)
+	}
+	return security, true
+}
+
 // parsePathsFromASTWithTypes parses gopenapi.Paths from AST with type resolution
 func parsePathsFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Paths, error) {
 	paths := make(gopenapi.Paths)
@@ -245,7 +483,7 @@ func parsePathsFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (g
 			// Get the path string
 			var pathStr string
 			if basicLit, ok := kv.Key.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-				pathStr = strings.Trim(basicLit.Value, `"`)
+				pathStr = stringLiteralValue(basicLit)
 			}
 
 			// Parse the path item
@@ -269,12 +507,21 @@ func parsePathItemFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 	for _, elt := range lit.Elts {
 		if kv, ok := elt.(*ast.KeyValueExpr); ok {
 			if ident, ok := kv.Key.(*ast.Ident); ok {
-				if unaryExpr, ok := kv.Value.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
+				if compLit, ok := kv.Value.(*ast.CompositeLit); ok && ident.Name == "Parameters" {
+					params, err := parseParametersFromASTWithTypes(compLit, pkg)
+					if err != nil {
+						return pathItem, fmt.Errorf("failed to parse path parameters: %w", err)
+					}
+					pathItem.Parameters = params
+				} else if unaryExpr, ok := kv.Value.(*ast.UnaryExpr); ok && unaryExpr.Op == token.AND {
 					if compLit, ok := unaryExpr.X.(*ast.CompositeLit); ok {
 						operation, err := parseOperationFromASTWithTypes(compLit, pkg)
 						if err != nil {
 							return pathItem, fmt.Errorf("failed to parse operation %s: %w", ident.Name, err)
 						}
+						if operation.Description == "" {
+							operation.Description = docComment(kv, pkg)
+						}
 
 						switch strings.ToUpper(ident.Name) {
 						case "GET":
@@ -301,6 +548,25 @@ func parsePathItemFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 	return pathItem, nil
 }
 
+// docComment returns the text of the comment group directly above node and
+// aligned with it, e.g. a comment on an operation field of a gopenapi.Path.
+// Trailing comments of the previous line are not doc comments and are ignored.
+func docComment(node ast.Node, pkg *packages.Package) string {
+	pos := pkg.Fset.Position(node.Pos())
+	for _, file := range pkg.Syntax {
+		if node.Pos() < file.Pos() || node.Pos() > file.End() {
+			continue
+		}
+		for _, group := range file.Comments {
+			start, end := pkg.Fset.Position(group.Pos()), pkg.Fset.Position(group.End())
+			if end.Line == pos.Line-1 && start.Column == pos.Column {
+				return strings.TrimSpace(group.Text())
+			}
+		}
+	}
+	return ""
+}
+
 // parseOperationFromASTWithTypes parses gopenapi.Operation from AST with type resolution
 func parseOperationFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Operation, error) {
 	operation := gopenapi.Operation{}
@@ -311,7 +577,7 @@ func parseOperationFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package
 				switch ident.Name {
 				case "OperationId", "Summary", "Description":
 					if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-						value := strings.Trim(basicLit.Value, `"`)
+						value := stringLiteralValue(basicLit)
 						switch ident.Name {
 						case "OperationId":
 							operation.OperationId = value
@@ -345,6 +611,34 @@ func parseOperationFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package
 						}
 						operation.RequestBody = requestBody
 					}
+				case "CodeSamples":
+					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+						operation.CodeSamples = parseCodeSamplesFromAST(compLit)
+					}
+				case "Tags":
+					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+						for _, tagElt := range compLit.Elts {
+							if basicLit, ok := tagElt.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+								if tag, err := strconv.Unquote(basicLit.Value); err == nil {
+									operation.Tags = append(operation.Tags, tag)
+								}
+							}
+						}
+					}
+				case "Security":
+					if security, ok := parseSecurityFromAST(kv.Value, pkg); ok {
+						operation.Security = security
+					}
+				case "Timeout":
+					timeout, ok := parseDurationFromAST(kv.Value, pkg)
+					if !ok {
+						return operation, fmt.Errorf("failed to parse timeout: expected a constant duration")
+					}
+					operation.Timeout = timeout
+				case "Paginated":
+					if ident, ok := kv.Value.(*ast.Ident); ok {
+						operation.Paginated = ident.Name == "true"
+					}
 				case "Handler":
 					// Skip handler parsing for now as it's complex and not needed for client generation
 					operation.Handler = nil
@@ -356,6 +650,272 @@ func parseOperationFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package
 	return operation, nil
 }
 
+// stringLiteralValue returns the value of a string literal. Literals are
+// unquoted rather than trimmed so that raw multi-line strings and escapes such
+// as \n in descriptions reach the spec verbatim.
+func stringLiteralValue(lit *ast.BasicLit) string {
+	if value, err := strconv.Unquote(lit.Value); err == nil {
+		return value
+	}
+	return strings.Trim(lit.Value, "`\"")
+}
+
+// parseDurationFromAST evaluates a constant time.Duration expression such as
+// 30 * time.Second using the type checker
+func parseDurationFromAST(expr ast.Expr, pkg *packages.Package) (time.Duration, bool) {
+	if pkg.TypesInfo == nil {
+		return 0, false
+	}
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return 0, false
+	}
+	nanos, ok := constant.Int64Val(constant.ToInt(tv.Value))
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(nanos), true
+}
+
+// parseConstantFromAST evaluates a constant expression such as 20, "asc" or
+// -1.5 using the type checker. Composite literals of constants, such as
+// []string{"a", "b"}, evaluate to a slice of their elements.
+func parseConstantFromAST(expr ast.Expr, pkg *packages.Package) (any, bool) {
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		values := []any{}
+		for _, elt := range lit.Elts {
+			value, ok := parseConstantFromAST(elt, pkg)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		}
+		return values, true
+	}
+	if pkg.TypesInfo == nil {
+		return nil, false
+	}
+	tv, ok := pkg.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil {
+		return nil, false
+	}
+	switch tv.Value.Kind() {
+	case constant.Bool:
+		return constant.BoolVal(tv.Value), true
+	case constant.String:
+		return constant.StringVal(tv.Value), true
+	case constant.Int:
+		if value, ok := constant.Int64Val(tv.Value); ok {
+			return value, true
+		}
+	case constant.Float:
+		value, _ := constant.Float64Val(tv.Value)
+		return value, true
+	}
+	return nil, false
+}
+
+// parseStringFromAST evaluates a constant string expression, such as a
+// literal or a concatenation of literals and constants
+func parseStringFromAST(expr ast.Expr, pkg *packages.Package) (string, bool) {
+	if basicLit, ok := expr.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+		return stringLiteralValue(basicLit), true
+	}
+	value, ok := parseConstantFromAST(expr, pkg)
+	if !ok {
+		return "", false
+	}
+	s, ok := value.(string)
+	return s, ok
+}
+
+// parseNumberFromAST evaluates a constant numeric expression such as 1, 0.5 or -10
+func parseNumberFromAST(expr ast.Expr, pkg *packages.Package) (float64, bool) {
+	value, ok := parseConstantFromAST(expr, pkg)
+	if !ok {
+		return 0, false
+	}
+	switch n := value.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// parseExamplesFromAST parses gopenapi.Examples from AST. Examples whose value
+// cannot be evaluated keep their other fields.
+func parseExamplesFromAST(lit *ast.CompositeLit, pkg *packages.Package) gopenapi.Examples {
+	examples := gopenapi.Examples{}
+
+	for _, entry := range lit.Elts {
+		name, exampleLit, ok := namedCompositeLit(entry, pkg)
+		if !ok {
+			continue
+		}
+		example := gopenapi.Example{}
+		for _, elt := range exampleLit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			ident, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if ident.Name == "Value" {
+				example.Value, _ = parseExampleValueFromAST(kv.Value, pkg)
+				continue
+			}
+			value, ok := parseStringFromAST(kv.Value, pkg)
+			if !ok {
+				continue
+			}
+			switch ident.Name {
+			case "Ref":
+				example.Ref = value
+			case "Summary":
+				example.Summary = value
+			case "Description":
+				example.Description = value
+			case "ExternalValue":
+				example.ExternalValue = value
+			}
+		}
+		examples[name] = example
+	}
+
+	return examples
+}
+
+// parseExampleValueFromAST evaluates the value of an example as the JSON value
+// it encodes to: constants, and composite literals of them such as
+// map[string]any{"name": "Alice"}, []string{"a"} or User{Name: "Alice"}, whose
+// fields are named by their json tags
+func parseExampleValueFromAST(expr ast.Expr, pkg *packages.Package) (any, bool) {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return parseConstantFromAST(expr, pkg)
+	}
+
+	var structType *types.Struct
+	if pkg.TypesInfo != nil {
+		if t := pkg.TypesInfo.TypeOf(lit); t != nil {
+			structType, _ = t.Underlying().(*types.Struct)
+		}
+	}
+
+	if len(lit.Elts) == 0 || !isKeyValueExpr(lit.Elts[0]) {
+		if structType != nil && len(lit.Elts) > 0 {
+			// Positional struct literals are not supported
+			return nil, false
+		}
+		if structType != nil {
+			return map[string]any{}, true
+		}
+		values := []any{}
+		for _, elt := range lit.Elts {
+			value, ok := parseExampleValueFromAST(elt, pkg)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, value)
+		}
+		return values, true
+	}
+
+	object := map[string]any{}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, false
+		}
+		value, ok := parseExampleValueFromAST(kv.Value, pkg)
+		if !ok {
+			return nil, false
+		}
+		if structType == nil {
+			key, ok := parseConstantFromAST(kv.Key, pkg)
+			if !ok {
+				return nil, false
+			}
+			object[fmt.Sprint(key)] = value
+			continue
+		}
+		ident, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			return nil, false
+		}
+		for i := range structType.NumFields() {
+			field := structType.Field(i)
+			if field.Name() != ident.Name {
+				continue
+			}
+			tag := reflect.StructTag(structType.Tag(i))
+			if tag.Get("json") == "-" {
+				break
+			}
+			name, _ := jsonName(tag, field.Name())
+			object[name] = value
+		}
+	}
+	return object, true
+}
+
+// isKeyValueExpr reports whether expr is a key: value element of a composite literal
+func isKeyValueExpr(expr ast.Expr) bool {
+	_, ok := expr.(*ast.KeyValueExpr)
+	return ok
+}
+
+// parseCodeSamplesFromAST parses gopenapi.CodeSamples from AST. Sources are
+// usually multi-line raw strings, so literals are unquoted rather than trimmed.
+func parseCodeSamplesFromAST(lit *ast.CompositeLit) gopenapi.CodeSamples {
+	var samples gopenapi.CodeSamples
+
+	for _, elt := range lit.Elts {
+		compLit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		sample := gopenapi.CodeSample{}
+		for _, sampleElt := range compLit.Elts {
+			kv, ok := sampleElt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			ident, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			basicLit, ok := kv.Value.(*ast.BasicLit)
+			if !ok || basicLit.Kind != token.STRING {
+				continue
+			}
+			value, err := strconv.Unquote(basicLit.Value)
+			if err != nil {
+				continue
+			}
+			switch ident.Name {
+			case "Lang":
+				sample.Lang = value
+			case "Label":
+				sample.Label = value
+			case "Source":
+				sample.Source = value
+			}
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
 // parseParametersFromASTWithTypes parses gopenapi.Parameters from AST with type resolution
 func parseParametersFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Parameters, error) {
 	var params gopenapi.Parameters
@@ -369,7 +929,7 @@ func parseParametersFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Packag
 						switch ident.Name {
 						case "Name", "Description":
 							if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-								value := strings.Trim(basicLit.Value, `"`)
+								value := stringLiteralValue(basicLit)
 								switch ident.Name {
 								case "Name":
 									param.Name = value
@@ -381,6 +941,13 @@ func parseParametersFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Packag
 							if ident, ok := kv.Value.(*ast.Ident); ok {
 								param.Required = ident.Name == "true"
 							}
+						case "Explode":
+							// Parse gopenapi.Ptr(true) or gopenapi.Ptr(false)
+							if call, ok := kv.Value.(*ast.CallExpr); ok && len(call.Args) == 1 {
+								if ident, ok := call.Args[0].(*ast.Ident); ok {
+									param.Explode = gopenapi.Ptr(ident.Name == "true")
+								}
+							}
 						case "In":
 							// Parse parameter location (path, query, header)
 							if selectorExpr, ok := kv.Value.(*ast.SelectorExpr); ok {
@@ -402,6 +969,10 @@ func parseParametersFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Packag
 								}
 								param.Schema = schema
 							}
+						case "Examples":
+							if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+								param.Examples = parseExamplesFromAST(compLit, pkg)
+							}
 						}
 					}
 				}
@@ -433,21 +1004,19 @@ func parseSchemaFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (
 						schema.Type = gopenapi.Boolean
 					case "Array":
 						schema.Type = gopenapi.Array
+					case "File":
+						schema.Type = gopenapi.File
 					}
 				} else if callExpr, ok := kv.Value.(*ast.CallExpr); ok {
 					// Handle different types of call expressions
-					if selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
-						if selectorExpr.Sel.Name == "TypeOf" {
-							// This is a reflect.TypeOf() call
-							if len(callExpr.Args) > 0 {
-								// Get the type from the argument to TypeOf
-								resolvedType := resolveTypeFromAST(callExpr.Args[0], pkg)
-								if resolvedType != nil {
-									schema.Type = resolvedType
-								} else {
-									fmt.Fprintf(os.Stderr, "Warning: Could not resolve type for reflect.TypeOf(), falling back to interface{}\n")
-									schema.Type = reflect.TypeOf((*interface{})(nil)).Elem()
-								}
+					if _, ok := callExpr.Fun.(*ast.SelectorExpr); ok {
+						// This is a reflect.TypeOf() call, possibly followed by .Elem()
+						if resolvedType, ok := resolveReflectTypeCall(callExpr, pkg); ok {
+							if resolvedType != nil {
+								schema.Type = resolvedType
+							} else {
+								fmt.Fprintf(os.Stderr, "Warning: Could not resolve type for reflect.TypeOf(), falling back to interface{}\n")
+								schema.Type = reflect.TypeOf((*interface{})(nil)).Elem()
 							}
 						}
 					} else if indexExpr, ok := callExpr.Fun.(*ast.IndexExpr); ok {
@@ -462,6 +1031,11 @@ func parseSchemaFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (
 							} else if selector, ok := indexExpr.Index.(*ast.SelectorExpr); ok {
 								// Imported type like Object[gopenapi.Schema]()
 								resolvedType = lookupImportedType(selector, pkg)
+							} else {
+								// Type literals like Object[[]User]() or anonymous
+								// structs like Object[struct{ Name string }](),
+								// built with type resolution
+								resolvedType = resolveTypeFromAST(indexExpr.Index, pkg)
 							}
 
 							if resolvedType != nil {
@@ -473,6 +1047,95 @@ func parseSchemaFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (
 						}
 					}
 				}
+			} else if ok && ident.Name == "Format" {
+				if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+					if value, err := strconv.Unquote(basicLit.Value); err == nil {
+						schema.Format = value
+					}
+				} else if selectorExpr, ok := kv.Value.(*ast.SelectorExpr); ok && selectorExpr.Sel.Name == "FormatPassword" {
+					schema.Format = gopenapi.FormatPassword
+				}
+			} else if ok && ident.Name == "Enum" {
+				if values, ok := parseConstantFromAST(kv.Value, pkg); ok {
+					schema.Enum, _ = values.([]any)
+				}
+			} else if ok && ident.Name == "Default" {
+				if value, ok := parseConstantFromAST(kv.Value, pkg); ok {
+					schema.Default = value
+				}
+			} else if ok && (ident.Name == "Minimum" || ident.Name == "Maximum") {
+				// Parse gopenapi.Ptr(1.0)
+				if call, ok := kv.Value.(*ast.CallExpr); ok && len(call.Args) == 1 {
+					if bound, ok := parseNumberFromAST(call.Args[0], pkg); ok {
+						if ident.Name == "Minimum" {
+							schema.Minimum = &bound
+						} else {
+							schema.Maximum = &bound
+						}
+					}
+				}
+			} else if ok && (ident.Name == "MinLength" || ident.Name == "MaxLength") {
+				// Parse gopenapi.Ptr(3)
+				if call, ok := kv.Value.(*ast.CallExpr); ok && len(call.Args) == 1 {
+					if length, ok := parseConstantFromAST(call.Args[0], pkg); ok {
+						if n, ok := length.(int64); ok {
+							if ident.Name == "MinLength" {
+								schema.MinLength = gopenapi.Ptr(int(n))
+							} else {
+								schema.MaxLength = gopenapi.Ptr(int(n))
+							}
+						}
+					}
+				}
+			} else if ok && ident.Name == "Pattern" {
+				if pattern, ok := parseStringFromAST(kv.Value, pkg); ok {
+					schema.Pattern = pattern
+				}
+			} else if ok && ident.Name == "Ref" {
+				if ref, ok := parseStringFromAST(kv.Value, pkg); ok {
+					schema.Ref = ref
+				}
+			} else if ok && (ident.Name == "ContentMediaType" || ident.Name == "ContentEncoding") {
+				if value, ok := parseStringFromAST(kv.Value, pkg); ok {
+					if ident.Name == "ContentMediaType" {
+						schema.ContentMediaType = value
+					} else {
+						schema.ContentEncoding = value
+					}
+				}
+			} else if ok && (ident.Name == "ExclusiveMinimum" || ident.Name == "ExclusiveMaximum") {
+				if valueIdent, ok := kv.Value.(*ast.Ident); ok {
+					if ident.Name == "ExclusiveMinimum" {
+						schema.ExclusiveMinimum = valueIdent.Name == "true"
+					} else {
+						schema.ExclusiveMaximum = valueIdent.Name == "true"
+					}
+				}
+			} else if ok && ident.Name == "PrefixItems" {
+				if itemsLit, ok := kv.Value.(*ast.CompositeLit); ok {
+					for _, itemElt := range itemsLit.Elts {
+						itemLit, ok := itemElt.(*ast.CompositeLit)
+						if !ok {
+							continue
+						}
+						itemSchema, err := parseSchemaFromASTWithTypes(itemLit, pkg)
+						if err != nil {
+							return schema, err
+						}
+						schema.PrefixItems = append(schema.PrefixItems, itemSchema)
+					}
+				}
+			} else if ok && ident.Name == "Items" {
+				// Items is a *Schema, written as &gopenapi.Schema{...}
+				if unary, ok := kv.Value.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+					if itemsLit, ok := unary.X.(*ast.CompositeLit); ok {
+						itemsSchema, err := parseSchemaFromASTWithTypes(itemsLit, pkg)
+						if err != nil {
+							return schema, err
+						}
+						schema.Items = &itemsSchema
+					}
+				}
 			}
 		}
 	}
@@ -480,6 +1143,40 @@ func parseSchemaFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (
 	return schema, nil
 }
 
+// resolveReflectTypeCall resolves the type of reflect.TypeOf(x) calls, such as
+// reflect.TypeOf(User{}), reflect.TypeOf([]User{}) or reflect.TypeOf((*User)(nil)),
+// optionally followed by .Elem() as in reflect.TypeOf((*User)(nil)).Elem().
+// It reports false for other calls and a nil type for arguments it cannot
+// resolve.
+func resolveReflectTypeCall(call *ast.CallExpr, pkg *packages.Package) (reflect.Type, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, false
+	}
+	switch selector.Sel.Name {
+	case "TypeOf":
+		if len(call.Args) != 1 {
+			return nil, false
+		}
+		return resolveTypeFromAST(call.Args[0], pkg), true
+	case "Elem":
+		inner, ok := ast.Unparen(selector.X).(*ast.CallExpr)
+		if !ok || len(call.Args) != 0 {
+			return nil, false
+		}
+		t, ok := resolveReflectTypeCall(inner, pkg)
+		if !ok || t == nil {
+			return nil, ok
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			return t.Elem(), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
 // resolveTypeFromAST resolves a type from AST using package type information
 func resolveTypeFromAST(expr ast.Expr, pkg *packages.Package) reflect.Type {
 	if pkg.TypesInfo == nil {
@@ -549,11 +1246,27 @@ func getTypeNameFromExpr(expr ast.Expr) string {
 			return pkg.Name + "." + e.Sel.Name
 		}
 		return e.Sel.Name
+	case *ast.StructType:
+		return "struct{...}"
 	default:
 		return "unknown"
 	}
 }
 
+// structTypeNames maps the struct types built for named Go types, which
+// reflect.StructOf leaves anonymous, to the name of the first type built
+var structTypeNames sync.Map
+
+// StructTypeName returns the Go name of a struct type built from a named type
+// of a parsed spec, e.g. User, or t.Name() for other types. Generators use it
+// to name the models of types parsed from source.
+func StructTypeName(t reflect.Type) string {
+	if name, ok := structTypeNames.Load(t); ok {
+		return name.(string)
+	}
+	return t.Name()
+}
+
 // createReflectTypeFromGoTypes creates a reflect.Type from go/types.Type
 func createReflectTypeFromGoTypes(t types.Type) reflect.Type {
 	processing := make(map[types.Type]bool)
@@ -599,11 +1312,18 @@ func createReflectTypeFromGoTypesWithProcessing(t types.Type, processing map[typ
 		switch underlyingType := underlying.(type) {
 		case *types.Struct:
 			// Complex struct type - create a struct type
-			return createStructTypeWithProcessing(underlyingType, processing)
+			structType := createStructTypeWithProcessing(underlyingType, processing)
+			if typeName != "" {
+				structTypeNames.LoadOrStore(structType, typeName)
+			}
+			return structType
 		case *types.Basic:
 			// Named type with primitive underlying type (like type ID string)
 			// Return the underlying primitive type
 			return getReflectTypeFromGoTypesTypeWithProcessing(underlyingType, processing)
+		case *types.Interface:
+			// Named interfaces, including error, which has no package
+			return interfaceReflectType(underlyingType)
 		default:
 			// For other underlying types (slices, arrays, etc.), use the underlying type
 			return getReflectTypeFromGoTypesTypeWithProcessing(underlying, processing)
@@ -630,6 +1350,8 @@ func getActualReflectType(pkgPath, typeName string) reflect.Type {
 		return reflect.TypeOf((*time.Time)(nil)).Elem()
 	case "time.Duration":
 		return reflect.TypeOf((*time.Duration)(nil)).Elem()
+	case "io.Reader":
+		return gopenapi.File
 	// Add more standard library types
 	case "net/url.URL":
 		return reflect.TypeOf((*struct{})(nil)).Elem() // Placeholder for now
@@ -648,17 +1370,16 @@ func createStructType(structType *types.Struct) reflect.Type {
 	return createStructTypeWithProcessing(structType, processing)
 }
 
-// createStructTypeWithProcessing creates a reflect.Type for a struct from go/types.Struct with cycle detection
+// createStructTypeWithProcessing creates a reflect.Type for a struct from go/types.Struct with cycle detection.
+// The fields of embedded structs are promoted into the created struct, which
+// holds the exported fields encoding/json would serialize.
 func createStructTypeWithProcessing(structType *types.Struct, processing map[types.Type]bool) reflect.Type {
-	numFields := structType.NumFields()
-	fields := make([]reflect.StructField, numFields)
+	jsonFields := dominantFields(structJSONFields(structType, 0, map[*types.Struct]bool{}))
+	fields := make([]reflect.StructField, len(jsonFields))
+	names := make(map[string]bool, len(jsonFields))
 
-	for i := range numFields {
-		field := structType.Field(i)
-		tag := ""
-		if structType.Tag(i) != "" {
-			tag = structType.Tag(i)
-		}
+	for i, jsonField := range jsonFields {
+		field := jsonField.field
 
 		// Use the recursive type resolution to properly handle named types
 		fieldType := createReflectTypeFromGoTypesWithProcessing(field.Type(), processing)
@@ -671,16 +1392,139 @@ func createStructTypeWithProcessing(structType *types.Struct, processing map[typ
 			}
 		}
 
+		// Promoted fields may share the Go name of a field with another JSON
+		// name, e.g. an ID tagged "parentId" next to an embedded ID tagged "id"
+		name, tag := field.Name(), jsonField.tag
+		if names[name] {
+			for n := 2; names[name]; n++ {
+				name = fmt.Sprintf("%s%d", field.Name(), n)
+			}
+			tag = withJSONName(tag, jsonField.name)
+		}
+		names[name] = true
+
 		fields[i] = reflect.StructField{
-			Name: field.Name(),
+			Name: name,
 			Type: fieldType,
-			Tag:  reflect.StructTag(tag),
+			Tag:  tag,
 		}
 	}
 
 	return reflect.StructOf(fields)
 }
 
+// jsonField is a field of a struct, or of a struct embedded in it at depth,
+// under the name encoding/json serializes it with
+type jsonField struct {
+	name string
+	// tagged reports whether the json tag sets the name
+	tagged bool
+	depth  int
+	tag    reflect.StructTag
+	field  *types.Var
+}
+
+// jsonName returns the name of a field from its json tag, falling back to the
+// field name, and whether the tag sets it. A "-," tag names the field "-".
+func jsonName(tag reflect.StructTag, fieldName string) (string, bool) {
+	if name, _, _ := strings.Cut(tag.Get("json"), ","); name != "" {
+		return name, true
+	}
+	return fieldName, false
+}
+
+// withJSONName returns tag with its json name set to name, keeping its options
+func withJSONName(tag reflect.StructTag, name string) reflect.StructTag {
+	value, ok := tag.Lookup("json")
+	if !ok {
+		return reflect.StructTag(strings.TrimSpace(fmt.Sprintf("json:%q %s", name, tag)))
+	}
+	_, options, _ := strings.Cut(value, ",")
+	if options != "" {
+		name += "," + options
+	}
+	return reflect.StructTag(strings.Replace(string(tag), fmt.Sprintf("json:%q", value), fmt.Sprintf("json:%q", name), 1))
+}
+
+// structJSONFields lists the exported fields of structType not tagged
+// json:"-", promoting the fields of embedded structs, or pointers to them, that
+// have no json name, as encoding/json does. Embedded structs being listed by
+// visiting are skipped.
+func structJSONFields(structType *types.Struct, depth int, visiting map[*types.Struct]bool) []jsonField {
+	if visiting[structType] {
+		return nil
+	}
+	visiting[structType] = true
+	defer delete(visiting, structType)
+
+	var fields []jsonField
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		tag := reflect.StructTag(structType.Tag(i))
+		// Fields tagged json:"-", embedded or not, are never serialized
+		if tag.Get("json") == "-" {
+			continue
+		}
+		name, tagged := jsonName(tag, field.Name())
+		if field.Embedded() && !tagged {
+			embedded := field.Type()
+			if pointer, ok := embedded.Underlying().(*types.Pointer); ok {
+				embedded = pointer.Elem()
+			}
+			if embeddedStruct, ok := embedded.Underlying().(*types.Struct); ok {
+				fields = append(fields, structJSONFields(embeddedStruct, depth+1, visiting)...)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		fields = append(fields, jsonField{name: name, tagged: tagged, depth: depth, tag: tag, field: field})
+	}
+	return fields
+}
+
+// dominantFields applies encoding/json's rules to fields sharing a name, as
+// gopenapi.JSONFields does for reflect types: the least nested field wins,
+// then the only tagged one among the least nested, and otherwise the name is
+// dropped. Names keep the order they first appear in.
+func dominantFields(fields []jsonField) []jsonField {
+	byName := make(map[string][]jsonField)
+	var names []string
+	for _, field := range fields {
+		if _, ok := byName[field.name]; !ok {
+			names = append(names, field.name)
+		}
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var dominant []jsonField
+	for _, name := range names {
+		candidates := byName[name]
+		depth := candidates[0].depth
+		for _, field := range candidates {
+			depth = min(depth, field.depth)
+		}
+		var shallowest, tagged []jsonField
+		for _, field := range candidates {
+			if field.depth != depth {
+				continue
+			}
+			shallowest = append(shallowest, field)
+			if field.tagged {
+				tagged = append(tagged, field)
+			}
+		}
+		switch {
+		case len(shallowest) == 1:
+			dominant = append(dominant, shallowest[0])
+		case len(tagged) == 1:
+			dominant = append(dominant, tagged[0])
+		}
+	}
+	return dominant
+}
+
 // getReflectTypeFromGoTypesType converts basic go/types.Type to reflect.Type
 func getReflectTypeFromGoTypesType(t types.Type) reflect.Type {
 	processing := make(map[types.Type]bool)
@@ -727,6 +1571,9 @@ func getReflectTypeFromGoTypesTypeWithProcessing(t types.Type, processing map[ty
 		// Use recursive resolution for slice elements
 		elemType := createReflectTypeFromGoTypesWithProcessing(typ.Elem(), processing)
 		return reflect.SliceOf(elemType)
+	case *types.Array:
+		elemType := createReflectTypeFromGoTypesWithProcessing(typ.Elem(), processing)
+		return reflect.ArrayOf(int(typ.Len()), elemType)
 	case *types.Pointer:
 		// Use recursive resolution for pointer elements
 		elemType := createReflectTypeFromGoTypesWithProcessing(typ.Elem(), processing)
@@ -739,11 +1586,42 @@ func getReflectTypeFromGoTypesTypeWithProcessing(t types.Type, processing map[ty
 	case *types.Named:
 		// This should be handled by createReflectTypeFromGoTypes, but add as fallback
 		return createReflectTypeFromGoTypesWithProcessing(typ, processing)
+	case *types.Interface:
+		return interfaceReflectType(typ)
 	default:
 		return reflect.TypeOf((*interface{})(nil)).Elem()
 	}
 }
 
+var (
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+	// textMarshalerInterface is encoding.TextMarshaler as a go/types interface
+	textMarshalerInterface = types.NewInterfaceType([]*types.Func{
+		types.NewFunc(token.NoPos, nil, "MarshalText", types.NewSignatureType(nil, nil, nil, nil,
+			types.NewTuple(
+				types.NewParam(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])),
+				types.NewParam(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+			), false)),
+	}, nil).Complete()
+)
+
+// interfaceReflectType returns the reflect.Type standing in for an interface
+// type. Interfaces embedding error map to error and text marshalers to
+// encoding.TextMarshaler, both described as strings; any other interface can
+// hold values of any type and maps to interface{}.
+func interfaceReflectType(iface *types.Interface) reflect.Type {
+	switch {
+	case types.Implements(iface, errorInterface):
+		return errorType
+	case types.Implements(iface, textMarshalerInterface):
+		return textMarshalerType
+	}
+	return reflect.TypeOf((*interface{})(nil)).Elem()
+}
+
 // parseResponsesFromASTWithTypes parses gopenapi.Responses from AST with type resolution
 func parseResponsesFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Responses, error) {
 	responses := make(gopenapi.Responses)
@@ -772,15 +1650,9 @@ func parseResponsesFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package
 	return responses, nil
 }
 
-// parseResponseFromASTWithTypes parses response struct from AST with type resolution
-func parseResponseFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (struct {
-	Description string           `json:"description,omitempty"`
-	Content     gopenapi.Content `json:"content,omitempty"`
-}, error) {
-	response := struct {
-		Description string           `json:"description,omitempty"`
-		Content     gopenapi.Content `json:"content,omitempty"`
-	}{}
+// parseResponseFromASTWithTypes parses gopenapi.Response from AST with type resolution
+func parseResponseFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Response, error) {
+	response := gopenapi.Response{}
 
 	for _, elt := range lit.Elts {
 		if kv, ok := elt.(*ast.KeyValueExpr); ok {
@@ -788,7 +1660,15 @@ func parseResponseFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 				switch ident.Name {
 				case "Description":
 					if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
-						response.Description = strings.Trim(basicLit.Value, `"`)
+						response.Description = stringLiteralValue(basicLit)
+					}
+				case "Headers":
+					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+						headers, err := parseHeadersFromASTWithTypes(compLit, pkg)
+						if err != nil {
+							return response, fmt.Errorf("failed to parse headers: %w", err)
+						}
+						response.Headers = headers
 					}
 				case "Content":
 					if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
@@ -806,6 +1686,58 @@ func parseResponseFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 	return response, nil
 }
 
+// parseHeadersFromASTWithTypes parses gopenapi.Headers from AST with type resolution
+func parseHeadersFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Headers, error) {
+	headers := make(gopenapi.Headers)
+
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			// Get the header name
+			var name string
+			if basicLit, ok := kv.Key.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+				name = stringLiteralValue(basicLit)
+			}
+
+			compLit, ok := kv.Value.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+			header := gopenapi.Header{}
+			for _, headerElt := range compLit.Elts {
+				if kv, ok := headerElt.(*ast.KeyValueExpr); ok {
+					if ident, ok := kv.Key.(*ast.Ident); ok {
+						switch ident.Name {
+						case "Description":
+							if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+								header.Description = stringLiteralValue(basicLit)
+							}
+						case "Required", "Deprecated":
+							if valueIdent, ok := kv.Value.(*ast.Ident); ok {
+								if ident.Name == "Required" {
+									header.Required = valueIdent.Name == "true"
+								} else {
+									header.Deprecated = valueIdent.Name == "true"
+								}
+							}
+						case "Schema":
+							if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
+								schema, err := parseSchemaFromASTWithTypes(compLit, pkg)
+								if err != nil {
+									return headers, fmt.Errorf("failed to parse schema for header %s: %w", name, err)
+								}
+								header.Schema = schema
+							}
+						}
+					}
+				}
+			}
+			headers[name] = header
+		}
+	}
+
+	return headers, nil
+}
+
 // parseContentFromASTWithTypes parses gopenapi.Content from AST with type resolution
 func parseContentFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.Content, error) {
 	content := make(gopenapi.Content)
@@ -813,29 +1745,36 @@ func parseContentFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 	for _, elt := range lit.Elts {
 		if kv, ok := elt.(*ast.KeyValueExpr); ok {
 			// Get media type
-			var mediaType gopenapi.MediaType
-			if selectorExpr, ok := kv.Key.(*ast.SelectorExpr); ok {
-				if selectorExpr.Sel.Name == "ApplicationJSON" {
-					mediaType = gopenapi.ApplicationJSON
-				}
-			}
+			mediaType := parseMediaTypeFromAST(kv.Key, pkg)
 
 			// Parse media type object
 			if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
 				mediaTypeObj := struct {
-					Schema gopenapi.Schema `json:"schema,omitempty"`
+					Schema   gopenapi.Schema   `json:"schema,omitempty"`
+					Examples gopenapi.Examples `json:"examples,omitempty"`
 				}{}
 				for _, mediaElt := range compLit.Elts {
-					if kv, ok := mediaElt.(*ast.KeyValueExpr); ok {
-						if ident, ok := kv.Key.(*ast.Ident); ok && ident.Name == "Schema" {
-							if compLit, ok := kv.Value.(*ast.CompositeLit); ok {
-								schema, err := parseSchemaFromASTWithTypes(compLit, pkg)
-								if err != nil {
-									return content, fmt.Errorf("failed to parse schema: %w", err)
-								}
-								mediaTypeObj.Schema = schema
-							}
+					kv, ok := mediaElt.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					ident, ok := kv.Key.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					compLit, ok := kv.Value.(*ast.CompositeLit)
+					if !ok {
+						continue
+					}
+					switch ident.Name {
+					case "Schema":
+						schema, err := parseSchemaFromASTWithTypes(compLit, pkg)
+						if err != nil {
+							return content, fmt.Errorf("failed to parse schema: %w", err)
 						}
+						mediaTypeObj.Schema = schema
+					case "Examples":
+						mediaTypeObj.Examples = parseExamplesFromAST(compLit, pkg)
 					}
 				}
 				content[mediaType] = mediaTypeObj
@@ -846,6 +1785,20 @@ func parseContentFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package)
 	return content, nil
 }
 
+// parseMediaTypeFromAST resolves a media type key such as gopenapi.MultipartFormData
+// or "text/plain" to its constant value
+func parseMediaTypeFromAST(expr ast.Expr, pkg *packages.Package) gopenapi.MediaType {
+	if pkg.TypesInfo != nil {
+		if tv, ok := pkg.TypesInfo.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			return gopenapi.MediaType(constant.StringVal(tv.Value))
+		}
+	}
+	if selectorExpr, ok := expr.(*ast.SelectorExpr); ok && selectorExpr.Sel.Name == "ApplicationJSON" {
+		return gopenapi.ApplicationJSON
+	}
+	return ""
+}
+
 // parseRequestBodyFromASTWithTypes parses gopenapi.RequestBody from AST with type resolution
 func parseRequestBodyFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Package) (gopenapi.RequestBody, error) {
 	requestBody := gopenapi.RequestBody{}
@@ -874,323 +1827,127 @@ func parseRequestBodyFromASTWithTypes(lit *ast.CompositeLit, pkg *packages.Packa
 	return requestBody, nil
 }
 
-// SpecToOpenAPIJSON converts a gopenapi.Spec to OpenAPI JSON format
-func SpecToOpenAPIJSON(spec *gopenapi.Spec) ([]byte, error) {
-	// Create OpenAPI JSON structure
-	openAPISpec := map[string]interface{}{
-		"openapi": spec.OpenAPI,
-		"info": map[string]interface{}{
-			"title":       spec.Info.Title,
-			"description": spec.Info.Description,
-			"version":     spec.Info.Version,
-		},
-	}
-
-	// Add servers if present
-	if len(spec.Servers) > 0 {
-		servers := make([]map[string]interface{}, len(spec.Servers))
-		for i, server := range spec.Servers {
-			servers[i] = map[string]interface{}{
-				"url":         server.URL,
-				"description": server.Description,
-			}
-		}
-		openAPISpec["servers"] = servers
-	}
-
-	// Add paths
-	if len(spec.Paths) > 0 {
-		paths := make(map[string]interface{})
-		for path, pathItem := range spec.Paths {
-			pathObj := make(map[string]interface{})
+// OperationIdCase is a casing policy applied to operationIds when emitting a spec
+type OperationIdCase string
 
-			// Add operations for each HTTP method
-			if pathItem.Get != nil {
-				pathObj["get"] = operationToJSON(pathItem.Get)
-			}
-			if pathItem.Post != nil {
-				pathObj["post"] = operationToJSON(pathItem.Post)
-			}
-			if pathItem.Put != nil {
-				pathObj["put"] = operationToJSON(pathItem.Put)
-			}
-			if pathItem.Delete != nil {
-				pathObj["delete"] = operationToJSON(pathItem.Delete)
-			}
-			if pathItem.Patch != nil {
-				pathObj["patch"] = operationToJSON(pathItem.Patch)
-			}
-			if pathItem.Head != nil {
-				pathObj["head"] = operationToJSON(pathItem.Head)
-			}
-			if pathItem.Options != nil {
-				pathObj["options"] = operationToJSON(pathItem.Options)
-			}
+const (
+	// OperationIdCasePreserve emits operationIds exactly as declared
+	OperationIdCasePreserve OperationIdCase = ""
+	// OperationIdCaseCamel emits operationIds as camelCase (getUserById)
+	OperationIdCaseCamel OperationIdCase = "camel"
+	// OperationIdCasePascal emits operationIds as PascalCase (GetUserById)
+	OperationIdCasePascal OperationIdCase = "pascal"
+)
 
-			paths[path] = pathObj
-		}
-		openAPISpec["paths"] = paths
+// ParseOperationIdCase parses a casing policy name as accepted by the CLI
+func ParseOperationIdCase(s string) (OperationIdCase, error) {
+	switch strings.ToLower(s) {
+	case "", "preserve":
+		return OperationIdCasePreserve, nil
+	case "camel", "camelcase":
+		return OperationIdCaseCamel, nil
+	case "pascal", "pascalcase":
+		return OperationIdCasePascal, nil
+	default:
+		return OperationIdCasePreserve, fmt.Errorf("unsupported operationId case %q (expected preserve, camel or pascal)", s)
 	}
-
-	// Marshal to JSON with proper indentation
-	return json.MarshalIndent(openAPISpec, "", "  ")
 }
 
-// operationToJSON converts a gopenapi.Operation to JSON format
-func operationToJSON(op *gopenapi.Operation) map[string]interface{} {
-	operation := map[string]interface{}{}
-
-	if op.OperationId != "" {
-		operation["operationId"] = op.OperationId
-	}
-	if op.Summary != "" {
-		operation["summary"] = op.Summary
-	}
-	if op.Description != "" {
-		operation["description"] = op.Description
+// Apply normalizes an operationId according to the casing policy
+func (c OperationIdCase) Apply(operationId string) string {
+	if c == OperationIdCasePreserve || operationId == "" {
+		return operationId
 	}
 
-	// Add parameters
-	if len(op.Parameters) > 0 {
-		params := make([]map[string]interface{}, len(op.Parameters))
-		for i, param := range op.Parameters {
-			paramObj := map[string]interface{}{
-				"name":        param.Name,
-				"in":          parameterLocationToString(param.In),
-				"required":    param.Required,
-				"description": param.Description,
-				"schema":      schemaToJSON(param.Schema),
-			}
-			params[i] = paramObj
-		}
-		operation["parameters"] = params
-	}
-
-	// Add request body
-	if op.RequestBody.Content != nil {
-		requestBody := map[string]interface{}{
-			"required": op.RequestBody.Required,
-			"content":  contentToJSON(op.RequestBody.Content),
-		}
-		operation["requestBody"] = requestBody
-	}
-
-	// Add responses
-	if len(op.Responses) > 0 {
-		responses := make(map[string]interface{})
-		for statusCode, response := range op.Responses {
-			responseObj := map[string]interface{}{
-				"description": response.Description,
-			}
-			if response.Content != nil {
-				responseObj["content"] = contentToJSON(response.Content)
-			}
-			responses[fmt.Sprintf("%d", statusCode)] = responseObj
+	var result strings.Builder
+	for i, word := range splitIdentifierWords(operationId) {
+		word = strings.ToLower(word)
+		if i == 0 && c == OperationIdCaseCamel {
+			result.WriteString(word)
+			continue
 		}
-		operation["responses"] = responses
+		result.WriteString(strings.ToUpper(word[:1]) + word[1:])
 	}
-
-	return operation
+	return result.String()
 }
 
-// parameterLocationToString converts parameter location to string
-func parameterLocationToString(location gopenapi.In) string {
-	switch location {
-	case gopenapi.InPath:
-		return "path"
-	case gopenapi.InQuery:
-		return "query"
-	case gopenapi.InHeader:
-		return "header"
-	case gopenapi.InCookie:
-		return "cookie"
-	default:
-		return "query"
-	}
-}
-
-// schemaToJSON converts a gopenapi.Schema to JSON format
-func schemaToJSON(schema gopenapi.Schema) map[string]interface{} {
-	schemaObj := map[string]interface{}{}
-
-	if schema.Type != nil {
-		switch schema.Type {
-		case gopenapi.String:
-			schemaObj["type"] = "string"
-		case gopenapi.Integer:
-			schemaObj["type"] = "integer"
-		case gopenapi.Number:
-			schemaObj["type"] = "number"
-		case gopenapi.Boolean:
-			schemaObj["type"] = "boolean"
-		case gopenapi.Array:
-			schemaObj["type"] = "array"
-		default:
-			// For complex types (structs), use object type
-			if schema.Type.Kind() == reflect.Struct {
-				schemaObj["type"] = "object"
-				// Add properties based on struct fields
-				properties := generateStructProperties(schema.Type)
-				if len(properties) > 0 {
-					schemaObj["properties"] = properties
-				}
-			} else {
-				schemaObj["type"] = goTypeToOpenAPIType(schema.Type)
+// splitIdentifierWords splits an identifier into words on separators and case boundaries,
+// keeping acronyms together (getUserByID -> get, User, By, ID)
+func splitIdentifierWords(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
 			}
+			continue
 		}
-	}
-
-	return schemaObj
-}
-
-// generateStructProperties recursively generates properties for struct types
-func generateStructProperties(t reflect.Type) map[string]interface{} {
-	properties := make(map[string]interface{})
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if !field.IsExported() {
+		if start < 0 {
+			start = i
 			continue
 		}
-
-		jsonTag := field.Tag.Get("json")
-		fieldName := field.Name
-		if jsonTag != "" {
-			parts := strings.Split(jsonTag, ",")
-			if parts[0] != "" && parts[0] != "-" {
-				fieldName = parts[0]
-			}
+		prev := runes[i-1]
+		boundary := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
 		}
-
-		// Generate schema for this field
-		fieldSchema := generateFieldSchema(field.Type)
-		properties[fieldName] = fieldSchema
 	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
 
-	return properties
+// SpecOptions controls how a gopenapi.Spec is rendered as an OpenAPI document
+type SpecOptions struct {
+	// OperationIdCase normalizes every emitted operationId
+	OperationIdCase OperationIdCase
+	// EmptyAnySchema describes interface{} and any values, which may hold any
+	// JSON value, with the empty schema {} instead of "type": "object", as
+	// servers setting the same gopenapi.OpenAPIOptions serve them
+	EmptyAnySchema bool
 }
 
-// generateFieldSchema generates the schema for a single field type
-func generateFieldSchema(t reflect.Type) map[string]interface{} {
-	schema := map[string]interface{}{}
-
-	// Handle special types first
-	if t.PkgPath() != "" && t.Name() != "" {
-		typeFullName := t.PkgPath() + "." + t.Name()
-		switch typeFullName {
-		case "time.Time":
-			schema["type"] = "string"
-			return schema
-		case "time.Duration":
-			schema["type"] = "integer"
-			return schema
-		}
-	}
-
-	// Handle types by kind
-	switch t.Kind() {
-	case reflect.String:
-		schema["type"] = "string"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		schema["type"] = "integer"
-	case reflect.Float32, reflect.Float64:
-		schema["type"] = "number"
-	case reflect.Bool:
-		schema["type"] = "boolean"
-	case reflect.Slice, reflect.Array:
-		schema["type"] = "array"
-		// TODO: Add items schema for array elements
-	case reflect.Struct:
-		schema["type"] = "object"
-		// Recursively generate properties for nested structs
-		properties := generateStructProperties(t)
-		if len(properties) > 0 {
-			schema["properties"] = properties
-		}
-	case reflect.Ptr:
-		// For pointers, use the element type
-		return generateFieldSchema(t.Elem())
-	case reflect.Map:
-		schema["type"] = "object"
-		// TODO: Add additionalProperties for map values
-	default:
-		schema["type"] = "object"
+// openAPIOptions returns the gopenapi.OpenAPIOptions rendering specs with opts
+func (opts SpecOptions) openAPIOptions() gopenapi.OpenAPIOptions {
+	return gopenapi.OpenAPIOptions{
+		EmptyAnySchema: opts.EmptyAnySchema,
+		OperationId:    opts.OperationIdCase.Apply,
 	}
-
-	return schema
 }
 
-// contentToJSON converts gopenapi.Content to JSON format
-func contentToJSON(content gopenapi.Content) map[string]interface{} {
-	contentObj := make(map[string]interface{})
+// SpecToOpenAPIJSON converts a gopenapi.Spec to OpenAPI JSON format
+func SpecToOpenAPIJSON(spec *gopenapi.Spec) ([]byte, error) {
+	return SpecToOpenAPIJSONWithOptions(spec, SpecOptions{})
+}
 
-	for mediaType, mediaTypeObj := range content {
-		contentObj[string(mediaType)] = map[string]interface{}{
-			"schema": schemaToJSON(mediaTypeObj.Schema),
-		}
-	}
+// SpecToOpenAPIJSONWithOptions converts a gopenapi.Spec to OpenAPI JSON format
+// using the given options. The document is rendered by gopenapi.MarshalOpenAPIJSON,
+// as servers serve it with gopenapi.OpenAPIJSONHandler.
+func SpecToOpenAPIJSONWithOptions(spec *gopenapi.Spec, opts SpecOptions) ([]byte, error) {
+	return gopenapi.MarshalOpenAPIJSON(spec, opts.openAPIOptions())
+}
 
-	return contentObj
+// SpecToOpenAPIYAML converts a gopenapi.Spec to OpenAPI YAML format
+func SpecToOpenAPIYAML(spec *gopenapi.Spec) ([]byte, error) {
+	return SpecToOpenAPIYAMLWithOptions(spec, SpecOptions{})
 }
 
-// goTypeToOpenAPIType converts Go reflect.Type to OpenAPI type string
-func goTypeToOpenAPIType(t reflect.Type) string {
-	// Handle named types
-	if t.PkgPath() != "" && t.Name() != "" {
-		// Special handling for well-known types that should have specific OpenAPI mappings
-		// regardless of their underlying Go structure
-		typeFullName := t.PkgPath() + "." + t.Name()
-		switch typeFullName {
-		case "time.Time":
-			return "string" // time.Time should be represented as string in OpenAPI (RFC3339)
-		case "time.Duration":
-			return "integer" // Duration as nanoseconds
-			// Add more well-known types as needed, but keep it minimal
-			// For example: net.IP, url.URL, etc.
-		}
-
-		// For other named types, check the underlying type
-		// This handles type aliases like `type UserID string` from any package
-		switch t.Kind() {
-		case reflect.String:
-			return "string"
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			return "integer"
-		case reflect.Float32, reflect.Float64:
-			return "number"
-		case reflect.Bool:
-			return "boolean"
-		case reflect.Slice, reflect.Array:
-			return "array"
-		case reflect.Struct:
-			return "object"
-		case reflect.Ptr:
-			return goTypeToOpenAPIType(t.Elem())
-		default:
-			return "object"
-		}
-	}
-
-	// Handle basic types
-	switch t.Kind() {
-	case reflect.String:
-		return "string"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return "integer"
-	case reflect.Float32, reflect.Float64:
-		return "number"
-	case reflect.Bool:
-		return "boolean"
-	case reflect.Slice, reflect.Array:
-		return "array"
-	case reflect.Struct:
-		return "object"
-	case reflect.Ptr:
-		return goTypeToOpenAPIType(t.Elem())
-	default:
-		return "object"
+// SpecToOpenAPIYAMLWithOptions converts a gopenapi.Spec to OpenAPI YAML format using the given options
+func SpecToOpenAPIYAMLWithOptions(spec *gopenapi.Spec, opts SpecOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(gopenapi.OpenAPIDocument(spec, opts.openAPIOptions())); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
 }
@@ -1,14 +1,18 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/runpod/gopenapi"
 	"github.com/runpod/gopenapi/cmd/gopenapi/parser/internal/company"
 	"github.com/runpod/gopenapi/cmd/gopenapi/parser/internal/mock"
+	"gopkg.in/yaml.v3"
 )
 
 // Test that we can resolve types from other packages
@@ -417,9 +421,32 @@ func TestParameterLocationToString(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parameterLocationToString(tt.location)
-			if result != tt.expected {
-				t.Errorf("parameterLocationToString() = %v, want %v", result, tt.expected)
+			spec := gopenapi.Spec{
+				OpenAPI: "3.0.0",
+				Paths: gopenapi.Paths{
+					"/items": {
+						Get: &gopenapi.Operation{
+							Parameters: gopenapi.Parameters{{Name: "value", In: tt.location, Schema: gopenapi.Schema{Type: gopenapi.String}}},
+						},
+					},
+				},
+			}
+			jsonData, err := SpecToOpenAPIJSON(&spec)
+			if err != nil {
+				t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+			}
+			var doc struct {
+				Paths map[string]map[string]struct {
+					Parameters []struct {
+						In string `json:"in"`
+					} `json:"parameters"`
+				} `json:"paths"`
+			}
+			if err := json.Unmarshal(jsonData, &doc); err != nil {
+				t.Fatalf("Failed to unmarshal JSON: %v", err)
+			}
+			if result := doc.Paths["/items"]["get"].Parameters[0].In; result != tt.expected {
+				t.Errorf("parameter in = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -480,9 +507,9 @@ func TestGoTypeToOpenAPIType(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := goTypeToOpenAPIType(tt.goType)
+			result := schemaToJSON(gopenapi.Schema{Type: tt.goType})["type"]
 			if result != tt.expected {
-				t.Errorf("goTypeToOpenAPIType() = %v, want %v", result, tt.expected)
+				t.Errorf("schema type = %v, want %v", result, tt.expected)
 			}
 		})
 	}
@@ -1948,3 +1975,1521 @@ func TestDebugExternalTypeResolution(t *testing.T) {
 			field.Name, field.Type, field.Type.Kind(), field.Type.Name(), field.Type.PkgPath())
 	}
 }
+
+func TestSpecToOpenAPIJSONOperationIdCase(t *testing.T) {
+	spec := gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info: gopenapi.Info{
+			Title:   "Casing Test API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/users/{id}": gopenapi.Path{
+				Get:    &gopenapi.Operation{OperationId: "GetUserById"},
+				Delete: &gopenapi.Operation{OperationId: "delete_user_by_id"},
+			},
+			"/users": gopenapi.Path{
+				Get:  &gopenapi.Operation{OperationId: "list-users"},
+				Post: &gopenapi.Operation{OperationId: "createUserFromID"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		idCase   OperationIdCase
+		expected map[string]string
+	}{
+		{
+			name:   "preserve",
+			idCase: OperationIdCasePreserve,
+			expected: map[string]string{
+				"/users/{id} get":    "GetUserById",
+				"/users/{id} delete": "delete_user_by_id",
+				"/users get":         "list-users",
+				"/users post":        "createUserFromID",
+			},
+		},
+		{
+			name:   "camel",
+			idCase: OperationIdCaseCamel,
+			expected: map[string]string{
+				"/users/{id} get":    "getUserById",
+				"/users/{id} delete": "deleteUserById",
+				"/users get":         "listUsers",
+				"/users post":        "createUserFromId",
+			},
+		},
+		{
+			name:   "pascal",
+			idCase: OperationIdCasePascal,
+			expected: map[string]string{
+				"/users/{id} get":    "GetUserById",
+				"/users/{id} delete": "DeleteUserById",
+				"/users get":         "ListUsers",
+				"/users post":        "CreateUserFromId",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData, err := SpecToOpenAPIJSONWithOptions(&spec, SpecOptions{OperationIdCase: tt.idCase})
+			if err != nil {
+				t.Fatalf("SpecToOpenAPIJSONWithOptions() error = %v", err)
+			}
+
+			var result struct {
+				Paths map[string]map[string]struct {
+					OperationId string `json:"operationId"`
+				} `json:"paths"`
+			}
+			if err := json.Unmarshal(jsonData, &result); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v", err)
+			}
+
+			for key, want := range tt.expected {
+				var path, method string
+				if i := strings.LastIndex(key, " "); i >= 0 {
+					path, method = key[:i], key[i+1:]
+				}
+				got := result.Paths[path][method].OperationId
+				if got != want {
+					t.Errorf("operationId for %s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOperationIdCase(t *testing.T) {
+	for input, want := range map[string]OperationIdCase{
+		"":         OperationIdCasePreserve,
+		"preserve": OperationIdCasePreserve,
+		"camel":    OperationIdCaseCamel,
+		"Pascal":   OperationIdCasePascal,
+	} {
+		got, err := ParseOperationIdCase(input)
+		if err != nil {
+			t.Errorf("ParseOperationIdCase(%q) error = %v", input, err)
+		}
+		if got != want {
+			t.Errorf("ParseOperationIdCase(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	if _, err := ParseOperationIdCase("kebab"); err == nil {
+		t.Error("ParseOperationIdCase(\"kebab\") should return an error")
+	}
+}
+
+func TestSpecToOpenAPIJSONResponseHeaders(t *testing.T) {
+	spec := gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info: gopenapi.Info{
+			Title:   "Headers Test API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/users": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listUsers",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Users",
+							Headers: gopenapi.Headers{
+								"X-Rate-Limit-Remaining": {
+									Description: "Requests left in the current window",
+									Required:    true,
+									Schema:      gopenapi.Schema{Type: gopenapi.Integer},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Headers map[string]struct {
+					Description string         `json:"description"`
+					Required    bool           `json:"required"`
+					Schema      map[string]any `json:"schema"`
+				} `json:"headers"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	header, ok := result.Paths["/users"]["get"].Responses["200"].Headers["X-Rate-Limit-Remaining"]
+	if !ok {
+		t.Fatalf("Expected X-Rate-Limit-Remaining header in response, got JSON:\n%s", jsonData)
+	}
+	if header.Schema["type"] != "integer" {
+		t.Errorf("Expected header schema type 'integer', got %v", header.Schema["type"])
+	}
+	if !header.Required {
+		t.Error("Expected header to be required")
+	}
+	if header.Description != "Requests left in the current window" {
+		t.Errorf("Unexpected header description %q", header.Description)
+	}
+}
+
+func TestSpecToOpenAPIJSONDeterministic(t *testing.T) {
+	spec := gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info: gopenapi.Info{
+			Title:   "Ordering Test API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/widgets": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listWidgets",
+					Parameters: gopenapi.Parameters{
+						{Name: "zeta", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "alpha", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "mid", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Responses: gopenapi.Responses{
+						500: {Description: "Server error"},
+						200: {Description: "Widgets"},
+						404: {Description: "Not found"},
+					},
+				},
+			},
+			"/accounts": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "listAccounts",
+					Responses: gopenapi.Responses{
+						200: {Description: "Accounts"},
+					},
+				},
+			},
+			"/users/{id}": gopenapi.Path{
+				Delete: &gopenapi.Operation{
+					OperationId: "deleteUser",
+					Responses: gopenapi.Responses{
+						204: {Description: "Deleted"},
+					},
+				},
+			},
+		},
+	}
+
+	first, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := SpecToOpenAPIJSON(&spec)
+		if err != nil {
+			t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatalf("Expected identical output between runs, got:\n%s\n---\n%s", first, again)
+		}
+	}
+
+	output := string(first)
+	assertOrdered := func(what string, keys ...string) {
+		t.Helper()
+		last := -1
+		for _, key := range keys {
+			idx := strings.Index(output, key)
+			if idx < 0 {
+				t.Fatalf("Expected %s %s in output:\n%s", what, key, output)
+			}
+			if idx < last {
+				t.Errorf("Expected %s in order %v, got:\n%s", what, keys, output)
+				return
+			}
+			last = idx
+		}
+	}
+	assertOrdered("paths", `"/accounts"`, `"/users/{id}"`, `"/widgets"`)
+	assertOrdered("response codes", `"200"`, `"404"`, `"500"`)
+	assertOrdered("parameters", `"zeta"`, `"alpha"`, `"mid"`)
+}
+
+func TestSpecToOpenAPIYAMLRoundTrip(t *testing.T) {
+	spec := gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info: gopenapi.Info{
+			Title:       "YAML Test API",
+			Description: "API for YAML output",
+			Version:     "1.0.0",
+		},
+		Servers: gopenapi.Servers{
+			{URL: "https://api.example.com", Description: "Production"},
+		},
+		Paths: gopenapi.Paths{
+			"/users/{id}": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "User",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {
+									Schema: gopenapi.Schema{Type: reflect.TypeOf(struct {
+										ID   int    `json:"id"`
+										Name string `json:"name"`
+									}{})},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	yamlData, err := SpecToOpenAPIYAML(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIYAML() error = %v", err)
+	}
+
+	var fromYAML map[string]interface{}
+	if err := yaml.Unmarshal(yamlData, &fromYAML); err != nil {
+		t.Fatalf("Generated YAML is invalid: %v\n%s", err, yamlData)
+	}
+
+	// Normalize YAML scalars through JSON so both documents use the same Go types
+	normalized, err := json.Marshal(fromYAML)
+	if err != nil {
+		t.Fatalf("Failed to re-encode YAML document: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(normalized, &got); err != nil {
+		t.Fatalf("Failed to decode normalized document: %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+	var want map[string]interface{}
+	if err := json.Unmarshal(jsonData, &want); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("YAML document does not match JSON document\nYAML:\n%s\nJSON:\n%s", yamlData, jsonData)
+	}
+}
+
+// schemaToJSON renders schema as the CLI does with the default options
+func schemaToJSON(schema gopenapi.Schema) map[string]interface{} {
+	return gopenapi.OpenAPISchema(schema, gopenapi.OpenAPIOptions{})
+}
+
+func TestSchemaToJSONPrefixItems(t *testing.T) {
+	schemaObj := schemaToJSON(gopenapi.Schema{
+		Type: gopenapi.Array,
+		PrefixItems: []gopenapi.Schema{
+			{Type: gopenapi.String},
+			{Type: gopenapi.Integer},
+		},
+	})
+
+	prefixItems, ok := schemaObj["prefixItems"].([]map[string]interface{})
+	if !ok || len(prefixItems) != 2 {
+		t.Fatalf("Expected two prefixItems, got %v", schemaObj["prefixItems"])
+	}
+	if prefixItems[0]["type"] != "string" || prefixItems[1]["type"] != "integer" {
+		t.Errorf("Unexpected prefixItems %v", prefixItems)
+	}
+}
+
+func TestSpecToOpenAPIJSONCodeSamples(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/codesamples/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			CodeSamples gopenapi.CodeSamples `json:"x-codeSamples"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	expected := gopenapi.CodeSamples{
+		{Lang: "shell", Label: "curl", Source: "curl https://api.example.com/users"},
+		{Lang: "go", Source: "users, err := client.ListUsers(ctx)\nif err != nil {\n\treturn err\n}"},
+	}
+	if got := result.Paths["/users"]["get"].CodeSamples; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected code samples %+v, got %+v", expected, got)
+	}
+}
+
+func TestSchemaToJSONPasswordFormat(t *testing.T) {
+	type Credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password" format:"password"`
+	}
+
+	schemaObj := schemaToJSON(gopenapi.Schema{Type: gopenapi.Object[Credentials]()})
+	properties := schemaObj["properties"].(map[string]interface{})
+	if format := properties["password"].(map[string]interface{})["format"]; format != "password" {
+		t.Errorf("Expected password property to have format 'password', got %v", format)
+	}
+	if _, ok := properties["username"].(map[string]interface{})["format"]; ok {
+		t.Error("Expected username property to have no format")
+	}
+
+	schemaObj = schemaToJSON(gopenapi.Schema{Type: gopenapi.String, Format: gopenapi.FormatPassword})
+	if schemaObj["format"] != "password" {
+		t.Errorf("Expected schema format 'password', got %v", schemaObj["format"])
+	}
+}
+
+func TestSchemaToJSONContentEncoding(t *testing.T) {
+	schemaObj := schemaToJSON(gopenapi.Schema{Type: gopenapi.String, ContentMediaType: "application/json", ContentEncoding: "base64"})
+	if schemaObj["type"] != "string" || schemaObj["contentEncoding"] != "base64" || schemaObj["contentMediaType"] != "application/json" {
+		t.Errorf("Expected a base64 encoded application/json string, got %v", schemaObj)
+	}
+
+	schemaObj = schemaToJSON(gopenapi.Schema{Type: gopenapi.String})
+	if _, ok := schemaObj["contentEncoding"]; ok {
+		t.Error("Expected no contentEncoding for a plain string")
+	}
+}
+
+func TestSpecToOpenAPIJSONTimeout(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/timeout/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	if got := spec.Paths["/generations"].Post.Timeout; got != 2*time.Minute {
+		t.Errorf("Expected createGeneration timeout 2m, got %v", got)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	expected := map[string]any{
+		"/generations post": "2m0s",
+		"/generations get":  "1.5s",
+		"/health get":       nil,
+	}
+	for key, want := range expected {
+		path, method, _ := strings.Cut(key, " ")
+		if got := result.Paths[path][method]["x-timeout"]; got != want {
+			t.Errorf("Expected %s x-timeout %v, got %v", key, want, got)
+		}
+	}
+}
+
+func TestStructTypeName(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/examples/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	users := spec.Paths["/users"].Get.Responses[200].Content[gopenapi.ApplicationJSON].Schema.Type
+	if users.Kind() != reflect.Slice {
+		t.Fatalf("Expected Object[[]User]() to parse as a slice, got %v", users)
+	}
+	if got := StructTypeName(users.Elem()); got != "User" {
+		t.Errorf("StructTypeName() = %q, want %q", got, "User")
+	}
+	if got := StructTypeName(reflect.TypeOf(struct{ ID int }{})); got != "" {
+		t.Errorf("StructTypeName() of an anonymous struct = %q, want empty", got)
+	}
+}
+
+func TestSpecToOpenAPIJSONPagination(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/pagination/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	if !spec.Paths["/users"].Get.Paginated {
+		t.Errorf("Expected listUsers to be paginated")
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	if got := result.Paths["/users"]["get"]["x-pagination"]; got != true {
+		t.Errorf("Expected listUsers x-pagination true, got %v", got)
+	}
+	if got, ok := result.Paths["/health"]["get"]["x-pagination"]; ok {
+		t.Errorf("Expected no x-pagination for health, got %v", got)
+	}
+}
+
+func TestSpecToOpenAPIJSONParameterDefaults(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/defaults/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name   string         `json:"name"`
+				Schema map[string]any `json:"schema"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	expected := map[string]any{
+		"limit":    float64(20),
+		"order":    "desc",
+		"archived": false,
+		"X-Region": nil,
+	}
+	params := result.Paths["/items"]["get"].Parameters
+	if len(params) != len(expected) {
+		t.Fatalf("Expected %d parameters, got %d", len(expected), len(params))
+	}
+	for _, param := range params {
+		if got := param.Schema["default"]; got != expected[param.Name] {
+			t.Errorf("Expected %s default %v, got %v", param.Name, expected[param.Name], got)
+		}
+	}
+}
+
+func TestSpecToOpenAPIJSONParameterConstraints(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/constraints/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name   string          `json:"name"`
+				Schema json.RawMessage `json:"schema"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	expected := map[string]string{
+		"page":   `{"maximum":100,"minimum":1,"type":"integer"}`,
+		"ratio":  `{"exclusiveMaximum":true,"exclusiveMinimum":true,"maximum":1,"minimum":-0.5,"type":"number"}`,
+		"slug":   `{"maxLength":64,"minLength":1,"pattern":"^[a-z0-9-]+$","type":"string"}`,
+		"filter": `{"contentEncoding":"base64","contentMediaType":"application/json","type":"string"}`,
+		"sort":   `{"enum":["asc","desc"],"type":"string"}`,
+	}
+	params := result.Paths["/items"]["get"].Parameters
+	if len(params) != len(expected) {
+		t.Fatalf("Expected %d parameters, got %d", len(expected), len(params))
+	}
+	for _, param := range params {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, param.Schema); err != nil {
+			t.Fatal(err)
+		}
+		if compact.String() != expected[param.Name] {
+			t.Errorf("Expected %s schema %s, got %s", param.Name, expected[param.Name], compact.String())
+		}
+	}
+}
+
+func TestSpecToOpenAPIJSONTags(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/tags/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Tags []string `json:"tags"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	if got := result.Paths["/users"]["get"].Tags; !reflect.DeepEqual(got, []string{"users", "admin"}) {
+		t.Errorf("Expected tags [users admin], got %v", got)
+	}
+	if got := result.Paths["/health"]["get"].Tags; got != nil {
+		t.Errorf("Expected no tags for health, got %v", got)
+	}
+}
+
+func TestSpecToOpenAPIJSONArrayParameterItems(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/arrayparams/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Schema  map[string]any `json:"schema"`
+				Explode *bool          `json:"explode"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	params := result.Paths["/items"]["get"].Parameters
+	if len(params) != 1 {
+		t.Fatalf("Expected one parameter, got %d", len(params))
+	}
+	expected := map[string]any{
+		"type":  "array",
+		"items": map[string]any{"type": "integer"},
+	}
+	if !reflect.DeepEqual(params[0].Schema, expected) {
+		t.Errorf("Expected schema %v, got %v", expected, params[0].Schema)
+	}
+	if params[0].Explode == nil || *params[0].Explode {
+		t.Errorf("Expected explode false, got %v", params[0].Explode)
+	}
+}
+
+func TestSchemaToJSONEnumTag(t *testing.T) {
+	type Account struct {
+		Status string `json:"status" enum:"active,inactive"`
+	}
+
+	schemaObj := schemaToJSON(gopenapi.Schema{Type: gopenapi.Object[Account]()})
+	properties := schemaObj["properties"].(map[string]interface{})
+	enum := properties["status"].(map[string]interface{})["enum"]
+	if !reflect.DeepEqual(enum, []string{"active", "inactive"}) {
+		t.Errorf("Expected status enum [active inactive], got %v", enum)
+	}
+}
+
+func TestSpecToOpenAPIJSONMultipartRequestBody(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/multipart/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]any `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	content, ok := result.Paths["/avatars"]["post"].RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatalf("Expected multipart/form-data request body, got %s", jsonData)
+	}
+	expected := map[string]any{"type": "string", "format": "binary"}
+	if file := content.Schema.Properties["file"]; !reflect.DeepEqual(file, expected) {
+		t.Errorf("Expected file property %v, got %v", expected, file)
+	}
+}
+
+func TestParseAnonymousStructObject(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/anonstruct/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	schemaType := spec.Paths["/users"].Post.RequestBody.Content[gopenapi.ApplicationJSON].Schema.Type
+	if schemaType == nil || schemaType.Kind() != reflect.Struct {
+		t.Fatalf("Expected a struct type, got %v", schemaType)
+	}
+
+	expected := map[string]struct {
+		kind reflect.Kind
+		tag  string
+	}{
+		"Name":    {reflect.String, "name"},
+		"Age":     {reflect.Int, "age,omitempty"},
+		"Tags":    {reflect.Slice, "tags"},
+		"Address": {reflect.Struct, "address"},
+	}
+	if schemaType.NumField() != len(expected) {
+		t.Fatalf("Expected %d fields, got %d", len(expected), schemaType.NumField())
+	}
+	for name, want := range expected {
+		field, ok := schemaType.FieldByName(name)
+		if !ok {
+			t.Errorf("Missing field %s", name)
+			continue
+		}
+		if field.Type.Kind() != want.kind {
+			t.Errorf("Field %s: expected kind %v, got %v", name, want.kind, field.Type.Kind())
+		}
+		if tag := field.Tag.Get("json"); tag != want.tag {
+			t.Errorf("Field %s: expected json tag %q, got %q", name, want.tag, tag)
+		}
+	}
+	if address, _ := schemaType.FieldByName("Address"); address.Type.Kind() == reflect.Struct {
+		if _, ok := address.Type.FieldByName("City"); !ok {
+			t.Errorf("Expected Address to resolve its City field, got %v", address.Type)
+		}
+	}
+}
+
+func TestParseDocCommentsAsDescriptions(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/doccomments/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	if spec.Info.Description != "Spec describes the users API." {
+		t.Errorf("Expected the spec doc comment as the info description, got %q", spec.Info.Description)
+	}
+
+	users := spec.Paths["/users"]
+	tests := []struct {
+		method      string
+		operation   *gopenapi.Operation
+		description string
+	}{
+		{"GET", users.Get, "List all users, most recently created first."},
+		{"POST", users.Post, "Creates a user from the request body."},
+		{"DELETE", users.Delete, ""},
+	}
+	for _, tt := range tests {
+		if tt.operation == nil {
+			t.Fatalf("%s operation not parsed", tt.method)
+		}
+		if tt.operation.Description != tt.description {
+			t.Errorf("%s: expected description %q, got %q", tt.method, tt.description, tt.operation.Description)
+		}
+	}
+}
+
+func TestSpecToOpenAPIJSONMultiLineDescriptions(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/descriptions/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	infoDescription := "# Descriptions API\n\nManages **items**.\n\n- Supports \"quotes\", \\escapes and <br> & HTML\n- Keeps `code` spans"
+	operationDescription := "Lists items.\n\nResults are \"paged\"."
+	responseDescription := "A page\nof items"
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+	if !bytes.Contains(jsonData, []byte("<br> & HTML")) {
+		t.Errorf("Expected HTML in descriptions not to be escaped, got %s", jsonData)
+	}
+
+	var result struct {
+		Info struct {
+			Description string `json:"description"`
+		} `json:"info"`
+		Paths map[string]map[string]struct {
+			Description string `json:"description"`
+			Responses   map[string]struct {
+				Description string `json:"description"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	if result.Info.Description != infoDescription {
+		t.Errorf("Expected info description %q, got %q", infoDescription, result.Info.Description)
+	}
+	operation := result.Paths["/items"]["get"]
+	if operation.Description != operationDescription {
+		t.Errorf("Expected operation description %q, got %q", operationDescription, operation.Description)
+	}
+	if got := operation.Responses["200"].Description; got != responseDescription {
+		t.Errorf("Expected response description %q, got %q", responseDescription, got)
+	}
+}
+
+func TestParseReflectTypeOfExternalStruct(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/reflecttypeof/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	expected := map[string]struct {
+		kind reflect.Kind
+		tag  string
+	}{
+		"ID":        {reflect.String, "id"},
+		"Name":      {reflect.String, "name"},
+		"Email":     {reflect.String, "email"},
+		"Age":       {reflect.Int, "age"},
+		"IsActive":  {reflect.Bool, "is_active"},
+		"CreatedAt": {reflect.Struct, "created_at"},
+		"Tags":      {reflect.Slice, "tags"},
+	}
+	tests := []struct {
+		path    string
+		pointer bool
+	}{
+		{path: "/users/{id}"},           // reflect.TypeOf(mock.User{})
+		{path: "/users", pointer: true}, // reflect.TypeOf(&mock.User{})
+	}
+	for _, tt := range tests {
+		schema := spec.Paths[tt.path].Get.Responses[200].Content[gopenapi.ApplicationJSON].Schema
+		schemaType := schema.Type
+		if tt.pointer {
+			if schemaType == nil || schemaType.Kind() != reflect.Ptr {
+				t.Fatalf("%s: expected a pointer type, got %v", tt.path, schemaType)
+			}
+			schemaType = schemaType.Elem()
+		}
+		if schemaType == nil || schemaType.Kind() != reflect.Struct {
+			t.Fatalf("%s: expected a struct type, got %v", tt.path, schemaType)
+		}
+		if schemaType.NumField() != reflect.TypeOf(mock.User{}).NumField() {
+			t.Errorf("%s: expected %d fields, got %v", tt.path, reflect.TypeOf(mock.User{}).NumField(), schemaType)
+		}
+		for name, want := range expected {
+			field, ok := schemaType.FieldByName(name)
+			if !ok {
+				t.Errorf("%s: missing field %s", tt.path, name)
+				continue
+			}
+			if field.Type.Kind() != want.kind {
+				t.Errorf("%s: field %s: expected kind %v, got %v", tt.path, name, want.kind, field.Type.Kind())
+			}
+			if tag := field.Tag.Get("json"); tag != want.tag {
+				t.Errorf("%s: field %s: expected json tag %q, got %q", tt.path, name, want.tag, tag)
+			}
+		}
+
+		properties, _ := schemaToJSON(schema)["properties"].(map[string]interface{})
+		if len(properties) != len(expected) {
+			t.Errorf("%s: expected %d properties in the emitted schema, got %v", tt.path, len(expected), properties)
+		}
+	}
+}
+
+func TestParseWellKnownTypesInCollections(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/wellknown/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	schemaType := spec.Paths["/schedule"].Get.Responses[200].Content[gopenapi.ApplicationJSON].Schema.Type
+	expectedTypes := map[string]reflect.Type{
+		"Times":     reflect.TypeOf([]time.Time{}),
+		"Window":    reflect.TypeOf([2]time.Time{}),
+		"Deadlines": reflect.TypeOf(map[string]time.Time{}),
+		"Timeouts":  reflect.TypeOf([]time.Duration{}),
+		"Nested":    reflect.TypeOf(map[string][]time.Time{}),
+	}
+	for name, want := range expectedTypes {
+		field, ok := schemaType.FieldByName(name)
+		if !ok {
+			t.Errorf("Expected field %s in %v", name, schemaType)
+			continue
+		}
+		if field.Type != want {
+			t.Errorf("Expected field %s to resolve to %v, got %v", name, want, field.Type)
+		}
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+	var result struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]any `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	dateTime := map[string]any{"type": "string", "format": "date-time"}
+	dateTimes := map[string]any{"type": "array", "items": dateTime}
+	expected := map[string]any{
+		"times":     dateTimes,
+		"window":    dateTimes,
+		"deadlines": map[string]any{"type": "object", "additionalProperties": dateTime},
+		"timeouts":  map[string]any{"type": "array", "items": map[string]any{"type": "integer"}},
+		"nested":    map[string]any{"type": "object", "additionalProperties": dateTimes},
+	}
+	properties := result.Paths["/schedule"]["get"].Responses["200"].Content["application/json"].Schema.Properties
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("Expected properties\n%v\ngot\n%v", expected, properties)
+	}
+}
+
+func TestParsePointerSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		varName string
+		title   string
+		path    string
+	}{
+		{name: "package variable", varName: "Spec", title: "Pointer API", path: "/health"},
+		{name: "local variable", varName: "spec", title: "Local API", path: "/ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecFromFileWithPath("testdata/pointerspec/spec.go", tt.varName, ".")
+			if err != nil {
+				t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+			}
+			if spec.Info.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, spec.Info.Title)
+			}
+			if _, ok := spec.Paths[tt.path]; !ok {
+				t.Errorf("Expected path %s, got %v", tt.path, spec.Paths)
+			}
+		})
+	}
+}
+
+func TestParseSpecBuiltByFunction(t *testing.T) {
+	tests := []struct {
+		varName string
+		title   string
+		path    string
+	}{
+		{varName: "Spec", title: "Built API", path: "/health"},
+		{varName: "PointerSpec", title: "Pointer Built API", path: "/ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.varName, func(t *testing.T) {
+			spec, err := ParseSpecFromFileWithPath("testdata/builtspec/spec.go", tt.varName, ".")
+			if err != nil {
+				t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+			}
+			if spec.Info.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, spec.Info.Title)
+			}
+			if _, ok := spec.Paths[tt.path]; !ok {
+				t.Errorf("Expected path %s, got %v", tt.path, spec.Paths)
+			}
+		})
+	}
+
+	t.Run("LoadedSpec", func(t *testing.T) {
+		_, err := ParseSpecFromFileWithPath("testdata/builtspec/spec.go", "LoadedSpec", ".")
+		if err == nil || !strings.Contains(err.Error(), "a call to a function in the same package that returns one") {
+			t.Errorf("Expected an error naming the supported forms, got %v", err)
+		}
+	})
+}
+
+func TestSpecToOpenAPIJSONComponents(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/components/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Components struct {
+			Schemas         map[string]map[string]any `json:"schemas"`
+			SecuritySchemes map[string]map[string]any `json:"securitySchemes"`
+		} `json:"components"`
+		Security []map[string][]string                `json:"security"`
+		Paths    map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	user := result.Components.Schemas["User"]
+	if user["type"] != "object" || user["properties"] == nil {
+		t.Errorf("Expected User to be an object schema with properties, got %v", user)
+	}
+	if got := result.Components.Schemas["UserReference"]["$ref"]; got != "#/components/schemas/User" {
+		t.Errorf("Expected UserReference $ref, got %v", got)
+	}
+
+	apiKey := result.Components.SecuritySchemes["apiKey"]
+	if apiKey["type"] != "apiKey" || apiKey["name"] != "X-API-KEY" || apiKey["in"] != "header" {
+		t.Errorf("Expected apiKey header scheme, got %v", apiKey)
+	}
+	bearer := result.Components.SecuritySchemes["bearer"]
+	if bearer["type"] != "http" || bearer["scheme"] != "bearer" {
+		t.Errorf("Expected bearer http scheme, got %v", bearer)
+	}
+
+	if len(result.Security) != 1 || result.Security[0]["apiKey"] == nil {
+		t.Errorf("Expected global apiKey security, got %v", result.Security)
+	}
+
+	createUser := result.Paths["/users"]["post"]
+	if security, _ := json.Marshal(createUser["security"]); string(security) != `[{"bearer":["users:write"]}]` {
+		t.Errorf("Expected createUser bearer security, got %s", security)
+	}
+	body := createUser["requestBody"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	if body["$ref"] != "#/components/schemas/User" {
+		t.Errorf("Expected request body $ref, got %v", body)
+	}
+	if security, ok := result.Paths["/health"]["get"]["security"].([]any); !ok || len(security) != 0 {
+		t.Errorf("Expected health to opt out with an empty security list, got %v", result.Paths["/health"]["get"]["security"])
+	}
+}
+
+func TestSpecToOpenAPIJSONExamples(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/examples/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Components struct {
+			Examples map[string]any `json:"examples"`
+		} `json:"components"`
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Examples map[string]any `json:"examples"`
+			} `json:"parameters"`
+			RequestBody struct {
+				Content map[string]struct {
+					Examples map[string]any `json:"examples"`
+				} `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Examples map[string]any `json:"examples"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{
+			name: "components",
+			got:  result.Components.Examples,
+			expected: map[string]any{
+				"Alice": map[string]any{
+					"summary": "A user",
+					"value":   map[string]any{"id": 1.0, "name": "Alice", "roles": []any{"admin"}},
+				},
+			},
+		},
+		{
+			name:     "parameter",
+			got:      result.Paths["/users"]["get"].Parameters[0].Examples,
+			expected: map[string]any{"alice": map[string]any{"value": "Alice"}},
+		},
+		{
+			name:     "request body",
+			got:      result.Paths["/users"]["post"].RequestBody.Content["application/json"].Examples,
+			expected: map[string]any{"alice": map[string]any{"$ref": "#/components/examples/Alice"}},
+		},
+		{
+			name: "response",
+			got:  result.Paths["/users"]["get"].Responses["200"].Content["application/json"].Examples,
+			expected: map[string]any{
+				"users": map[string]any{
+					"description": "Every user",
+					"value":       []any{map[string]any{"id": 1.0, "name": "Alice"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.expected) {
+				t.Errorf("Expected examples %v, got %v", tt.expected, tt.got)
+			}
+		})
+	}
+}
+
+func TestSpecToOpenAPIJSONPathParameters(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/pathparams/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+	if got := len(spec.Paths["/items/{id}"].Parameters); got != 2 {
+		t.Fatalf("Expected 2 path-level parameters, got %d", got)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Parameters []map[string]any `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	expected := map[string]map[string]string{
+		"get":    {"id": "Item ID", "verbose": ""},
+		"delete": {"id": "Item ID", "verbose": "Report what was deleted"},
+	}
+	for method, want := range expected {
+		got := map[string]string{}
+		for _, param := range result.Paths["/items/{id}"][method].Parameters {
+			// Parameters without a description leave it out
+			description, _ := param["description"].(string)
+			got[param["name"].(string)] = description
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %s parameters %v, got %v", method, want, got)
+		}
+	}
+}
+
+func TestParseReflectTypeOfShapes(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/reflecttypeof/shapes.go", "ShapesSpec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	object := spec.Paths["/object"].Get.Responses[200].Content[gopenapi.ApplicationJSON].Schema.Type
+	if object == nil || object.Kind() != reflect.Struct {
+		t.Fatalf("Expected Object[Item]() to resolve to a struct, got %v", object)
+	}
+
+	tests := []struct {
+		path string
+		kind reflect.Kind
+	}{
+		{"/value", reflect.Struct},            // reflect.TypeOf(Item{})
+		{"/slice", reflect.Slice},             // reflect.TypeOf([]Item{})
+		{"/nil-pointer", reflect.Ptr},         // reflect.TypeOf((*Item)(nil))
+		{"/nil-pointer-elem", reflect.Struct}, // reflect.TypeOf((*Item)(nil)).Elem()
+	}
+	for _, tt := range tests {
+		schemaType := spec.Paths[tt.path].Get.Responses[200].Content[gopenapi.ApplicationJSON].Schema.Type
+		if schemaType == nil || schemaType.Kind() != tt.kind {
+			t.Errorf("%s: expected kind %v, got %v", tt.path, tt.kind, schemaType)
+			continue
+		}
+		item := schemaType
+		if tt.kind != reflect.Struct {
+			item = schemaType.Elem()
+		}
+		if item != object {
+			t.Errorf("%s: expected the Item type of Object[Item](), got %v", tt.path, item)
+		}
+	}
+}
+
+func TestSpecToOpenAPIJSONOperationWithoutId(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/handleronly/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+
+	docs, ok := result.Paths["/docs"]["get"]
+	if !ok {
+		t.Fatalf("Expected the GET /docs operation without an operationId in the JSON, got %s", jsonData)
+	}
+	if _, ok := docs["operationId"]; ok {
+		t.Errorf("Expected no operationId, got %v", docs["operationId"])
+	}
+	if docs["summary"] != "API documentation" {
+		t.Errorf("Expected summary %q, got %v", "API documentation", docs["summary"])
+	}
+	responses, _ := docs["responses"].(map[string]any)
+	if ok200, _ := responses["200"].(map[string]any); ok200["description"] != "Documentation page" || ok200["content"] == nil {
+		t.Errorf("Expected the 200 response with its content, got %v", responses)
+	}
+}
+
+func TestSpecToOpenAPIJSONInterfaceFields(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/interfaces/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]any `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+	properties := result.Paths["/report"]["get"].Responses["200"].Content["application/json"].Schema.Properties
+
+	expectedTypes := map[string]string{
+		"err":     "string", // error
+		"failure": "string", // interface embedding error
+		"level":   "string", // interface with MarshalText
+		"shape":   "object", // any other interface
+	}
+	for name, expectedType := range expectedTypes {
+		if got := properties[name]["type"]; got != expectedType {
+			t.Errorf("Field %s: expected type %s, got %v", name, expectedType, got)
+		}
+	}
+
+	errors := properties["errors"]
+	items, _ := errors["items"].(map[string]any)
+	if errors["type"] != "array" || items["type"] != "string" {
+		t.Errorf("Expected []error to be an array of strings, got %v", errors)
+	}
+}
+
+type embeddedBase struct {
+	ID   int `json:"id"`
+	Kind string
+}
+
+type embeddedResource struct {
+	embeddedBase
+	Name string `json:"name"`
+	Kind int
+}
+
+func TestSpecToOpenAPIJSONEmbeddedFields(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/embedded/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	properties := responseProperties(t, &spec, "/resource")
+	expectedTypes := map[string]string{
+		"id":         "integer", // promoted from Base
+		"resourceId": "string",  // Resource's own ID
+		"created":    "integer", // Resource's Created shadows Base's
+		"updatedBy":  "string",  // promoted from the unexported *audit
+		"name":       "string",
+		"meta":       "object", // tagged, so not promoted
+	}
+	for name, expectedType := range expectedTypes {
+		if got := properties[name]["type"]; got != expectedType {
+			t.Errorf("Field %s: expected type %s, got %v", name, expectedType, got)
+		}
+	}
+	if len(properties) != len(expectedTypes) {
+		t.Errorf("Expected properties %v, got %v", expectedTypes, properties)
+	}
+
+	// Types built with reflect keep their embedded fields anonymous
+	runtimeSpec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/resource": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "getResource",
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Resource",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf(embeddedResource{})}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	properties = responseProperties(t, &runtimeSpec, "/resource")
+	if properties["id"]["type"] != "integer" || properties["name"] == nil {
+		t.Errorf("Expected the promoted id and name, got %v", properties)
+	}
+	if properties["Kind"]["type"] != "integer" {
+		t.Errorf("Expected the embedded Kind to be shadowed, got %v", properties)
+	}
+}
+
+// responseProperties returns the properties of the JSON schema of the 200
+// response of the GET operation at path
+func responseProperties(t *testing.T, spec *gopenapi.Spec, path string) map[string]map[string]any {
+	t.Helper()
+	jsonData, err := SpecToOpenAPIJSON(spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]any `json:"properties"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+	return result.Paths[path]["get"].Responses["200"].Content["application/json"].Schema.Properties
+}
+
+func TestSpecToOpenAPIJSONEmptyAnySchema(t *testing.T) {
+	type event struct {
+		Value  any            `json:"value"`
+		Values []any          `json:"values"`
+		Labels map[string]any `json:"labels"`
+	}
+	spec := gopenapi.Spec{
+		Paths: gopenapi.Paths{
+			"/events": gopenapi.Path{
+				Post: &gopenapi.Operation{
+					OperationId: "createEvent",
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf((*any)(nil)).Elem()}},
+						},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "Event",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf(event{})}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		opts SpecOptions
+		any  string
+	}{
+		{"object by default", SpecOptions{}, `{"type":"object"}`},
+		{"empty schema", SpecOptions{EmptyAnySchema: true}, `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonData, err := SpecToOpenAPIJSONWithOptions(&spec, tt.opts)
+			if err != nil {
+				t.Fatalf("SpecToOpenAPIJSONWithOptions() error = %v", err)
+			}
+
+			var result struct {
+				Paths map[string]map[string]struct {
+					RequestBody struct {
+						Content map[string]struct {
+							Schema json.RawMessage `json:"schema"`
+						} `json:"content"`
+					} `json:"requestBody"`
+					Responses map[string]struct {
+						Content map[string]struct {
+							Schema struct {
+								Properties map[string]json.RawMessage `json:"properties"`
+							} `json:"schema"`
+						} `json:"content"`
+					} `json:"responses"`
+				} `json:"paths"`
+			}
+			if err := json.Unmarshal(jsonData, &result); err != nil {
+				t.Fatalf("Generated JSON is invalid: %v", err)
+			}
+			op := result.Paths["/events"]["post"]
+			properties := op.Responses["200"].Content["application/json"].Schema.Properties
+
+			compact := func(raw json.RawMessage) string {
+				var buf bytes.Buffer
+				if err := json.Compact(&buf, raw); err != nil {
+					t.Fatalf("Invalid schema %s: %v", raw, err)
+				}
+				return buf.String()
+			}
+			expected := map[string]string{
+				"request body": tt.any,
+				"value":        tt.any,
+				"values":       `{"items":` + tt.any + `,"type":"array"}`,
+				"labels":       `{"additionalProperties":` + tt.any + `,"type":"object"}`,
+			}
+			actual := map[string]string{
+				"request body": compact(op.RequestBody.Content["application/json"].Schema),
+				"value":        compact(properties["value"]),
+				"values":       compact(properties["values"]),
+				"labels":       compact(properties["labels"]),
+			}
+			for name, want := range expected {
+				if actual[name] != want {
+					t.Errorf("%s: expected schema %s, got %s", name, want, actual[name])
+				}
+			}
+		})
+	}
+}
+
+func TestSpecToOpenAPIJSONSkipsIgnoredFields(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/jsonskip/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+
+	properties := responseProperties(t, &spec, "/account")
+	for _, name := range []string{"id", "-"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Expected property %q, got %v", name, properties)
+		}
+	}
+	for _, name := range []string{"Secret", "Internal", "shard"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Expected the json:\"-\" field %s to be omitted, got %v", name, properties)
+		}
+	}
+}
+
+func TestSpecToOpenAPIJSONTagOptions(t *testing.T) {
+	spec, err := ParseSpecFromFileWithPath("testdata/tagoptions/spec.go", "Spec", ".")
+	if err != nil {
+		t.Fatalf("ParseSpecFromFileWithPath() error = %v", err)
+	}
+	jsonData, err := SpecToOpenAPIJSON(&spec)
+	if err != nil {
+		t.Fatalf("SpecToOpenAPIJSON() error = %v", err)
+	}
+
+	var result struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]any `json:"properties"`
+						Required   []string                  `json:"required"`
+					} `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v", err)
+	}
+	schema := result.Paths["/order"]["get"].Responses["200"].Content["application/json"].Schema
+
+	// The string option encodes numbers as JSON strings, but not slices
+	for name, expected := range map[string]string{"id": "string", "quantity": "string", "total": "number", "tags": "array"} {
+		if schema.Properties[name]["type"] != expected {
+			t.Errorf("Expected %s to have type %s, got %v", name, expected, schema.Properties[name])
+		}
+	}
+	// Fields with omitempty are optional
+	sort.Strings(schema.Required)
+	if expected := []string{"id", "tags", "total"}; !reflect.DeepEqual(schema.Required, expected) {
+		t.Errorf("Expected required %v, got %v", expected, schema.Required)
+	}
+}
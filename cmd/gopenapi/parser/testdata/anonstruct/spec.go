@@ -0,0 +1,37 @@
+package anonstruct
+
+import "github.com/runpod/gopenapi"
+
+type Address struct {
+	City string `json:"city"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Anonymous Struct API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Post: &gopenapi.Operation{
+				OperationId: "createUser",
+				RequestBody: gopenapi.RequestBody{
+					Content: gopenapi.Content{
+						gopenapi.ApplicationJSON: {
+							Schema: gopenapi.Schema{Type: gopenapi.Object[struct {
+								Name    string   `json:"name"`
+								Age     int      `json:"age,omitempty"`
+								Tags    []string `json:"tags"`
+								Address Address  `json:"address"`
+							}]()},
+						},
+					},
+				},
+				Responses: gopenapi.Responses{
+					201: {Description: "Created"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,32 @@
+package arrayparams
+
+import "github.com/runpod/gopenapi"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Array Parameters API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/items": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listItems",
+				Parameters: gopenapi.Parameters{
+					{
+						Name:    "ids",
+						In:      gopenapi.InQuery,
+						Explode: gopenapi.Ptr(false),
+						Schema: gopenapi.Schema{
+							Type:  gopenapi.Array,
+							Items: &gopenapi.Schema{Type: gopenapi.Integer},
+						},
+					},
+				},
+				Responses: gopenapi.Responses{
+					200: {Description: "Items"},
+				},
+			},
+		},
+	},
+}
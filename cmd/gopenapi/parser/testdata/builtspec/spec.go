@@ -0,0 +1,57 @@
+package builtspec
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+var Spec = buildSpec()
+
+var PointerSpec = newSpec("Built API")
+
+var LoadedSpec = loadSpec()
+
+func buildSpec() gopenapi.Spec {
+	return gopenapi.Spec{
+		OpenAPI: "3.1.0",
+		Info: gopenapi.Info{
+			Title:   "Built API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/health": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "health",
+					Responses: gopenapi.Responses{
+						200: {Description: "OK"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newSpec(title string) *gopenapi.Spec {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.1.0",
+		Info: gopenapi.Info{
+			Title:   "Pointer Built API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/ping": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "ping",
+					Responses: gopenapi.Responses{
+						200: {Description: "Pong"},
+					},
+				},
+			},
+		},
+	}
+	return spec
+}
+
+func loadSpec() gopenapi.Spec {
+	var spec gopenapi.Spec
+	return spec
+}
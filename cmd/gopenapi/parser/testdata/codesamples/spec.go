@@ -0,0 +1,35 @@
+package codesamples
+
+import "github.com/runpod/gopenapi"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Code Samples API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				CodeSamples: gopenapi.CodeSamples{
+					{
+						Lang:   "shell",
+						Label:  "curl",
+						Source: "curl https://api.example.com/users",
+					},
+					{
+						Lang: "go",
+						Source: `users, err := client.ListUsers(ctx)
+if err != nil {
+	return err
+}`,
+					},
+				},
+				Responses: gopenapi.Responses{
+					200: {Description: "Users"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,69 @@
+package components
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+type User struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+const userRef = "#/components/schemas/User"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Components API",
+		Version: "1.0.0",
+	},
+	Components: gopenapi.Components{
+		Schemas: gopenapi.Schemas{
+			"User": {
+				Type: gopenapi.Object[User](),
+			},
+			"UserReference": {
+				Ref: userRef,
+			},
+		},
+		SecuritySchemes: gopenapi.SecuritySchemes{
+			"apiKey": {
+				Type: gopenapi.APIKey,
+				Name: "X-API-KEY",
+				In:   gopenapi.InHeader,
+			},
+			"bearer": {
+				Type:   gopenapi.HTTP,
+				Scheme: gopenapi.BearerScheme,
+			},
+		},
+	},
+	Security: []gopenapi.Security{
+		{"apiKey": []string{}},
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Post: &gopenapi.Operation{
+				OperationId: "createUser",
+				Security:    []gopenapi.Security{{"bearer": []string{"users:write"}}},
+				RequestBody: gopenapi.RequestBody{
+					Content: gopenapi.Content{
+						gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: userRef}},
+					},
+				},
+				Responses: gopenapi.Responses{
+					201: {Description: "Created"},
+				},
+			},
+		},
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Security:    gopenapi.NoSecurity,
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
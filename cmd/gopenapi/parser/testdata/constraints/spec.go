@@ -0,0 +1,52 @@
+package constraints
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+const maxPage = 100
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.0.0",
+	Info: gopenapi.Info{
+		Title:   "Constraints API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/items": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listItems",
+				Parameters: gopenapi.Parameters{
+					{
+						Name:   "page",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.Integer, Minimum: gopenapi.Ptr(1.0), Maximum: gopenapi.Ptr[float64](maxPage)},
+					},
+					{
+						Name:   "ratio",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.Number, Minimum: gopenapi.Ptr(-0.5), ExclusiveMinimum: true, Maximum: gopenapi.Ptr(1.0), ExclusiveMaximum: true},
+					},
+					{
+						Name:   "slug",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.String, MinLength: gopenapi.Ptr(1), MaxLength: gopenapi.Ptr(64), Pattern: `^[a-z0-9-]+$`},
+					},
+					{
+						Name:   "filter",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.String, ContentMediaType: "application/json", ContentEncoding: "base64"},
+					},
+					{
+						Name:   "sort",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.String, Enum: []any{"asc", "desc"}},
+					},
+				},
+				Responses: gopenapi.Responses{
+					200: {Description: "Items"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,47 @@
+package defaults
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+const defaultOrder = "desc"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Defaults API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/items": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listItems",
+				Parameters: gopenapi.Parameters{
+					{
+						Name:   "limit",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.Integer, Default: 20},
+					},
+					{
+						Name:   "order",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.String, Default: defaultOrder},
+					},
+					{
+						Name:   "archived",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.Boolean, Default: false},
+					},
+					{
+						Name:   "X-Region",
+						In:     gopenapi.InHeader,
+						Schema: gopenapi.Schema{Type: gopenapi.String},
+					},
+				},
+				Responses: gopenapi.Responses{
+					200: {Description: "Items"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,31 @@
+package descriptions
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title: "Descriptions API",
+		Description: `# Descriptions API
+
+Manages **items**.
+
+- Supports "quotes", \escapes and <br> & HTML
+- Keeps ` + "`code`" + ` spans`,
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/items": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listItems",
+				Description: "Lists items.\n\nResults are \"paged\".",
+				Responses: gopenapi.Responses{
+					200: {Description: `A page
+of items`},
+				},
+			},
+		},
+	},
+}
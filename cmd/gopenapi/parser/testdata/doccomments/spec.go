@@ -0,0 +1,37 @@
+package doccomments
+
+import "github.com/runpod/gopenapi"
+
+// Spec describes the users API.
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Users API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			// List all users, most recently created first.
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				Responses: gopenapi.Responses{
+					200: {Description: "Users"},
+				},
+			},
+			// Create a user.
+			Post: &gopenapi.Operation{
+				OperationId: "createUser",
+				Description: "Creates a user from the request body.",
+				Responses: gopenapi.Responses{
+					201: {Description: "Created"},
+				},
+			}, // Not a doc comment
+			Delete: &gopenapi.Operation{
+				OperationId: "deleteUsers",
+				Responses: gopenapi.Responses{
+					204: {Description: "Deleted"},
+				},
+			},
+		},
+	},
+}
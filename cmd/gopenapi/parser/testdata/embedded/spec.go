@@ -0,0 +1,55 @@
+package embedded
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+// Base holds the fields shared by resources
+type Base struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+	Kind    string
+}
+
+type audit struct {
+	UpdatedBy string `json:"updatedBy"`
+	Kind      string
+}
+
+type Meta struct {
+	Labels []string `json:"labels"`
+}
+
+// Resource promotes the fields of Base and audit. Its own Created shadows
+// Base's, and Kind, declared by both embedded structs, is dropped.
+type Resource struct {
+	Base
+	*audit
+	Meta    `json:"meta"`
+	ID      string `json:"resourceId"`
+	Name    string `json:"name"`
+	Created int64  `json:"created"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Embedded API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/resource": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getResource",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "Resource",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Resource]()}},
+						},
+					},
+				},
+			},
+		},
+	},
+}
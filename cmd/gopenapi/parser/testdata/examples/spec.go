@@ -0,0 +1,78 @@
+package examples
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+type User struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+const aliceRef = "#/components/examples/Alice"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Examples API",
+		Version: "1.0.0",
+	},
+	Components: gopenapi.Components{
+		Examples: gopenapi.Examples{
+			"Alice": {
+				Summary: "A user",
+				Value:   User{ID: 1, Name: "Alice", Roles: []string{"admin"}},
+			},
+		},
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				Parameters: gopenapi.Parameters{
+					{
+						Name:   "name",
+						In:     gopenapi.InQuery,
+						Schema: gopenapi.Schema{Type: gopenapi.String},
+						Examples: gopenapi.Examples{
+							"alice": {Value: "Alice"},
+						},
+					},
+				},
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "OK",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {
+								Schema: gopenapi.Schema{Type: gopenapi.Object[[]User]()},
+								Examples: gopenapi.Examples{
+									"users": {
+										Description: "Every user",
+										Value:       []map[string]any{{"id": 1, "name": "Alice"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Post: &gopenapi.Operation{
+				OperationId: "createUser",
+				RequestBody: gopenapi.RequestBody{
+					Content: gopenapi.Content{
+						gopenapi.ApplicationJSON: {
+							Schema: gopenapi.Schema{Type: gopenapi.Object[User]()},
+							Examples: gopenapi.Examples{
+								"alice": {Ref: aliceRef},
+							},
+						},
+					},
+				},
+				Responses: gopenapi.Responses{
+					201: {Description: "Created"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,43 @@
+package handleronly
+
+import (
+	"net/http"
+
+	"github.com/runpod/gopenapi"
+)
+
+func docsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<html></html>"))
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Handler Only API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/docs": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				Summary: "API documentation",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "Documentation page",
+						Content: gopenapi.Content{
+							gopenapi.TextHTML: {Schema: gopenapi.Schema{Type: gopenapi.String}},
+						},
+					},
+				},
+				Handler: http.HandlerFunc(docsHandler),
+			},
+		},
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,52 @@
+package interfaces
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+// Shape can hold any shape, so its values have no fixed schema
+type Shape interface {
+	Area() float64
+}
+
+// Level is marshaled by its text, e.g. "warning"
+type Level interface {
+	MarshalText() ([]byte, error)
+}
+
+// Failure is an error with a code, reported by its message
+type Failure interface {
+	error
+	Code() int
+}
+
+type Report struct {
+	Err     error   `json:"err"`
+	Errors  []error `json:"errors"`
+	Failure Failure `json:"failure"`
+	Level   Level   `json:"level"`
+	Shape   Shape   `json:"shape"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Interfaces API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/report": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getReport",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "Report",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Report]()}},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,40 @@
+package jsonskip
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+type Internal struct {
+	Shard int `json:"shard"`
+}
+
+type Account struct {
+	Internal `json:"-"`
+	ID       int    `json:"id"`
+	Secret   string `json:"-"`
+	// Dash is named "-", as the comma keeps the tag from skipping it
+	Dash string `json:"-,"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Skipped Fields API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/account": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getAccount",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "Account",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Account]()}},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,37 @@
+package multipart
+
+import (
+	"io"
+
+	"github.com/runpod/gopenapi"
+)
+
+type UploadAvatarRequest struct {
+	UserID string    `json:"user_id"`
+	File   io.Reader `json:"file"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Upload API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/avatars": gopenapi.Path{
+			Post: &gopenapi.Operation{
+				OperationId: "uploadAvatar",
+				RequestBody: gopenapi.RequestBody{
+					Content: gopenapi.Content{
+						gopenapi.MultipartFormData: {
+							Schema: gopenapi.Schema{Type: gopenapi.Object[UploadAvatarRequest]()},
+						},
+					},
+				},
+				Responses: gopenapi.Responses{
+					201: {Description: "Uploaded"},
+				},
+			},
+		},
+	},
+}
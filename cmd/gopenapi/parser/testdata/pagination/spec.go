@@ -0,0 +1,30 @@
+package pagination
+
+import "github.com/runpod/gopenapi"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Pagination API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				Paginated:   true,
+				Responses: gopenapi.Responses{
+					200: {Description: "A page of users"},
+				},
+			},
+		},
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
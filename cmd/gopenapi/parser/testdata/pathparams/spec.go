@@ -0,0 +1,36 @@
+package pathparams
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Path Parameters API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/items/{id}": gopenapi.Path{
+			Parameters: gopenapi.Parameters{
+				{Name: "id", In: gopenapi.InPath, Required: true, Description: "Item ID", Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+				{Name: "verbose", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Boolean}},
+			},
+			Get: &gopenapi.Operation{
+				OperationId: "getItem",
+				Responses: gopenapi.Responses{
+					200: {Description: "Item"},
+				},
+			},
+			Delete: &gopenapi.Operation{
+				OperationId: "deleteItem",
+				Parameters: gopenapi.Parameters{
+					{Name: "verbose", In: gopenapi.InQuery, Description: "Report what was deleted", Schema: gopenapi.Schema{Type: gopenapi.Boolean}},
+				},
+				Responses: gopenapi.Responses{
+					204: {Description: "Deleted"},
+				},
+			},
+		},
+	},
+}
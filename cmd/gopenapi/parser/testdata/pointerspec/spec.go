@@ -0,0 +1,48 @@
+package pointerspec
+
+import (
+	"net/http"
+
+	"github.com/runpod/gopenapi"
+)
+
+// Spec describes the pointer API
+var Spec = &gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Pointer API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
+
+// NewMux builds its spec inside the function, as the examples do
+func NewMux() (http.Handler, error) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.1.0",
+		Info: gopenapi.Info{
+			Title:   "Local API",
+			Version: "2.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/ping": gopenapi.Path{
+				Get: &gopenapi.Operation{
+					OperationId: "ping",
+					Responses: gopenapi.Responses{
+						200: {Description: "Pong"},
+					},
+				},
+			},
+		},
+	}
+	return gopenapi.NewServerMux(spec)
+}
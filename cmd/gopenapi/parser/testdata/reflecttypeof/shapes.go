@@ -0,0 +1,62 @@
+package reflecttypeof
+
+import (
+	"reflect"
+
+	"github.com/runpod/gopenapi"
+)
+
+type Item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var ShapesSpec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "reflect.TypeOf shapes API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/value": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "value",
+				Responses: gopenapi.Responses{
+					200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf(Item{})}}}},
+				},
+			},
+		},
+		"/slice": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "slice",
+				Responses: gopenapi.Responses{
+					200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf([]Item{})}}}},
+				},
+			},
+		},
+		"/nil-pointer": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "nilPointer",
+				Responses: gopenapi.Responses{
+					200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf((*Item)(nil))}}}},
+				},
+			},
+		},
+		"/nil-pointer-elem": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "nilPointerElem",
+				Responses: gopenapi.Responses{
+					200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf((*Item)(nil)).Elem()}}}},
+				},
+			},
+		},
+		"/object": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "object",
+				Responses: gopenapi.Responses{
+					200: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Item]()}}}},
+				},
+			},
+		},
+	},
+}
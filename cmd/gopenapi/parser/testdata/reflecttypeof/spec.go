@@ -0,0 +1,48 @@
+package reflecttypeof
+
+import (
+	"reflect"
+
+	"github.com/runpod/gopenapi"
+	"github.com/runpod/gopenapi/cmd/gopenapi/parser/internal/mock"
+)
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "reflect.TypeOf API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users/{id}": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getUser",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "The user",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {
+								Schema: gopenapi.Schema{Type: reflect.TypeOf(mock.User{})},
+							},
+						},
+					},
+				},
+			},
+		},
+		"/users": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "The users",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {
+								Schema: gopenapi.Schema{Type: reflect.TypeOf(&mock.User{})},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,36 @@
+package tagoptions
+
+import (
+	"github.com/runpod/gopenapi"
+)
+
+type Order struct {
+	ID       int64   `json:"id,string"`
+	Quantity *int    `json:"quantity,string,omitempty"`
+	Note     string  `json:"note,omitempty"`
+	Total    float64 `json:"total"`
+	Tags     []int   `json:"tags,string"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Tag Options API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/order": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getOrder",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "Order",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Order]()}},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,30 @@
+package tags
+
+import "github.com/runpod/gopenapi"
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Tags API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/users": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "listUsers",
+				Tags:        []string{"users", "admin"},
+				Responses: gopenapi.Responses{
+					200: {Description: "Users"},
+				},
+			},
+		},
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,43 @@
+package timeout
+
+import (
+	"time"
+
+	"github.com/runpod/gopenapi"
+)
+
+const generationTimeout = 2 * time.Minute
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Timeout API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/generations": gopenapi.Path{
+			Post: &gopenapi.Operation{
+				OperationId: "createGeneration",
+				Timeout:     generationTimeout,
+				Responses: gopenapi.Responses{
+					200: {Description: "Generation"},
+				},
+			},
+			Get: &gopenapi.Operation{
+				OperationId: "listGenerations",
+				Timeout:     1500 * time.Millisecond,
+				Responses: gopenapi.Responses{
+					200: {Description: "Generations"},
+				},
+			},
+		},
+		"/health": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "health",
+				Responses: gopenapi.Responses{
+					200: {Description: "OK"},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,40 @@
+package wellknown
+
+import (
+	"time"
+
+	"github.com/runpod/gopenapi"
+)
+
+type Schedule struct {
+	Times     []time.Time            `json:"times"`
+	Window    [2]time.Time           `json:"window"`
+	Deadlines map[string]time.Time   `json:"deadlines"`
+	Timeouts  []time.Duration        `json:"timeouts"`
+	Nested    map[string][]time.Time `json:"nested"`
+}
+
+var Spec = gopenapi.Spec{
+	OpenAPI: "3.1.0",
+	Info: gopenapi.Info{
+		Title:   "Well-known types API",
+		Version: "1.0.0",
+	},
+	Paths: gopenapi.Paths{
+		"/schedule": gopenapi.Path{
+			Get: &gopenapi.Operation{
+				OperationId: "getSchedule",
+				Responses: gopenapi.Responses{
+					200: {
+						Description: "The schedule",
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {
+								Schema: gopenapi.Schema{Type: gopenapi.Object[Schedule]()},
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/runpod/gopenapi/cmd/gopenapi/linter"
+	"github.com/runpod/gopenapi/cmd/gopenapi/parser"
+)
+
+func validateCommand() {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
+	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
+	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	queryParamCase := fs.String("query-param-case", "", "Require query parameter names in this casing (camel, snake); disabled when empty")
+	help := fs.Bool("help", false, "Show help information")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Check an OpenAPI spec in Go code for contract problems
+
+Usage:
+  gopenapi validate [flags]
+
+Flags:
+  -spec string
+        Go file containing the OpenAPI spec (required)
+  -var string
+        Variable name containing the spec (required, e.g., 'ExampleSpec')
+  -path string
+        Working directory for package resolution (defaults to current directory)
+  -query-param-case string
+        Require query parameter names in this casing: camel, snake (disabled when empty)
+  -help
+        Show this help message
+
+Exits with status 1 and lists the findings when any rule fails.
+
+Rules:
+%s
+Examples:
+  gopenapi validate -spec examples/spec/spec.go -var ExampleSpec
+  gopenapi validate -spec examples/spec/spec.go -var ExampleSpec -query-param-case camel
+`, ruleList(linter.DefaultRules()))
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *help {
+		fs.Usage()
+		return
+	}
+
+	if *specFile == "" || *specVar == "" {
+		fmt.Fprintf(os.Stderr, "Error: Both -spec and -var flags are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	rules := linter.DefaultRules()
+	if *queryParamCase != "" {
+		casing, err := linter.ParseCasing(*queryParamCase)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			os.Exit(1)
+		}
+		rules = append(rules, linter.QueryParamCaseRule(casing))
+	}
+
+	// Use current directory if path not specified
+	workingDir := *path
+	if workingDir == "" {
+		var err error
+		workingDir, err = os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get current directory: %v", err)
+		}
+	}
+
+	spec, err := parser.ParseSpecFromFileWithPath(*specFile, *specVar, workingDir)
+	if err != nil {
+		log.Fatalf("Failed to parse spec from file: %v", err)
+	}
+
+	findings := linter.Lint(&spec, rules)
+	if len(findings) > 0 {
+		fmt.Fprintf(os.Stderr, "%s (%s) has %d problem(s):\n", *specFile, *specVar, len(findings))
+		for _, finding := range findings {
+			fmt.Fprintf(os.Stderr, "  %s\n", finding)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s (%s) passed validation\n", *specFile, *specVar)
+}
+
+// ruleList formats rules for the validate usage text
+func ruleList(rules []linter.Rule) string {
+	list := ""
+	for _, rule := range rules {
+		list += fmt.Sprintf("  %-24s %s\n", rule.Name, rule.Description)
+	}
+	return list
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/runpod/gopenapi/cmd/gopenapi/parser"
+)
+
+func verifyCommand() {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Go file containing the OpenAPI spec (required)")
+	specVar := fs.String("var", "", "Variable name containing the spec (required, e.g., 'ExampleSpec')")
+	url := fs.String("url", "", "URL of the live OpenAPI JSON document (required, e.g., 'http://localhost:8080/openapi.json')")
+	path := fs.String("path", "", "Working directory for package resolution (defaults to current directory)")
+	operationIdCase := fs.String("operationid-case", "preserve", "Casing policy for operationIds (preserve, camel, pascal)")
+	emptyAnySchema := fs.Bool("empty-any-schema", false, "Describe interface{} and any values with the empty schema {}, as servers with Spec.OpenAPIOptions.EmptyAnySchema serve them")
+	help := fs.Bool("help", false, "Show help information")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, `Verify that a live server's OpenAPI JSON matches the spec in Go code
+
+Usage:
+  gopenapi verify [flags]
+
+Flags:
+  -spec string
+        Go file containing the OpenAPI spec (required)
+  -var string
+        Variable name containing the spec (required, e.g., 'ExampleSpec')
+  -url string
+        URL of the live OpenAPI JSON document (required, e.g., 'http://localhost:8080/openapi.json')
+  -path string
+        Working directory for package resolution (defaults to current directory)
+  -operationid-case string
+        Casing policy for operationIds (preserve, camel, pascal) (default "preserve")
+  -empty-any-schema
+        Describe interface{} and any values with the empty schema {}, as servers
+        setting Spec.OpenAPIOptions.EmptyAnySchema serve them
+  -help
+        Show this help message
+
+Exits with status 1 and lists the differences when the documents do not match.
+
+Examples:
+  gopenapi verify -spec examples/spec/spec.go -var ExampleSpec -url http://localhost:8080/openapi.json
+`)
+	}
+
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *help {
+		fs.Usage()
+		return
+	}
+
+	if *specFile == "" || *specVar == "" || *url == "" {
+		fmt.Fprintf(os.Stderr, "Error: -spec, -var and -url flags are required\n\n")
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	idCase, err := parser.ParseOperationIdCase(*operationIdCase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	// Use current directory if path not specified
+	workingDir := *path
+	if workingDir == "" {
+		workingDir, err = os.Getwd()
+		if err != nil {
+			log.Fatalf("Failed to get current directory: %v", err)
+		}
+	}
+
+	spec, err := parser.ParseSpecFromFileWithPath(*specFile, *specVar, workingDir)
+	if err != nil {
+		log.Fatalf("Failed to parse spec from file: %v", err)
+	}
+
+	expected, err := parser.SpecToOpenAPIJSONWithOptions(&spec, parser.SpecOptions{
+		OperationIdCase: idCase,
+		EmptyAnySchema:  *emptyAnySchema,
+	})
+	if err != nil {
+		log.Fatalf("Failed to convert spec to OpenAPI JSON: %v", err)
+	}
+
+	actual, err := fetchOpenAPIJSON(&http.Client{Timeout: 30 * time.Second}, *url)
+	if err != nil {
+		log.Fatalf("Failed to fetch live OpenAPI JSON: %v", err)
+	}
+
+	differences, err := diffOpenAPIJSON(expected, actual)
+	if err != nil {
+		log.Fatalf("Failed to compare OpenAPI JSON: %v", err)
+	}
+
+	if len(differences) > 0 {
+		fmt.Fprintf(os.Stderr, "Live spec at %s differs from %s (%s):\n", *url, *specFile, *specVar)
+		for _, difference := range differences {
+			fmt.Fprintf(os.Stderr, "  %s\n", difference)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("Live spec at %s matches %s (%s)\n", *url, *specFile, *specVar)
+}
+
+// fetchOpenAPIJSON downloads the OpenAPI document served at url
+func fetchOpenAPIJSON(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return body, nil
+}
+
+// diffOpenAPIJSON compares two OpenAPI JSON documents semantically and returns
+// a sorted list of human-readable differences, empty when they match
+func diffOpenAPIJSON(expected, actual []byte) ([]string, error) {
+	var expectedDoc, actualDoc any
+	if err := json.Unmarshal(expected, &expectedDoc); err != nil {
+		return nil, fmt.Errorf("invalid expected document: %w", err)
+	}
+	if err := json.Unmarshal(actual, &actualDoc); err != nil {
+		return nil, fmt.Errorf("invalid live document: %w", err)
+	}
+
+	var differences []string
+	diffJSONValues("$", expectedDoc, actualDoc, &differences)
+	sort.Strings(differences)
+	return differences, nil
+}
+
+// diffJSONValues recursively compares decoded JSON values, recording differences under the given path
+func diffJSONValues(path string, expected, actual any, differences *[]string) {
+	expectedObj, expectedIsObj := expected.(map[string]any)
+	actualObj, actualIsObj := actual.(map[string]any)
+	if expectedIsObj && actualIsObj {
+		for key, expectedValue := range expectedObj {
+			actualValue, ok := actualObj[key]
+			if !ok {
+				*differences = append(*differences, fmt.Sprintf("%s: missing from live spec", jsonPath(path, key)))
+				continue
+			}
+			diffJSONValues(jsonPath(path, key), expectedValue, actualValue, differences)
+		}
+		for key := range actualObj {
+			if _, ok := expectedObj[key]; !ok {
+				*differences = append(*differences, fmt.Sprintf("%s: not declared in source spec", jsonPath(path, key)))
+			}
+		}
+		return
+	}
+
+	expectedArr, expectedIsArr := expected.([]any)
+	actualArr, actualIsArr := actual.([]any)
+	if expectedIsArr && actualIsArr && len(expectedArr) == len(actualArr) {
+		for i := range expectedArr {
+			diffJSONValues(fmt.Sprintf("%s[%d]", path, i), expectedArr[i], actualArr[i], differences)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		expectedJSON, _ := json.Marshal(expected)
+		actualJSON, _ := json.Marshal(actual)
+		*differences = append(*differences, fmt.Sprintf("%s: expected %s, got %s", path, expectedJSON, actualJSON))
+	}
+}
+
+// jsonPath appends an object key to a JSON path, quoting keys that are not plain identifiers
+func jsonPath(path, key string) string {
+	if key == "" || strings.ContainsAny(key, "./{}[] ") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	return path + "." + key
+}
@@ -0,0 +1,81 @@
+package gopenapi
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS configures the Cross-Origin Resource Sharing headers set by servers
+// created from a spec. Preflight requests are answered without reaching the
+// operation handlers.
+type CORS struct {
+	// AllowedOrigins lists the origins allowed to make requests, e.g.
+	// "https://app.example.com". "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in preflight requests. Defaults
+	// to GET, HEAD and POST.
+	AllowedMethods []string
+	// AllowedHeaders lists the request headers allowed in preflight requests
+	AllowedHeaders []string
+	// AllowCredentials allows requests with cookies or HTTP authentication
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the result of a preflight request,
+	// in whole seconds. Zero leaves the browser default.
+	MaxAge time.Duration
+}
+
+// Handler wraps next to set the Access-Control-* headers on responses to
+// allowed origins and to answer preflight OPTIONS requests
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		requestMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && origin != "" && requestMethod != "" {
+			header := w.Header()
+			header.Add("Vary", "Origin")
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			if c.allowsOrigin(origin) && slices.Contains(methods, requestMethod) {
+				c.setAllowOrigin(header, origin)
+				header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+				if len(c.AllowedHeaders) > 0 {
+					header.Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+				}
+				if c.MaxAge > 0 {
+					header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.MaxAge/time.Second)))
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if origin != "" {
+			w.Header().Add("Vary", "Origin")
+			if c.allowsOrigin(origin) {
+				c.setAllowOrigin(w.Header(), origin)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *CORS) allowsOrigin(origin string) bool {
+	return slices.Contains(c.AllowedOrigins, "*") || slices.Contains(c.AllowedOrigins, origin)
+}
+
+// setAllowOrigin echoes the request origin, since "*" is not accepted by
+// browsers for requests with credentials
+func (c *CORS) setAllowOrigin(header http.Header, origin string) {
+	header.Set("Access-Control-Allow-Origin", origin)
+	if c.AllowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
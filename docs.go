@@ -0,0 +1,93 @@
+package gopenapi
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:generate ./update_swagger_ui.sh
+
+//go:embed docs.html
+var docsHTML string
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsHTML))
+
+// docsAssets holds the docs page script and the swagger-ui-dist files vendored
+// by update_swagger_ui.sh
+//
+//go:embed swaggerui
+var docsAssets embed.FS
+
+var docsAssetsFS, _ = fs.Sub(docsAssets, "swaggerui")
+
+// DefaultDocsAssetsURL is the pinned swagger-ui-dist release on jsDelivr the
+// docs page falls back to when the package is built without the vendored
+// files, e.g. before `go generate` has run update_swagger_ui.sh
+const DefaultDocsAssetsURL = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5.17.14"
+
+// swaggerUIEmbedded reports whether the swagger-ui-dist files are embedded
+func swaggerUIEmbedded() bool {
+	_, err := fs.Stat(docsAssetsFS, "swagger-ui-bundle.js")
+	return err == nil
+}
+
+// DocsHandler serves a Swagger UI page for spec that loads the OpenAPI
+// document from specURL. The page loads its script and the Swagger UI files
+// from Spec.DocsPath, where DocsAssetsHandler must be mounted, or the Swagger
+// UI files from Spec.DocsAssetsURL when set.
+func DocsHandler(spec *Spec, specURL string) http.Handler {
+	docsPath := strings.TrimSuffix(spec.DocsPath, "/")
+	assetsURL := spec.DocsAssetsURL
+	if assetsURL == "" {
+		assetsURL = docsPath
+		if !swaggerUIEmbedded() {
+			assetsURL = DefaultDocsAssetsURL
+		}
+	}
+	var page bytes.Buffer
+	err := docsTemplate.Execute(&page, struct {
+		Title     string
+		SpecURL   string
+		DocsPath  string
+		AssetsURL string
+	}{
+		Title:     spec.Info.Title,
+		SpecURL:   specURL,
+		DocsPath:  docsPath,
+		AssetsURL: strings.TrimSuffix(assetsURL, "/"),
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page.Bytes())
+	})
+}
+
+// DocsAssetsHandler serves the embedded docs page script and swagger-ui-dist
+// files by name, e.g. /swagger-ui-bundle.js. Strip the docs path before it, as
+// NewServerMux does for Spec.DocsPath.
+func DocsAssetsHandler() http.Handler {
+	return http.FileServerFS(docsAssetsFS)
+}
+
+// OpenAPIJSONHandler serves spec as an OpenAPI JSON document rendered by
+// MarshalOpenAPIJSON with Spec.OpenAPIOptions, byte for byte the document the
+// gopenapi CLI generates from the same spec and options
+func OpenAPIJSONHandler(spec *Spec) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		document, err := MarshalOpenAPIJSON(spec, spec.OpenAPIOptions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", string(ApplicationJSON))
+		w.Write(document)
+	})
+}
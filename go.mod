@@ -2,7 +2,10 @@ module github.com/runpod/gopenapi
 
 go 1.24.0
 
-require golang.org/x/tools v0.33.0
+require (
+	golang.org/x/tools v0.33.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	golang.org/x/mod v0.24.0 // indirect
@@ -4,12 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 type Middleware interface {
@@ -24,16 +30,37 @@ type Operation struct {
 	Tags        []string   `json:"tags,omitempty"`
 	Deprecated  bool       `json:"deprecated,omitempty"`
 	Parameters  Parameters `json:"parameters,omitempty"`
-	Security    []Security `json:"security,omitempty"`
+	// Security overrides Spec.Security for this operation. Operations that leave
+	// it nil inherit Spec.Security; NoSecurity makes the operation public.
+	Security []Security `json:"security,omitempty"`
 	// OpenAPI operation ID
 	OperationId string `json:"operationId,omitempty"`
 	// Request body schema for OpenAPI
 	RequestBody RequestBody `json:"requestBody,omitempty"`
 	// Response schemas for OpenAPI, keyed by status code
-	Responses Responses    `json:"responses,omitempty"`
+	Responses Responses `json:"responses,omitempty"`
+	// Example requests emitted as the x-codeSamples extension
+	CodeSamples CodeSamples `json:"x-codeSamples,omitempty"`
+	// Default timeout for generated clients, emitted as the x-timeout extension
+	// (e.g. "2m0s"). Clients apply it when the caller's context has no deadline.
+	Timeout time.Duration `json:"x-timeout,omitempty"`
+	// Paginated marks a GET operation whose responses link to the next page with
+	// an RFC 5988 Link header, emitted as the x-pagination extension. Generated
+	// Go clients get a <Method>Pages iterator for it.
+	Paginated bool         `json:"x-pagination,omitempty"`
 	Handler   http.Handler `json:"-"`
 }
 
+// CodeSample is an example request for an operation in a given language.
+// Documentation renderers such as Redoc display these next to the operation.
+type CodeSample struct {
+	Lang   string `json:"lang"`
+	Label  string `json:"label,omitempty"`
+	Source string `json:"source"`
+}
+
+type CodeSamples []CodeSample
+
 func (o *Operation) MarshalJSON() ([]byte, error) {
 	m := make(map[string]interface{})
 	if o.Summary != "" {
@@ -63,6 +90,15 @@ func (o *Operation) MarshalJSON() ([]byte, error) {
 	if o.Responses != nil {
 		m["responses"] = o.Responses
 	}
+	if len(o.CodeSamples) > 0 {
+		m["x-codeSamples"] = o.CodeSamples
+	}
+	if o.Timeout > 0 {
+		m["x-timeout"] = o.Timeout.String()
+	}
+	if o.Paginated {
+		m["x-pagination"] = o.Paginated
+	}
 	return json.Marshal(m)
 }
 
@@ -86,10 +122,26 @@ var Number = reflect.TypeOf(float64(0))
 var Boolean = reflect.TypeOf(bool(false))
 var Array = reflect.TypeOf([]any{})
 
+// File is the type of an uploaded file, io.Reader. Declare file fields of a
+// MultipartFormData body as io.Reader; they are documented as binary strings
+// and streamed by generated clients.
+var File = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
 func Object[T any]() reflect.Type {
 	return Type[T]()
 }
 
+// Ptr returns a pointer to v, for optional fields such as Parameter.Explode
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// FormatPassword marks a string as sensitive. Its values are redacted by LoggingMiddleware.
+const FormatPassword = "password"
+
+// Schema describes a value by its Go type. Fields of struct types may refine
+// their schema with tags: `format:"password"` sets the format and
+// `enum:"active,inactive"` restricts a string field to the listed values.
 type Schema struct {
 	Type     reflect.Type   `json:"-"`
 	Enum     []any          `json:"enum,omitempty"`
@@ -97,124 +149,124 @@ type Schema struct {
 	Example  any            `json:"example,omitempty"`
 	Examples map[string]any `json:"examples,omitempty"`
 	Ref      string         `json:"$ref,omitempty"`
+	// Format refines Type, e.g. FormatPassword. Struct fields set it with a `format:"..."` tag.
+	Format string `json:"format,omitempty"`
+	// PrefixItems describes the schema of each leading position of a tuple-like array
+	PrefixItems []Schema `json:"prefixItems,omitempty"`
+	// Items describes the elements of an Array schema, e.g. the values of a
+	// repeated query parameter (?ids=1&ids=2)
+	Items *Schema `json:"items,omitempty"`
+	// Minimum and Maximum bound integer and number values, e.g. Ptr(1.0) for
+	// ?page= values of at least 1. ExclusiveMinimum and ExclusiveMaximum
+	// exclude the bound itself.
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum bool     `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum bool     `json:"exclusiveMaximum,omitempty"`
+	// MinLength and MaxLength bound the length of string values in characters
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+	// Pattern is a regular expression string values must match, e.g.
+	// ^[a-z0-9-]+$ for a slug. Patterns are compiled once by NewServerMux.
+	Pattern string `json:"pattern,omitempty"`
+	// ContentMediaType and ContentEncoding describe a payload carried in a string
+	// (OpenAPI 3.1), e.g. "application/json" encoded as "base64"
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+	ContentEncoding  string `json:"contentEncoding,omitempty"`
+	// AllOf, OneOf and AnyOf compose the schema from others, e.g. a OneOf of
+	// Cat and Dog for a polymorphic pet. Values must match all of the AllOf
+	// schemas, exactly one of the OneOf schemas and at least one of the AnyOf
+	// schemas. Type may be left nil when the members describe the value.
+	AllOf []Schema `json:"allOf,omitempty"`
+	OneOf []Schema `json:"oneOf,omitempty"`
+	AnyOf []Schema `json:"anyOf,omitempty"`
+	// Discriminator selects the OneOf or AnyOf member a value is validated
+	// against by one of its properties, e.g. the type of an event
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
 }
 
-func reflectTypeToJSON(t reflect.Type, schemaJSON map[string]any) error {
-	switch t.Kind() {
-	case reflect.String:
-		schemaJSON["type"] = "string"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		schemaJSON["type"] = "integer"
-	case reflect.Float32, reflect.Float64:
-		schemaJSON["type"] = "number"
-	case reflect.Bool:
-		schemaJSON["type"] = "boolean"
-	case reflect.Slice, reflect.Array:
-		schemaJSON["type"] = "array"
-		items := map[string]interface{}{}
-		if t.Elem() != nil {
-			_ = reflectTypeToJSON(t.Elem(), items)
-			// Add items schema if we can determine element type
-			schemaJSON["items"] = items
-		}
-	case reflect.Struct:
-		schemaJSON["type"] = "object"
-
-		// Add properties for struct fields
-		properties := make(map[string]interface{})
-		requiredProps := []string{}
-
-		for i := range t.NumField() {
-			field := t.Field(i)
-
-			// Skip unexported fields
-			if !field.IsExported() {
-				continue
-			}
-
-			// Get JSON field name from tag, fall back to struct field name
-			jsonTag := field.Tag.Get("json")
-			fieldName := field.Name
-			if jsonTag != "" {
-				// Parse the json tag to get the name part
-				parts := strings.Split(jsonTag, ",")
-				if parts[0] != "" && parts[0] != "-" {
-					fieldName = parts[0]
-				}
-
-				// Check if this field is required (no omitempty tag)
-				if !strings.Contains(jsonTag, "omitempty") {
-					requiredProps = append(requiredProps, fieldName)
-				}
-			}
-
-			// Create schema for this field
-			fieldSchema := map[string]any{}
-			err := reflectTypeToJSON(field.Type, fieldSchema)
-			if err != nil {
-				return err
-			}
+// Discriminator names the property whose value selects the variant of a
+// polymorphic schema. Mapping maps property values to member references, e.g.
+// "user.created" to "#/components/schemas/UserCreated"; values without a
+// mapping select the member whose reference ends with the value itself.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
 
-			properties[fieldName] = fieldSchema
-		}
-		if len(properties) > 0 {
-			schemaJSON["properties"] = properties
-		}
+// composed reports whether the schema has AllOf, OneOf or AnyOf members
+func (s Schema) composed() bool {
+	return len(s.AllOf) > 0 || len(s.OneOf) > 0 || len(s.AnyOf) > 0
+}
 
-		if len(requiredProps) > 0 {
-			schemaJSON["required"] = requiredProps
-		}
-	default:
-		return fmt.Errorf("unsupported type %s", t.Kind())
+// StringEnum returns a string schema restricted to values, e.g.
+// StringEnum("asc", "desc") for a sort order
+func StringEnum(values ...string) Schema {
+	enum := make([]any, len(values))
+	for i, value := range values {
+		enum[i] = value
 	}
-	return nil
+	return Schema{Type: String, Enum: enum}
 }
 
-// MarshalJSON implements json.Marshaler to output proper OpenAPI schema format
-func (s Schema) MarshalJSON() ([]byte, error) {
-
-	schemaJSON := map[string]interface{}{}
-	// Handle type field as string based on reflection.Type
-	if s.Ref != "" {
-		schemaJSON["$ref"] = s.Ref
-	} else if s.Type != nil {
-		err := reflectTypeToJSON(s.Type, schemaJSON)
-		if err != nil {
-			return nil, err
-		}
-	}
+// IntRange returns an integer schema bounded by min and max, inclusive
+func IntRange(min, max int) Schema {
+	return Schema{Type: Integer, Minimum: Ptr(float64(min)), Maximum: Ptr(float64(max))}
+}
 
-	// Add other fields from the original schema
-	if len(s.Enum) > 0 {
-		schemaJSON["enum"] = s.Enum
-	}
-	if s.Default != nil {
-		schemaJSON["default"] = s.Default
-	}
-	if s.Example != nil {
-		schemaJSON["example"] = s.Example
-	}
-	if len(s.Examples) > 0 {
-		schemaJSON["examples"] = s.Examples
-	}
+// ArrayOf returns an array schema whose elements are described by items, e.g.
+// ArrayOf(gopenapi.Schema{Type: gopenapi.Integer}) for ?ids=1&ids=2
+func ArrayOf(items Schema) Schema {
+	return Schema{Type: Array, Items: &items}
+}
 
-	return json.Marshal(schemaJSON)
+// MarshalJSON implements json.Marshaler to output the OpenAPI schema, as
+// rendered by OpenAPISchema with the default options
+func (s Schema) MarshalJSON() ([]byte, error) {
+	return json.Marshal(OpenAPISchema(s, OpenAPIOptions{}))
 }
 
 func (s Schema) Validate(value string) (any, error) {
 	// If this schema has a resolved reference, use the resolved schema
-	if s.Ref != "" && s.Type == nil {
+	if s.Ref != "" && s.Type == nil && !s.composed() {
 		return nil, fmt.Errorf("gopenapi: unresolved schema reference %s", s.Ref)
 	}
+	if s.Type == nil && s.composed() {
+		// The members describe the value, which is JSON or else a plain string
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+			decoded = value
+		}
+		if err := validateSchemaValue(s, "$", decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	}
 
 	switch s.Type {
 	case String:
+		if err := s.validateString(value); err != nil {
+			return nil, err
+		}
 		return value, nil
 	case Integer:
-		return strconv.Atoi(value)
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateRange(float64(n)); err != nil {
+			return nil, err
+		}
+		return n, nil
 	case Number:
-		return strconv.ParseFloat(value, 64)
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.validateRange(n); err != nil {
+			return nil, err
+		}
+		return n, nil
 	case Boolean:
 		return strconv.ParseBool(value)
 	default:
@@ -222,10 +274,110 @@ func (s Schema) Validate(value string) (any, error) {
 		if err := json.Unmarshal([]byte(value), v); err != nil {
 			return nil, err
 		}
+		if len(s.PrefixItems) > 0 || s.Items != nil || s.composed() {
+			var decoded any
+			if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+				return nil, err
+			}
+			if err := validateSchemaValue(s, "$", decoded); err != nil {
+				return nil, err
+			}
+		}
 		return v, nil
 	}
 }
 
+// validateRange checks n against the Minimum and Maximum of the schema
+func (s Schema) validateRange(n float64) error {
+	if s.Minimum != nil {
+		if s.ExclusiveMinimum && n <= *s.Minimum {
+			return fmt.Errorf("value %v must be greater than %v", n, *s.Minimum)
+		}
+		if n < *s.Minimum {
+			return fmt.Errorf("value %v is less than minimum %v", n, *s.Minimum)
+		}
+	}
+	if s.Maximum != nil {
+		if s.ExclusiveMaximum && n >= *s.Maximum {
+			return fmt.Errorf("value %v must be less than %v", n, *s.Maximum)
+		}
+		if n > *s.Maximum {
+			return fmt.Errorf("value %v is greater than maximum %v", n, *s.Maximum)
+		}
+	}
+	return nil
+}
+
+// validateString checks value against the Enum, MinLength, MaxLength, Format
+// and Pattern of the schema. Formats without a validator are not checked.
+func (s Schema) validateString(value string) error {
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, any(value)) {
+		return fmt.Errorf("value %q is not one of %v", value, s.Enum)
+	}
+	length := utf8.RuneCountInString(value)
+	if s.MinLength != nil && length < *s.MinLength {
+		return fmt.Errorf("value length %d is less than minLength %d", length, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return fmt.Errorf("value length %d is greater than maxLength %d", length, *s.MaxLength)
+	}
+	if validateFormat, ok := formatValidators[s.Format]; ok {
+		if err := validateFormat(value); err != nil {
+			return fmt.Errorf("value %q is not a valid %s: %w", value, s.Format, err)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := compilePattern(s.Pattern)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("value %q does not match pattern %s", value, s.Pattern)
+		}
+	}
+	return nil
+}
+
+// patterns caches compiled Schema patterns by source
+var patterns sync.Map
+
+// compilePattern returns the compiled regular expression for pattern,
+// compiling it on first use
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+// ValidateValues validates the values of a repeated parameter such as
+// ?ids=1&ids=2. Array schemas with Items validate each value against Items and
+// return them as []any; other schemas validate the first value.
+func (s Schema) ValidateValues(values []string) (any, error) {
+	if s.Type == nil || s.Items == nil || (s.Type.Kind() != reflect.Slice && s.Type.Kind() != reflect.Array) {
+		value := ""
+		if len(values) > 0 {
+			value = values[0]
+		}
+		return s.Validate(value)
+	}
+
+	items := make([]any, len(values))
+	for i, value := range values {
+		item, err := s.Items.Validate(value)
+		if err != nil {
+			return nil, fmt.Errorf("gopenapi: item %d: %w", i, err)
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
 type Parameters []Parameter
 
 type GroupedParameters struct {
@@ -269,6 +421,12 @@ type Parameter struct {
 	Required    bool   `json:"required,omitempty"`
 	Deprecated  bool   `json:"deprecated,omitempty"`
 	Schema      Schema `json:"schema,omitempty"`
+	// Explode controls how array query values are serialized: repeated keys
+	// (?tags=a&tags=b) when true or nil, the OpenAPI default, and a single
+	// comma-separated value (?tags=a,b) when false
+	Explode *bool `json:"explode,omitempty"`
+	// Examples of the parameter value, which may reference Components.Examples
+	Examples Examples `json:"examples,omitempty"`
 }
 
 type MediaType string
@@ -285,6 +443,8 @@ const (
 	TextJSON        MediaType = "text/json"
 	TextYAML        MediaType = "text/yaml"
 	TextMarkdown    MediaType = "text/markdown"
+	// TextEventStream responses are written with WriteSSE
+	TextEventStream MediaType = "text/event-stream"
 	ImagePNG        MediaType = "image/png"
 	ImageJPEG       MediaType = "image/jpeg"
 	ImageGIF        MediaType = "image/gif"
@@ -301,10 +461,16 @@ const (
 	VideoWEBM       MediaType = "video/webm"
 	VideoMPEG       MediaType = "video/mpeg"
 	VideoMPG        MediaType = "video/mpeg"
+
+	// MultipartFormData bodies are sent by generated clients as form fields,
+	// with File fields streamed as file parts
+	MultipartFormData MediaType = "multipart/form-data"
 )
 
 type Content = map[MediaType]struct {
 	Schema Schema `json:"schema,omitempty"`
+	// Examples of the body, which may reference Components.Examples
+	Examples Examples `json:"examples,omitempty"`
 }
 
 type RequestBody struct {
@@ -317,28 +483,102 @@ type Ref string
 
 type Tags []string
 
+// Path describes the operations available on a path. Ref points at an entry
+// of Components.PathItems, e.g. "#/components/pathItems/Health", to share one
+// path item between paths; it is kept when the spec is serialized and the
+// referenced path item is copied in when the spec is resolved.
 type Path struct {
-	Summary     string     `json:"summary"`
-	Description string     `json:"description"`
-	Tags        Tags       `json:"tags"`
-	Servers     Servers    `json:"servers,omitempty"`
-	Get         *Operation `json:"get,omitempty"`
-	Post        *Operation `json:"post,omitempty"`
-	Put         *Operation `json:"put,omitempty"`
-	Delete      *Operation `json:"delete,omitempty"`
-	Patch       *Operation `json:"patch,omitempty"`
-	Head        *Operation `json:"head,omitempty"`
-	Options     *Operation `json:"options,omitempty"`
-	Trace       *Operation `json:"trace,omitempty"`
+	Ref         string  `json:"$ref,omitempty"`
+	Summary     string  `json:"summary,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Tags        Tags    `json:"tags,omitempty"`
+	Servers     Servers `json:"servers,omitempty"`
+	// Parameters are shared by every operation of the path, e.g. an {id} path
+	// parameter. Operations override them by declaring a parameter with the same
+	// name and location.
+	Parameters Parameters `json:"parameters,omitempty"`
+	Get        *Operation `json:"get,omitempty"`
+	Post       *Operation `json:"post,omitempty"`
+	Put        *Operation `json:"put,omitempty"`
+	Delete     *Operation `json:"delete,omitempty"`
+	Patch      *Operation `json:"patch,omitempty"`
+	Head       *Operation `json:"head,omitempty"`
+	Options    *Operation `json:"options,omitempty"`
+	Trace      *Operation `json:"trace,omitempty"`
 }
 
 type Paths map[string]Path
 
-type Responses = map[int]struct {
+// Header describes a header sent with a response
+type Header struct {
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Schema      Schema `json:"schema,omitempty"`
+}
+
+// Headers maps header names to their definitions
+type Headers map[string]Header
+
+// Example is a named example of a parameter or body. Ref points at an entry of
+// Components.Examples, e.g. "#/components/examples/User"; it is kept when the
+// spec is serialized and the referenced fields are copied in when the spec is
+// resolved.
+type Example struct {
+	Ref           string `json:"$ref,omitempty"`
+	Summary       string `json:"summary,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Value         any    `json:"value,omitempty"`
+	ExternalValue string `json:"externalValue,omitempty"`
+}
+
+// MarshalJSON outputs only the reference for referenced examples
+func (e Example) MarshalJSON() ([]byte, error) {
+	if e.Ref != "" {
+		return json.Marshal(map[string]string{"$ref": e.Ref})
+	}
+	type example Example
+	return json.Marshal(example(e))
+}
+
+// MarshalJSON outputs only the reference for referenced path items
+func (p Path) MarshalJSON() ([]byte, error) {
+	if p.Ref != "" {
+		return json.Marshal(map[string]string{"$ref": p.Ref})
+	}
+	type path Path
+	return json.Marshal(path(p))
+}
+
+// OperationParameters returns the parameters of operation together with those
+// shared by the path, leaving out path parameters the operation overrides
+func (p Path) OperationParameters(operation *Operation) Parameters {
+	if len(p.Parameters) == 0 {
+		return operation.Parameters
+	}
+	var parameters Parameters
+	for _, shared := range p.Parameters {
+		overridden := slices.ContainsFunc(operation.Parameters, func(parameter Parameter) bool {
+			return parameter.Name == shared.Name && parameter.In == shared.In
+		})
+		if !overridden {
+			parameters = append(parameters, shared)
+		}
+	}
+	return append(parameters, operation.Parameters...)
+}
+
+// Examples maps example names to their definitions
+type Examples map[string]Example
+
+type Response struct {
 	Description string  `json:"description,omitempty"`
+	Headers     Headers `json:"headers,omitempty"`
 	Content     Content `json:"content,omitempty"`
 }
 
+type Responses = map[int]Response
+
 type Info struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
@@ -387,11 +627,13 @@ type OAuthFlows struct {
 }
 
 type SecurityScheme struct {
-	Type    SecuritySchemeType `json:"type,omitempty"`
-	In      In                 `json:"in,omitempty"`
-	Scheme  Scheme             `json:"scheme,omitempty"`
-	Flows   *OAuthFlows        `json:"flows,omitempty"`
-	Handler MiddlewareHandler  `json:"-"`
+	Type SecuritySchemeType `json:"type,omitempty"`
+	// Name of the header, query parameter or cookie carrying an API key
+	Name    string            `json:"name,omitempty"`
+	In      In                `json:"in,omitempty"`
+	Scheme  Scheme            `json:"scheme,omitempty"`
+	Flows   *OAuthFlows       `json:"flows,omitempty"`
+	Handler MiddlewareHandler `json:"-"`
 }
 
 type SecuritySchemes map[string]SecurityScheme
@@ -400,8 +642,14 @@ type Schemas map[string]Schema
 type Components struct {
 	SecuritySchemes SecuritySchemes `json:"securitySchemes,omitempty"`
 	Schemas         Schemas         `json:"schemas,omitempty"`
+	Examples        Examples        `json:"examples,omitempty"`
+	// PathItems are path items shared by Paths entries through their Ref (OpenAPI 3.1)
+	PathItems PathItems `json:"pathItems,omitempty"`
 }
 
+// PathItems maps path item names to their definitions
+type PathItems map[string]Path
+
 type Security map[string][]string
 
 type SecurityHandler func(w http.ResponseWriter, r *http.Request) error
@@ -411,26 +659,33 @@ type DefaultSecurityMiddleware struct {
 }
 
 func (s *DefaultSecurityMiddleware) Apply(spec *Spec, operation *Operation) (MiddlewareHandler, error) {
+	// An operation's own requirements, including the empty NoSecurity, take
+	// precedence over the spec's
 	security := operation.Security
 	if security == nil {
 		security = spec.Security
 	}
-	handler := http.Handler(operation.Handler)
-
+	var schemes []SecurityScheme
 	for _, security := range security {
 		for name := range security {
 			maybeScheme, ok := spec.Components.SecuritySchemes[name]
 			if !ok || maybeScheme.Handler == nil {
 				return nil, fmt.Errorf("gopenapi: security scheme %s not found", name)
 			}
-			handler = maybeScheme.Handler(handler)
+			schemes = append(schemes, maybeScheme)
 		}
 	}
 	return func(next http.Handler) http.Handler {
+		handler := next
+		for _, scheme := range schemes {
+			handler = scheme.Handler(handler)
+		}
 		return handler
 	}, nil
 }
 
+// NoSecurity opts an operation out of Spec.Security. It is an empty, non-nil
+// list, emitted as "security": [] in the OpenAPI document.
 var NoSecurity []Security = []Security{}
 
 type Spec struct {
@@ -442,11 +697,66 @@ type Spec struct {
 	Security             []Security           `json:"security,omitempty"`
 	ValidationMiddleware ValidationMiddleware `json:"-"`
 	SecurityMiddleware   Middleware           `json:"-"`
+	// LoggingMiddleware, when set, wraps every operation outside validation and security
+	LoggingMiddleware Middleware `json:"-"`
+	// ValidateResponses makes WriteResponse check JSON bodies against the response
+	// schema declared for their status. Mismatches are logged and answered with a
+	// 500 written by ErrorResponder instead of the body. Meant for development and
+	// tests; it costs a decode of every response.
+	ValidateResponses bool `json:"-"`
+	// ValidateRequests makes DefaultValidationMiddleware validate the parameters
+	// and JSON body of every request before its handler runs, rejecting invalid
+	// requests with a 400 written by ErrorResponder
+	ValidateRequests bool `json:"-"`
+	// ErrorResponder writes requests rejected by the built-in middleware and
+	// WriteError. Defaults to DefaultErrorResponder, which writes
+	// application/problem+json.
+	ErrorResponder ErrorResponder `json:"-"`
+	// CORS, when set, makes servers answer preflight requests and set the
+	// Access-Control-* headers on responses to allowed origins
+	CORS *CORS `json:"-"`
+	// RecoverPanics makes servers answer requests whose handler panics with a
+	// 500 written by ErrorResponder, logging the panic, instead of letting
+	// net/http abort the connection. Defaults to true when nil; set it to
+	// Ptr(false) to disable recovery.
+	RecoverPanics *bool `json:"-"`
+	// DocsPath, when set, mounts a Swagger UI page for the spec at that path,
+	// e.g. "/docs", loading the document from OpenAPIJSONPath or /openapi.json.
+	// The embedded Swagger UI files are served below it, e.g. /docs/swagger-ui.css.
+	DocsPath string `json:"-"`
+	// DocsAssetsURL optionally overrides the base URL of the swagger-ui-dist
+	// files the docs page loads, swagger-ui-bundle.js and swagger-ui.css, e.g.
+	// to use another release. Defaults to the files embedded under DocsPath.
+	DocsAssetsURL string `json:"-"`
+	// OpenAPIJSONPath, when set, mounts OpenAPIJSONHandler at that path, e.g.
+	// "/openapi.json"
+	OpenAPIJSONPath string `json:"-"`
+	// OpenAPIOptions controls how OpenAPIJSONHandler renders the spec. Set them
+	// as the flags the gopenapi CLI is run with, e.g. EmptyAnySchema for
+	// -empty-any-schema, for the served and generated documents to match.
+	OpenAPIOptions OpenAPIOptions `json:"-"`
+	// MaxRequestBodySize limits the request bodies of every operation to that
+	// many bytes. It applies before any middleware, so that LoggingMiddleware
+	// and handlers read at most that many bytes; longer bodies fail with
+	// *BodyTooLargeError from ValidateRequestBody. Defaults to
+	// DefaultMaxRequestBodySize when nil; Ptr[int64](0) removes the limit.
+	// WithMaxBodyBytes sets it.
+	MaxRequestBodySize *int64 `json:"-"`
+	// RejectUnknownFields makes ValidateRequestBody reject JSON bodies with
+	// properties that are not fields of the request body's struct type
+	RejectUnknownFields bool `json:"-"`
+	// Encoders adds response formats to JSON, e.g. "application/xml". When set,
+	// WriteResponse encodes bodies with the encoder best matching the request's
+	// Accept header, defaulting to JSON, and sets the response Content-Type.
+	Encoders map[MediaType]Encoder `json:"-"`
 }
 
 type Server struct {
 	http.Server
 	Spec Spec `json:"-"`
+	// spec is the spec served by Handler, which options such as
+	// WithMaxBodyBytes configure
+	spec *Spec
 }
 
 func formatPattern(method, host, pattern string) string {
@@ -460,7 +770,16 @@ func formatPattern(method, host, pattern string) string {
 
 func handle(spec *Spec, operation *Operation) (http.HandlerFunc, error) {
 	handler := http.Handler(operation.Handler)
-	for _, middleware := range []Middleware{spec.ValidationMiddleware, spec.SecurityMiddleware} {
+	if spec.ValidateResponses {
+		handler = validateResponses(operation, handler)
+	}
+	if len(spec.Encoders) > 0 {
+		handler = negotiate(spec.Encoders, handler)
+	}
+	if spec.RecoverPanics == nil || *spec.RecoverPanics {
+		handler = recoverPanics(operation, handler)
+	}
+	for _, middleware := range []Middleware{spec.ValidationMiddleware, spec.SecurityMiddleware, spec.LoggingMiddleware} {
 		if middleware == nil {
 			continue
 		}
@@ -475,6 +794,10 @@ func handle(spec *Spec, operation *Operation) (http.HandlerFunc, error) {
 		operation: operation,
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
+		// Bodies are limited outside every middleware, which may read them
+		if limit := spec.maxRequestBodySize(); limit > 0 && r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
 		// Add both spec and operation to the request context in a single chain (preserving existing context)
 		ctx := context.WithValue(r.Context(), RequestContextKey, handlerContextValue)
 		handler.ServeHTTP(w, r.WithContext(ctx))
@@ -486,6 +809,9 @@ func NewServerMux(spec *Spec) (http.Handler, error) {
 	if err := resolveRefs(spec); err != nil {
 		return nil, fmt.Errorf("failed to resolve schema references: %w", err)
 	}
+	if err := compilePatterns(spec); err != nil {
+		return nil, err
+	}
 
 	mux := http.NewServeMux()
 	hosts := make([]string, len(spec.Servers))
@@ -528,10 +854,67 @@ func NewServerMux(spec *Spec) (http.Handler, error) {
 		}
 	}
 
+	specURL := "/openapi.json"
+	if spec.OpenAPIJSONPath != "" {
+		if path, ok := spec.Paths[spec.OpenAPIJSONPath]; ok && path.Get != nil {
+			return nil, fmt.Errorf("gopenapi: OpenAPIJSONPath %s conflicts with the GET operation of that path", spec.OpenAPIJSONPath)
+		}
+		mux.Handle("GET "+spec.OpenAPIJSONPath, OpenAPIJSONHandler(spec))
+		specURL = spec.OpenAPIJSONPath
+	}
+	if spec.DocsPath != "" {
+		if path, ok := spec.Paths[spec.DocsPath]; ok && path.Get != nil {
+			return nil, fmt.Errorf("gopenapi: DocsPath %s conflicts with the GET operation of that path", spec.DocsPath)
+		}
+		mux.Handle("GET "+spec.DocsPath, DocsHandler(spec, specURL))
+		docsPath := strings.TrimSuffix(spec.DocsPath, "/")
+		mux.Handle("GET "+docsPath+"/{asset}", http.StripPrefix(docsPath, DocsAssetsHandler()))
+	}
+
+	if spec.CORS != nil {
+		return spec.CORS.Handler(mux), nil
+	}
 	return mux, nil
 }
 
-func NewServer(spec *Spec, port string) (*Server, error) {
+// ServerOption configures a Server created by NewServer
+type ServerOption func(*Server)
+
+// WithReadTimeout sets the maximum duration for reading an entire request,
+// including the body
+func WithReadTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.ReadTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the maximum duration before timing out writes of the response
+func WithWriteTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.WriteTimeout = d
+	}
+}
+
+// WithIdleTimeout sets how long keep-alive connections wait for the next request
+func WithIdleTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.IdleTimeout = d
+	}
+}
+
+// WithMaxBodyBytes limits request bodies to n bytes by setting
+// Spec.MaxRequestBodySize, replacing its default. Reading past the limit
+// fails, so ValidateRequestBody rejects larger bodies.
+func WithMaxBodyBytes(n int64) ServerOption {
+	return func(s *Server) {
+		s.spec.MaxRequestBodySize = &n
+		s.Spec.MaxRequestBodySize = &n
+	}
+}
+
+// NewServer returns a server for spec listening on port. Timeouts are unset
+// unless configured with opts.
+func NewServer(spec *Spec, port string, opts ...ServerOption) (*Server, error) {
 	handler, err := NewServerMux(spec)
 	if err != nil {
 		return nil, err
@@ -541,7 +924,7 @@ func NewServer(spec *Spec, port string) (*Server, error) {
 		spec:      spec,
 		operation: nil,
 	})
-	return &Server{
+	server := &Server{
 		Server: http.Server{
 			Addr:    fmt.Sprintf(":%s", port),
 			Handler: handler,
@@ -550,7 +933,12 @@ func NewServer(spec *Spec, port string) (*Server, error) {
 			},
 		},
 		Spec: *spec,
-	}, nil
+		spec: spec,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	return server, nil
 }
 
 func Serve(ctx context.Context, listener net.Listener, spec *Spec) error {
@@ -596,13 +984,48 @@ func OperationFromRequest(r *http.Request) (*Operation, bool) {
 	return requestCtx.operation, true
 }
 
+// WriteResponse writes body as JSON with the given status, or with the encoder
+// of Spec.Encoders matching the request's Accept header. When the spec sets
+// ValidateResponses, the body is first checked against the operation's
+// response schema for status.
 func WriteResponse(w http.ResponseWriter, status int, body any) {
+	if validator, ok := w.(*responseValidator); ok {
+		validator.writeResponse(status, body)
+		return
+	}
+	writeEncoded(w, status, body)
+}
+
+// WriteResponseWith writes a pre-encoded body, such as XML or a cached JSON
+// document, with the given status and content type. When the spec sets
+// ValidateResponses, application/json bodies are checked like WriteResponse's.
+func WriteResponseWith(w http.ResponseWriter, status int, contentType string, body []byte) {
+	if validator, ok := w.(*responseValidator); ok && isJSONMediaType(contentType) {
+		if err := validator.operation.ValidateResponse(status, body); err != nil {
+			validator.reject(status, err)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(body)
+	_, _ = w.Write(body)
 }
 
 // resolveRefs resolves all schema references in the spec
 func resolveRefs(spec *Spec) error {
+	// Path items come first, as their operations hold the other references
+	for pathPattern, path := range spec.Paths {
+		if path.Ref == "" {
+			continue
+		}
+		resolved, err := resolvePathItemRef(spec, path.Ref, make(map[string]bool))
+		if err != nil {
+			return fmt.Errorf("gopenapi.resolveRefs: failed to resolve path item ref in %s: %w", pathPattern, err)
+		}
+		resolved.Ref = path.Ref
+		spec.Paths[pathPattern] = resolved
+	}
+
 	// Track which schemas are being resolved to detect circular references
 	resolving := make(map[string]bool)
 
@@ -618,11 +1041,17 @@ func resolveRefs(spec *Spec) error {
 				continue
 			}
 
+			// Operations validate the parameters shared by their path as their own
+			operation.Parameters = path.OperationParameters(operation)
+
 			// Resolve parameter schema references
 			for i := range operation.Parameters {
 				if err := resolveSchemaRefWithTracking(&operation.Parameters[i].Schema, spec, resolving); err != nil {
 					return fmt.Errorf("gopenapi.resolveRefs: failed to resolve parameter schema ref in %s: %w", pathPattern, err)
 				}
+				if err := resolveExampleRefs(operation.Parameters[i].Examples, spec); err != nil {
+					return fmt.Errorf("gopenapi.resolveRefs: failed to resolve parameter example ref in %s: %w", pathPattern, err)
+				}
 			}
 
 			// Resolve request body schema references
@@ -630,16 +1059,28 @@ func resolveRefs(spec *Spec) error {
 				if err := resolveSchemaRefWithTracking(&content.Schema, spec, resolving); err != nil {
 					return fmt.Errorf("gopenapi.resolveRefs: failed to resolve request body schema ref for %s in %s: %w", mediaType, pathPattern, err)
 				}
+				if err := resolveExampleRefs(content.Examples, spec); err != nil {
+					return fmt.Errorf("gopenapi.resolveRefs: failed to resolve request body example ref for %s in %s: %w", mediaType, pathPattern, err)
+				}
 				// Update the content in the map since we modified the schema
 				operation.RequestBody.Content[mediaType] = content
 			}
 
 			// Resolve response schema references
 			for statusCode, response := range operation.Responses {
+				for name, header := range response.Headers {
+					if err := resolveSchemaRefWithTracking(&header.Schema, spec, resolving); err != nil {
+						return fmt.Errorf("gopenapi.resolveRefs: failed to resolve response header schema ref for status %d, header %s in %s: %w", statusCode, name, pathPattern, err)
+					}
+					response.Headers[name] = header
+				}
 				for mediaType, content := range response.Content {
 					if err := resolveSchemaRefWithTracking(&content.Schema, spec, resolving); err != nil {
 						return fmt.Errorf("gopenapi.resolveRefs: failed to resolve response schema ref for status %d, media type %s in %s: %w", statusCode, mediaType, pathPattern, err)
 					}
+					if err := resolveExampleRefs(content.Examples, spec); err != nil {
+						return fmt.Errorf("gopenapi.resolveRefs: failed to resolve response example ref for status %d, media type %s in %s: %w", statusCode, mediaType, pathPattern, err)
+					}
 					// Update the content in the map since we modified the schema
 					response.Content[mediaType] = content
 				}
@@ -652,8 +1093,98 @@ func resolveRefs(spec *Spec) error {
 	return nil
 }
 
+// compilePatterns compiles the patterns of the parameter and request body
+// schemas of spec up front, so that invalid patterns fail NewServerMux rather
+// than the first request
+func compilePatterns(spec *Spec) error {
+	for pathPattern, path := range spec.Paths {
+		operations := []*Operation{
+			path.Get, path.Post, path.Put, path.Delete,
+			path.Patch, path.Head, path.Options, path.Trace,
+		}
+		for _, operation := range operations {
+			if operation == nil {
+				continue
+			}
+			for _, param := range operation.Parameters {
+				if err := compileSchemaPatterns(param.Schema); err != nil {
+					return fmt.Errorf("gopenapi: parameter %s in %s: %w", param.Name, pathPattern, err)
+				}
+			}
+			for mediaType, content := range operation.RequestBody.Content {
+				if err := compileSchemaPatterns(content.Schema); err != nil {
+					return fmt.Errorf("gopenapi: request body %s in %s: %w", mediaType, pathPattern, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func compileSchemaPatterns(schema Schema) error {
+	if schema.Pattern != "" {
+		if _, err := compilePattern(schema.Pattern); err != nil {
+			return err
+		}
+	}
+	if schema.Items != nil {
+		if err := compileSchemaPatterns(*schema.Items); err != nil {
+			return err
+		}
+	}
+	for _, item := range schema.PrefixItems {
+		if err := compileSchemaPatterns(item); err != nil {
+			return err
+		}
+	}
+	for _, members := range [][]Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, member := range members {
+			if err := compileSchemaPatterns(member); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // resolveSchemaRefWithTracking resolves a single schema reference with circular reference detection
 func resolveSchemaRefWithTracking(schema *Schema, spec *Spec, resolving map[string]bool) error {
+	for i := range schema.PrefixItems {
+		if err := resolveSchemaRefWithTracking(&schema.PrefixItems[i], spec, resolving); err != nil {
+			return fmt.Errorf("failed to resolve prefix item %d: %w", i, err)
+		}
+	}
+
+	if schema.Items != nil {
+		items := *schema.Items
+		if err := resolveSchemaRefWithTracking(&items, spec, resolving); err != nil {
+			return fmt.Errorf("failed to resolve items: %w", err)
+		}
+		schema.Items = &items
+	}
+
+	// Members are copied, as they may be shared with the schemas they came from
+	compositions := []struct {
+		keyword string
+		members *[]Schema
+	}{
+		{"allOf", &schema.AllOf},
+		{"oneOf", &schema.OneOf},
+		{"anyOf", &schema.AnyOf},
+	}
+	for _, composition := range compositions {
+		if len(*composition.members) == 0 {
+			continue
+		}
+		members := slices.Clone(*composition.members)
+		for i := range members {
+			if err := resolveSchemaRefWithTracking(&members[i], spec, resolving); err != nil {
+				return fmt.Errorf("failed to resolve %s member %d: %w", composition.keyword, i, err)
+			}
+		}
+		*composition.members = members
+	}
+
 	if schema.Ref == "" {
 		return nil
 	}
@@ -698,10 +1229,118 @@ func resolveSchemaRefWithTracking(schema *Schema, spec *Spec, resolving map[stri
 	if len(referencedSchema.Examples) > 0 {
 		schema.Examples = referencedSchema.Examples
 	}
+	if referencedSchema.Format != "" {
+		schema.Format = referencedSchema.Format
+	}
+	if len(referencedSchema.PrefixItems) > 0 {
+		schema.PrefixItems = referencedSchema.PrefixItems
+	}
+	if referencedSchema.Items != nil {
+		schema.Items = referencedSchema.Items
+	}
+	if referencedSchema.Minimum != nil {
+		schema.Minimum = referencedSchema.Minimum
+		schema.ExclusiveMinimum = referencedSchema.ExclusiveMinimum
+	}
+	if referencedSchema.Maximum != nil {
+		schema.Maximum = referencedSchema.Maximum
+		schema.ExclusiveMaximum = referencedSchema.ExclusiveMaximum
+	}
+	if referencedSchema.MinLength != nil {
+		schema.MinLength = referencedSchema.MinLength
+	}
+	if referencedSchema.MaxLength != nil {
+		schema.MaxLength = referencedSchema.MaxLength
+	}
+	if referencedSchema.Pattern != "" {
+		schema.Pattern = referencedSchema.Pattern
+	}
+	if referencedSchema.ContentMediaType != "" {
+		schema.ContentMediaType = referencedSchema.ContentMediaType
+	}
+	if referencedSchema.ContentEncoding != "" {
+		schema.ContentEncoding = referencedSchema.ContentEncoding
+	}
+	if len(referencedSchema.AllOf) > 0 {
+		schema.AllOf = referencedSchema.AllOf
+	}
+	if len(referencedSchema.OneOf) > 0 {
+		schema.OneOf = referencedSchema.OneOf
+	}
+	if len(referencedSchema.AnyOf) > 0 {
+		schema.AnyOf = referencedSchema.AnyOf
+	}
+	if referencedSchema.Discriminator != nil {
+		schema.Discriminator = referencedSchema.Discriminator
+	}
 
 	return nil
 }
 
+// resolveExampleRefs copies the referenced component examples into examples,
+// keeping each Ref for JSON serialization
+func resolveExampleRefs(examples Examples, spec *Spec) error {
+	for name, example := range examples {
+		if example.Ref == "" {
+			continue
+		}
+		resolved, err := resolveExampleRef(spec, example.Ref, make(map[string]bool))
+		if err != nil {
+			return fmt.Errorf("failed to resolve example %s: %w", name, err)
+		}
+		resolved.Ref = example.Ref
+		examples[name] = resolved
+	}
+	return nil
+}
+
+// resolveExampleRef looks up a "#/components/examples/<name>" reference,
+// following component examples that are themselves references
+func resolveExampleRef(spec *Spec, ref string, resolving map[string]bool) (Example, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/examples/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return Example{}, fmt.Errorf("unsupported example reference: %s", ref)
+	}
+	if resolving[ref] {
+		return Example{}, fmt.Errorf("circular reference detected for: %s", ref)
+	}
+	resolving[ref] = true
+
+	example, exists := spec.Components.Examples[name]
+	if !exists {
+		return Example{}, fmt.Errorf("example not found: %s", name)
+	}
+	if example.Ref != "" {
+		return resolveExampleRef(spec, example.Ref, resolving)
+	}
+	return example, nil
+}
+
+// resolvePathItemRef looks up a "#/components/pathItems/<name>" reference,
+// following component path items that are themselves references
+func resolvePathItemRef(spec *Spec, ref string, resolving map[string]bool) (Path, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return Path{}, fmt.Errorf("external references not supported: %s", ref)
+	}
+	name, ok := strings.CutPrefix(ref, "#/components/pathItems/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return Path{}, fmt.Errorf("unsupported path item reference: %s", ref)
+	}
+	if resolving[ref] {
+		return Path{}, fmt.Errorf("circular reference detected for: %s", ref)
+	}
+	resolving[ref] = true
+
+	path, exists := spec.Components.PathItems[name]
+	if !exists {
+		return Path{}, fmt.Errorf("path item not found: %s", name)
+	}
+	if path.Ref != "" {
+		return resolvePathItemRef(spec, path.Ref, resolving)
+	}
+	return path, nil
+}
+
 // resolveJSONPointer resolves a JSON Pointer reference within the spec
 func resolveJSONPointer(spec *Spec, ref string) (Schema, error) {
 	// Remove the # prefix
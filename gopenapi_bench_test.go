@@ -18,7 +18,7 @@ type BenchUser struct {
 }
 
 type BenchProduct struct {
-	ID          int     `json:"id"`
+	ID          int     `json:"id,omitempty"`
 	Name        string  `json:"name"`
 	Price       float64 `json:"price"`
 	Description string  `json:"description"`
@@ -2,11 +2,19 @@ package gopenapi_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/runpod/gopenapi"
 )
@@ -1036,3 +1044,3134 @@ func TestJSONPointerReferenceFormats(t *testing.T) {
 
 	t.Log("JSON Pointer reference formats test passed")
 }
+
+func TestSchemaPrefixItems(t *testing.T) {
+	// A [string, int] tuple, e.g. ["alice", 42]
+	schema := gopenapi.Schema{
+		Type: gopenapi.Array,
+		PrefixItems: []gopenapi.Schema{
+			{Type: gopenapi.String},
+			{Type: gopenapi.Integer},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "matching tuple", value: `["alice", 42]`},
+		{name: "extra items allowed", value: `["alice", 42, true]`},
+		{name: "wrong first position", value: `[1, 42]`, wantErr: "$[0]: expected string, got integer"},
+		{name: "wrong second position", value: `["alice", "42"]`, wantErr: "$[1]: expected integer, got string"},
+		{name: "fractional integer", value: `["alice", 4.2]`, wantErr: "$[1]: expected integer, got number"},
+		{name: "not an array", value: `{"name": "alice"}`, wantErr: "cannot unmarshal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schema.Validate(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected %s to validate, got %v", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q for %s, got %v", tt.wantErr, tt.value, err)
+			}
+		})
+	}
+
+	jsonBytes, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	prefixItems, ok := parsed["prefixItems"].([]interface{})
+	if !ok || len(prefixItems) != 2 {
+		t.Fatalf("Expected two prefixItems in %s", jsonBytes)
+	}
+	if prefixItems[0].(map[string]interface{})["type"] != "string" || prefixItems[1].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("Unexpected prefixItems types in %s", jsonBytes)
+	}
+}
+
+func TestLoggingMiddlewareRedactsPasswords(t *testing.T) {
+	type Credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password" format:"password"`
+	}
+
+	var logs bytes.Buffer
+	var received Credentials
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/login": {
+				Post: &gopenapi.Operation{
+					OperationId: "login",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Required: true,
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Credentials]()}},
+						},
+					},
+					Responses: gopenapi.Responses{204: {Description: "Logged in"}},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				},
+			},
+		},
+		LoggingMiddleware: &gopenapi.DefaultLoggingMiddleware{
+			Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		},
+	}
+
+	server, err := gopenapi.NewServer(spec, "8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"alice","password":"hunter2"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response := httptest.NewRecorder()
+	server.Handler.ServeHTTP(response, request)
+
+	if response.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusNoContent, response.Code, response.Body.String())
+	}
+	if received.Password != "hunter2" {
+		t.Errorf("Expected the handler to receive the real password, got %q", received.Password)
+	}
+
+	logged := logs.String()
+	if strings.Contains(logged, "hunter2") {
+		t.Errorf("Expected password to be redacted, got log:\n%s", logged)
+	}
+	if !strings.Contains(logged, gopenapi.RedactedValue) || !strings.Contains(logged, "alice") {
+		t.Errorf("Expected logged body with redacted password and username, got log:\n%s", logged)
+	}
+	if !strings.Contains(logged, "status=204") {
+		t.Errorf("Expected logged status, got log:\n%s", logged)
+	}
+}
+
+func TestExampleReferences(t *testing.T) {
+	newSpec := func(ref string) *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI: "3.0.0",
+			Info: gopenapi.Info{
+				Title:   "Test API",
+				Version: "1.0.0",
+			},
+			Components: gopenapi.Components{
+				Examples: gopenapi.Examples{
+					"Alice": {
+						Summary: "A typical user",
+						Value:   map[string]any{"name": "Alice"},
+					},
+				},
+			},
+			Paths: gopenapi.Paths{
+				"/users": {
+					Post: &gopenapi.Operation{
+						Security: gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {
+									Schema:   UserSchema,
+									Examples: gopenapi.Examples{"alice": {Ref: ref}},
+								},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+					},
+				},
+				"/users/{name}": {
+					Get: &gopenapi.Operation{
+						Security: gopenapi.NoSecurity,
+						Parameters: gopenapi.Parameters{
+							{Name: "name", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						},
+						Responses: gopenapi.Responses{
+							200: {
+								Description: "OK",
+								Content: gopenapi.Content{
+									gopenapi.ApplicationJSON: {
+										Schema:   UserSchema,
+										Examples: gopenapi.Examples{"alice": {Ref: ref}},
+									},
+								},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+					},
+				},
+			},
+			Servers: gopenapi.Servers{
+				{URL: "/"},
+			},
+		}
+	}
+
+	t.Run("shared example is resolved for both operations", func(t *testing.T) {
+		spec := newSpec("#/components/examples/Alice")
+		if _, err := gopenapi.NewServerMux(spec); err != nil {
+			t.Fatal(err)
+		}
+
+		requestExample := spec.Paths["/users"].Post.RequestBody.Content[gopenapi.ApplicationJSON].Examples["alice"]
+		responseExample := spec.Paths["/users/{name}"].Get.Responses[200].Content[gopenapi.ApplicationJSON].Examples["alice"]
+		for _, example := range []gopenapi.Example{requestExample, responseExample} {
+			if example.Summary != "A typical user" || example.Value.(map[string]any)["name"] != "Alice" {
+				t.Errorf("Expected example to be resolved from components, got %+v", example)
+			}
+		}
+
+		jsonBytes, err := json.Marshal(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var parsed map[string]any
+		if err := json.Unmarshal(jsonBytes, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		lookup := func(keys ...string) any {
+			var value any = parsed
+			for _, key := range keys {
+				obj, _ := value.(map[string]any)
+				value = obj[key]
+			}
+			return value
+		}
+
+		if got := lookup("components", "examples", "Alice", "summary"); got != "A typical user" {
+			t.Errorf("Expected components.examples.Alice to be emitted, got %v", lookup("components", "examples"))
+		}
+		refs := []any{
+			lookup("paths", "/users", "post", "requestBody", "content", "application/json", "examples", "alice"),
+			lookup("paths", "/users/{name}", "get", "responses", "200", "content", "application/json", "examples", "alice"),
+		}
+		for _, ref := range refs {
+			if obj, _ := ref.(map[string]any); len(obj) != 1 || obj["$ref"] != "#/components/examples/Alice" {
+				t.Errorf("Expected example to be emitted as a $ref, got %v", ref)
+			}
+		}
+	})
+
+	t.Run("dangling example reference", func(t *testing.T) {
+		_, err := gopenapi.NewServerMux(newSpec("#/components/examples/Bob"))
+		if err == nil || !strings.Contains(err.Error(), "example not found: Bob") {
+			t.Errorf("Expected a missing example error, got %v", err)
+		}
+	})
+}
+
+func TestArrayQueryParameterItems(t *testing.T) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info: gopenapi.Info{
+			Title:   "Test API",
+			Version: "1.0.0",
+		},
+		Paths: gopenapi.Paths{
+			"/items": {
+				Get: &gopenapi.Operation{
+					Security: gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{
+							Name:   "ids",
+							In:     gopenapi.InQuery,
+							Schema: gopenapi.Schema{Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.Integer}},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var ids []int
+						if err := gopenapi.ValidateRequestQueryValue(r, "ids", &ids); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusOK, ids)
+					}),
+				},
+			},
+		},
+		Servers: gopenapi.Servers{
+			{URL: "/"},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{query: "ids=1&ids=2", wantStatus: http.StatusOK, wantBody: "[1,2]"},
+		{query: "ids=x", wantStatus: http.StatusBadRequest, wantBody: "item 0"},
+		{query: "ids=1&ids=2.5", wantStatus: http.StatusBadRequest, wantBody: "item 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+
+	jsonBytes, err := json.Marshal(spec.Paths["/items"].Get.Parameters[0].Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(jsonBytes) != `{"items":{"type":"integer"},"type":"array"}` {
+		t.Errorf("Unexpected array schema JSON %s", jsonBytes)
+	}
+}
+
+func TestValidateResponses(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		ID       int       `json:"id"`
+		Name     string    `json:"name"`
+		Nickname string    `json:"nickname,omitempty"`
+		Address  *Address  `json:"address,omitempty"`
+		Created  time.Time `json:"created"`
+	}
+
+	var body any
+	newSpec := func(validate bool) *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI: "3.0.0",
+			Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers: gopenapi.Servers{{URL: "/"}},
+			Paths: gopenapi.Paths{
+				"/users/{id}": {
+					Get: &gopenapi.Operation{
+						OperationId: "getUser",
+						Security:    gopenapi.NoSecurity,
+						Responses: gopenapi.Responses{
+							200: {
+								Description: "The user",
+								Content: gopenapi.Content{
+									gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[User]()}},
+								},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							gopenapi.WriteResponse(w, http.StatusOK, body)
+						}),
+					},
+				},
+			},
+			ValidateResponses: validate,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		body       any
+		wantStatus int
+		wantBody   string // In the body of valid responses
+		wantLog    string // In the log of rejected responses
+	}{
+		{
+			name:       "valid struct",
+			body:       User{ID: 1, Name: "alice"},
+			wantStatus: http.StatusOK,
+			wantBody:   `"name":"alice"`,
+		},
+		{
+			name:       "missing required field",
+			body:       map[string]any{"name": "alice", "created": "2024-01-01T00:00:00Z"},
+			wantStatus: http.StatusInternalServerError,
+			wantLog:    `$: missing required property \"id\"`,
+		},
+		{
+			name:       "wrong field type",
+			body:       map[string]any{"id": "1", "name": "alice", "created": "2024-01-01T00:00:00Z"},
+			wantStatus: http.StatusInternalServerError,
+			wantLog:    "$.id: expected integer, got string",
+		},
+		{
+			name:       "missing nested required field",
+			body:       map[string]any{"id": 1, "name": "alice", "created": "2024-01-01T00:00:00Z", "address": map[string]any{}},
+			wantStatus: http.StatusInternalServerError,
+			wantLog:    `$.address: missing required property \"city\"`,
+		},
+	}
+
+	var logs bytes.Buffer
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(logger)
+
+	for _, validate := range []bool{true, false} {
+		mux, err := gopenapi.NewServerMux(newSpec(validate))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/validate=%v", tt.name, validate), func(t *testing.T) {
+				body = tt.body
+				logs.Reset()
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+				// Without ValidateResponses every body is written as-is
+				wantStatus := tt.wantStatus
+				if !validate {
+					wantStatus = http.StatusOK
+				}
+				if rec.Code != wantStatus {
+					t.Fatalf("Expected status %d, got %d: %s", wantStatus, rec.Code, rec.Body)
+				}
+				if !validate || tt.wantLog == "" {
+					if !strings.Contains(rec.Body.String(), tt.wantBody) {
+						t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+					}
+					return
+				}
+
+				// Rejected responses are answered with a problem, the mismatch is only logged
+				if got := rec.Header().Get("Content-Type"); got != string(gopenapi.ApplicationProblemJSON) {
+					t.Errorf("Expected Content-Type %s, got %q", gopenapi.ApplicationProblemJSON, got)
+				}
+				var problem gopenapi.Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+					t.Fatalf("Expected a problem body, got %q: %v", rec.Body, err)
+				}
+				if problem.Status != http.StatusInternalServerError || strings.Contains(problem.Detail, "$") {
+					t.Errorf("Expected a 500 problem without the mismatch, got %+v", problem)
+				}
+				if !strings.Contains(logs.String(), tt.wantLog) {
+					t.Errorf("Expected the log to contain %q, got %q", tt.wantLog, logs.String())
+				}
+			})
+		}
+	}
+
+	// A custom ErrorResponder writes rejected responses
+	spec := newSpec(true)
+	spec.ErrorResponder = func(w http.ResponseWriter, r *http.Request, status int, err error) {
+		http.Error(w, "custom", status)
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body = map[string]any{"name": "alice"}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if rec.Code != http.StatusInternalServerError || strings.TrimSpace(rec.Body.String()) != "custom" {
+		t.Errorf("Expected the ErrorResponder to write the 500, got %d %q", rec.Code, rec.Body)
+	}
+}
+
+func TestValidateResponsesMarshalerTypes(t *testing.T) {
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI:           "3.0.0",
+		Info:              gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:           gopenapi.Servers{{URL: "/"}},
+		ValidateResponses: true,
+		Paths: gopenapi.Paths{
+			"/now": {
+				Get: &gopenapi.Operation{
+					OperationId: "getTime",
+					Security:    gopenapi.NoSecurity,
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "The current time",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: reflect.TypeOf(time.Time{})}},
+							},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						gopenapi.WriteResponse(w, http.StatusOK, time.Now())
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// time.Time marshals itself as a string, not the object its kind suggests
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/now", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestValidateRequestQueryValues(t *testing.T) {
+	type Order string
+	type ListQuery struct {
+		Search   string `json:"search"`
+		Limit    int    `json:"limit"`
+		Archived bool   `json:"archived"`
+		Order    Order  `json:"order"`
+		Tags     []int  `json:"tags"`
+	}
+
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/items": {
+				Get: &gopenapi.Operation{
+					OperationId: "listItems",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "search", In: gopenapi.InQuery, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+						{Name: "archived", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Boolean}},
+						{Name: "order", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "tags", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.Integer}}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						query := ListQuery{Limit: 10}
+						if err := gopenapi.ValidateRequestQueryValues(r, &query); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusOK, query)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			query:      "search=cat&limit=5&archived=true&order=desc&tags=1&tags=2",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"search":"cat","limit":5,"archived":true,"order":"desc","tags":[1,2]}`,
+		},
+		{
+			// Optional parameters keep their value when absent
+			query:      "search=cat",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"search":"cat","limit":10,"archived":false,"order":"","tags":null}`,
+		},
+		{query: "limit=5", wantStatus: http.StatusBadRequest, wantBody: "missing required query parameter search"},
+		{query: "search=cat&limit=five", wantStatus: http.StatusBadRequest, wantBody: "'limit'"},
+		{query: "search=cat&archived=maybe", wantStatus: http.StatusBadRequest, wantBody: "'archived'"},
+		{query: "search=cat&tags=1&tags=x", wantStatus: http.StatusBadRequest, wantBody: "item 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+}
+
+func TestWriteResponseWith(t *testing.T) {
+	rec := httptest.NewRecorder()
+	gopenapi.WriteResponseWith(rec, http.StatusOK, "application/xml", []byte("<user><name>alice</name></user>"))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/xml" {
+		t.Errorf("Expected Content-Type application/xml, got %q", got)
+	}
+	if got := rec.Body.String(); got != "<user><name>alice</name></user>" {
+		t.Errorf("Expected the body as given, got %q", got)
+	}
+
+	// Pre-encoded JSON is validated like WriteResponse when ValidateResponses is set
+	var contentType string
+	var body []byte
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/user": {
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Security:    gopenapi.NoSecurity,
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "The user",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: UserSchema},
+								gopenapi.ApplicationXML:  {Schema: UserSchema},
+							},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						gopenapi.WriteResponseWith(w, http.StatusOK, contentType, body)
+					}),
+				},
+			},
+		},
+		ValidateResponses: true,
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer slog.SetDefault(logger)
+
+	tests := []struct {
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{contentType: "application/json; charset=utf-8", body: `{"name":"alice"}`, wantStatus: http.StatusOK},
+		{contentType: "application/json", body: `{}`, wantStatus: http.StatusInternalServerError},
+		{contentType: "application/xml", body: "<user/>", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType+" "+tt.body, func(t *testing.T) {
+			contentType, body = tt.contentType, []byte(tt.body)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/user", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if tt.wantStatus == http.StatusOK && (rec.Header().Get("Content-Type") != tt.contentType || rec.Body.String() != tt.body) {
+				t.Errorf("Expected %s %q, got %s %q", tt.contentType, tt.body, rec.Header().Get("Content-Type"), rec.Body)
+			}
+		})
+	}
+}
+
+func TestValidateRequestHeaderValues(t *testing.T) {
+	type Headers struct {
+		RequestID string `header:"x-request-id"`
+		Version   int    `json:"API-Version"`
+		Trace     bool   `header:"X-Trace"`
+	}
+
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/items": {
+				Get: &gopenapi.Operation{
+					OperationId: "listItems",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "X-Request-ID", In: gopenapi.InHeader, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "api-version", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+						{Name: "X-Trace", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.Boolean}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						headers := Headers{Version: 1}
+						if err := gopenapi.ValidateRequestHeaderValues(r, &headers); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusOK, headers)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "all headers",
+			headers:    map[string]string{"X-Request-Id": "abc", "Api-Version": "2", "X-Trace": "true"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"RequestID":"abc","API-Version":2,"Trace":true}`,
+		},
+		{
+			name:       "optional headers absent",
+			headers:    map[string]string{"x-request-id": "abc"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"RequestID":"abc","API-Version":1,"Trace":false}`,
+		},
+		{
+			name:       "missing required header",
+			headers:    map[string]string{"Api-Version": "2"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "missing required header X-Request-ID",
+		},
+		{
+			name:       "invalid integer",
+			headers:    map[string]string{"X-Request-Id": "abc", "Api-Version": "two"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "'api-version'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/items", nil)
+			for name, value := range tt.headers {
+				request.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, request)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+}
+
+func TestValidateRequestCookieValues(t *testing.T) {
+	type Cookies struct {
+		Session string `cookie:"session"`
+		Theme   string `json:"theme"`
+		Visits  int    `cookie:"visits"`
+	}
+
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/profile": {
+				Get: &gopenapi.Operation{
+					OperationId: "getProfile",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "session", In: gopenapi.InCookie, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "theme", In: gopenapi.InCookie, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "visits", In: gopenapi.InCookie, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						cookies := Cookies{Theme: "light"}
+						if err := gopenapi.ValidateRequestCookieValues(r, &cookies); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusOK, cookies)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		cookies    map[string]string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "all cookies",
+			cookies:    map[string]string{"session": "abc", "theme": "dark", "visits": "3"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"Session":"abc","theme":"dark","Visits":3}`,
+		},
+		{
+			name:       "optional cookies absent",
+			cookies:    map[string]string{"session": "abc"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"Session":"abc","theme":"light","Visits":0}`,
+		},
+		{
+			name:       "missing required cookie",
+			cookies:    map[string]string{"theme": "dark"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "missing required cookie session",
+		},
+		{
+			name:       "malformed integer",
+			cookies:    map[string]string{"session": "abc", "visits": "many"},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "cookie parameter validation failed for 'visits'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/profile", nil)
+			for name, value := range tt.cookies {
+				request.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, request)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+}
+
+func TestParameterDefaults(t *testing.T) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/items": {
+				Get: &gopenapi.Operation{
+					OperationId: "listItems",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "limit", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Integer, Default: 20}},
+						{Name: "tags", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.Array, Items: &gopenapi.Schema{Type: gopenapi.String}, Default: []string{"a", "b"}}},
+						{Name: "X-Region", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.String, Default: "eu"}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						spec, _ := gopenapi.SpecFromRequest(r)
+						op, _ := gopenapi.OperationFromRequest(r)
+						if _, err := spec.ValidationMiddleware.ValidateRequest(op, r); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						var limit int
+						if err := gopenapi.ValidateRequestQueryValue(r, "limit", &limit); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						fmt.Fprintf(w, "limit=%d tags=%v region=%s", limit, r.URL.Query()["tags"], r.Header.Get("X-Region"))
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		region   string
+		wantBody string
+	}{
+		{name: "absent", wantBody: "limit=20 tags=[a b] region=eu"},
+		{name: "present", query: "limit=5&tags=c", region: "us", wantBody: "limit=5 tags=[c] region=us"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/items?"+tt.query, nil)
+			if tt.region != "" {
+				req.Header.Set("X-Region", tt.region)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+
+	t.Run("binders", func(t *testing.T) {
+		type ListQuery struct {
+			Limit int `json:"limit"`
+		}
+		type ListHeaders struct {
+			Region string `header:"X-Region"`
+		}
+		spec.Paths["/items"].Get.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var query ListQuery
+			var headers ListHeaders
+			if err := gopenapi.ValidateRequestQueryValues(r, &query); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := gopenapi.ValidateRequestHeaderValues(r, &headers); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, "limit=%d region=%s", query.Limit, headers.Region)
+		})
+		mux, err := gopenapi.NewServerMux(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		if want := "limit=20 region=eu"; rec.Body.String() != want {
+			t.Errorf("Expected body %q, got %q", want, rec.Body)
+		}
+	})
+}
+
+func TestParameterRange(t *testing.T) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/shelves/{shelf}/items": {
+				Get: &gopenapi.Operation{
+					OperationId: "listItems",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "shelf", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer, Minimum: gopenapi.Ptr(0.0), Maximum: gopenapi.Ptr(10.0), ExclusiveMaximum: true}},
+						{Name: "page", In: gopenapi.InQuery, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer, Minimum: gopenapi.Ptr(1.0), Maximum: gopenapi.Ptr(100.0)}},
+						{Name: "X-Ratio", In: gopenapi.InHeader, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Number, Minimum: gopenapi.Ptr(0.0), ExclusiveMinimum: true}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						spec, _ := gopenapi.SpecFromRequest(r)
+						op, _ := gopenapi.OperationFromRequest(r)
+						if _, err := spec.ValidationMiddleware.ValidateRequest(op, r); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		ratio      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "in range", target: "/shelves/0/items?page=1", ratio: "0.5", wantStatus: http.StatusOK},
+		{name: "upper bounds", target: "/shelves/9/items?page=100", ratio: "1", wantStatus: http.StatusOK},
+		{name: "below minimum", target: "/shelves/1/items?page=0", ratio: "1", wantStatus: http.StatusBadRequest, wantBody: "value 0 is less than minimum 1"},
+		{name: "above maximum", target: "/shelves/1/items?page=101", ratio: "1", wantStatus: http.StatusBadRequest, wantBody: "value 101 is greater than maximum 100"},
+		{name: "exclusive minimum", target: "/shelves/1/items?page=1", ratio: "0", wantStatus: http.StatusBadRequest, wantBody: "value 0 must be greater than 0"},
+		{name: "exclusive maximum", target: "/shelves/10/items?page=1", ratio: "1", wantStatus: http.StatusBadRequest, wantBody: "value 10 must be less than 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("X-Ratio", tt.ratio)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+
+	schemaJSON, err := json.Marshal(spec.Paths["/shelves/{shelf}/items"].Get.Parameters[0].Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"exclusiveMaximum":true,"maximum":10,"minimum":0,"type":"integer"}`; string(schemaJSON) != want {
+		t.Errorf("Expected schema JSON %s, got %s", want, schemaJSON)
+	}
+}
+
+func TestStringConstraints(t *testing.T) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/posts/{slug}": {
+				Get: &gopenapi.Operation{
+					OperationId: "getPost",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "slug", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String, Pattern: `^[a-z0-9-]+$`}},
+						{Name: "q", In: gopenapi.InQuery, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String, MinLength: gopenapi.Ptr(2), MaxLength: gopenapi.Ptr(5)}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						spec, _ := gopenapi.SpecFromRequest(r)
+						op, _ := gopenapi.OperationFromRequest(r)
+						if _, err := spec.ValidationMiddleware.ValidateRequest(op, r); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "matching pattern", target: "/posts/hello-world?q=go", wantStatus: http.StatusOK},
+		{name: "failing pattern", target: "/posts/Hello_World?q=go", wantStatus: http.StatusBadRequest, wantBody: `path parameter validation failed for 'slug': value "Hello_World" does not match pattern ^[a-z0-9-]+$`},
+		{name: "too short", target: "/posts/hello?q=g", wantStatus: http.StatusBadRequest, wantBody: "query parameter validation failed for 'q': value length 1 is less than minLength 2"},
+		{name: "too long", target: "/posts/hello?q=gopher", wantStatus: http.StatusBadRequest, wantBody: "query parameter validation failed for 'q': value length 6 is greater than maxLength 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		spec.Paths["/posts/{slug}"].Get.Parameters[0].Schema.Pattern = `^[a-z`
+		_, err := gopenapi.NewServerMux(spec)
+		if err == nil || !strings.Contains(err.Error(), "parameter slug in /posts/{slug}: invalid pattern") {
+			t.Errorf("Expected an invalid pattern error for slug, got %v", err)
+		}
+	})
+}
+
+func TestStringFormats(t *testing.T) {
+	spec := &gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/events": {
+				Get: &gopenapi.Operation{
+					OperationId: "listEvents",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "email", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Format: "email"}},
+						{Name: "id", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Format: "uuid"}},
+						{Name: "since", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Format: "date-time"}},
+						{Name: "callback", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Format: "uri"}},
+						{Name: "color", In: gopenapi.InQuery, Schema: gopenapi.Schema{Type: gopenapi.String, Format: "hex-color"}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						spec, _ := gopenapi.SpecFromRequest(r)
+						op, _ := gopenapi.OperationFromRequest(r)
+						if _, err := spec.ValidationMiddleware.ValidateRequest(op, r); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := "email=ada%40example.com&id=0b5e2d3c-1f4a-4c8e-9a6b-7d2f1e3c4b5a&since=2024-05-01T12:00:00Z&callback=https%3A%2F%2Fexample.com%2Fhook&color=not-a-color"
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "valid", query: valid, wantStatus: http.StatusOK},
+		{name: "invalid email", query: "email=ada.example.com", wantStatus: http.StatusBadRequest, wantBody: `'email': value "ada.example.com" is not a valid email`},
+		{name: "invalid uuid", query: "id=0b5e2d3c-1f4a-4c8e-9a6b", wantStatus: http.StatusBadRequest, wantBody: `'id': value "0b5e2d3c-1f4a-4c8e-9a6b" is not a valid uuid`},
+		{name: "invalid date-time", query: "since=2024-05-01", wantStatus: http.StatusBadRequest, wantBody: `'since': value "2024-05-01" is not a valid date-time`},
+		{name: "relative uri", query: "callback=%2Fhook", wantStatus: http.StatusBadRequest, wantBody: `'callback': value "/hook" is not a valid uri`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events?"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %q", tt.wantBody, rec.Body)
+			}
+		})
+	}
+}
+
+func TestValidateRequestsProblemJSON(t *testing.T) {
+	newSpec := func() *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI:          "3.0.0",
+			Info:             gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:          gopenapi.Servers{{URL: "/"}},
+			ValidateRequests: true,
+			Paths: gopenapi.Paths{
+				"/items": {
+					Get: &gopenapi.Operation{
+						OperationId: "listItems",
+						Security:    gopenapi.NoSecurity,
+						Parameters: gopenapi.Parameters{
+							{Name: "limit", In: gopenapi.InQuery, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+							{Name: "X-Page", In: gopenapi.InHeader, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+						}),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("default responder", func(t *testing.T) {
+		mux, err := gopenapi.NewServerMux(newSpec())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?limit=10", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d for a valid request, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set("X-Page", "two")
+		rec = httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("Expected Content-Type application/problem+json, got %q", got)
+		}
+
+		var problem gopenapi.Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		if problem.Title != "Bad Request" || problem.Status != http.StatusBadRequest {
+			t.Errorf("Expected title Bad Request and status 400, got %q and %d", problem.Title, problem.Status)
+		}
+		if !strings.Contains(problem.Detail, "missing required query parameter limit") {
+			t.Errorf("Expected detail to name the missing parameter, got %q", problem.Detail)
+		}
+		if len(problem.Errors) != 2 {
+			t.Fatalf("Expected 2 field errors, got %+v", problem.Errors)
+		}
+		if want := (gopenapi.FieldError{In: gopenapi.InQuery, Name: "limit", Detail: "missing required parameter"}); problem.Errors[0] != want {
+			t.Errorf("Expected field error %+v, got %+v", want, problem.Errors[0])
+		}
+		if got := problem.Errors[1]; got.In != gopenapi.InHeader || got.Name != "X-Page" || !strings.Contains(got.Detail, "invalid syntax") {
+			t.Errorf("Expected an X-Page header field error, got %+v", got)
+		}
+	})
+
+	t.Run("custom responder", func(t *testing.T) {
+		spec := newSpec()
+		spec.ErrorResponder = func(w http.ResponseWriter, r *http.Request, status int, err error) {
+			http.Error(w, "rejected: "+err.Error(), status)
+		}
+		mux, err := gopenapi.NewServerMux(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body)
+		}
+		if want := "rejected: gopenapi: missing required query parameter limit\n"; rec.Body.String() != want {
+			t.Errorf("Expected body %q, got %q", want, rec.Body)
+		}
+	})
+
+	t.Run("WriteError from a handler", func(t *testing.T) {
+		type ListQuery struct {
+			Limit int `json:"limit"`
+		}
+		spec := newSpec()
+		spec.ValidateRequests = false
+		spec.Paths["/items"].Get.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var query ListQuery
+			if err := gopenapi.ValidateRequestQueryValues(r, &query); err != nil {
+				gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		})
+		mux, err := gopenapi.NewServerMux(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items?limit=ten", nil))
+		var problem gopenapi.Problem
+		if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		if len(problem.Errors) != 1 || problem.Errors[0].Name != "limit" || problem.Errors[0].In != gopenapi.InQuery {
+			t.Errorf("Expected a single limit field error, got %+v", problem.Errors)
+		}
+	})
+}
+
+func TestPathItemReferences(t *testing.T) {
+	newSpec := func(ref string) *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI: "3.1.0",
+			Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers: gopenapi.Servers{{URL: "/"}},
+			Components: gopenapi.Components{
+				PathItems: gopenapi.PathItems{
+					"Health": {
+						Get: &gopenapi.Operation{
+							OperationId: "health",
+							Security:    gopenapi.NoSecurity,
+							Responses:   gopenapi.Responses{200: {Description: "OK"}},
+							Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+								fmt.Fprint(w, "ok")
+							}),
+						},
+					},
+				},
+			},
+			Paths: gopenapi.Paths{
+				"/healthz": {Ref: ref},
+			},
+		}
+	}
+
+	spec := newSpec("#/components/pathItems/Health")
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Errorf("Expected the referenced path item to serve /healthz, got %d: %s", rec.Code, rec.Body)
+	}
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			PathItems map[string]map[string]any `json:"pathItems"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(specJSON, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Paths["/healthz"]; len(got) != 1 || got["$ref"] != "#/components/pathItems/Health" {
+		t.Errorf("Expected /healthz to serialize as a $ref, got %v", got)
+	}
+	if _, ok := doc.Components.PathItems["Health"]["get"]; !ok {
+		t.Errorf("Expected components.pathItems.Health.get, got %v", doc.Components.PathItems)
+	}
+
+	for ref, wantErr := range map[string]string{
+		"shared.yaml#/components/pathItems/Health": "external references not supported",
+		"#/components/pathItems/Missing":           "path item not found: Missing",
+	} {
+		if _, err := gopenapi.NewServerMux(newSpec(ref)); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Expected error containing %q for %s, got %v", wantErr, ref, err)
+		}
+	}
+}
+
+func TestCORS(t *testing.T) {
+	newServer := func(cors *gopenapi.CORS) *gopenapi.Server {
+		server, err := gopenapi.NewServer(&gopenapi.Spec{
+			OpenAPI: "3.0.0",
+			Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers: gopenapi.Servers{{URL: "/"}},
+			CORS:    cors,
+			Paths: gopenapi.Paths{
+				"/items": {
+					Put: &gopenapi.Operation{
+						OperationId: "putItems",
+						Security:    gopenapi.NoSecurity,
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusOK)
+						}),
+					},
+				},
+			},
+		}, "0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return server
+	}
+	server := newServer(&gopenapi.CORS{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+
+	t.Run("preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", "authorization")
+		rec := httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
+		expected := map[string]string{
+			"Access-Control-Allow-Origin":      "https://app.example.com",
+			"Access-Control-Allow-Methods":     "GET, PUT",
+			"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Max-Age":           "600",
+		}
+		for name, value := range expected {
+			if got := rec.Header().Get(name); got != value {
+				t.Errorf("Expected %s %q, got %q", name, value, got)
+			}
+		}
+	})
+
+	t.Run("preflight from disallowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		rec := httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no Access-Control-Allow-Origin, got %q", got)
+		}
+	})
+
+	t.Run("simple request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/items", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+			t.Errorf("Expected the origin to be echoed, got %q", got)
+		}
+		if got := rec.Header().Get("Vary"); got != "Origin" {
+			t.Errorf("Expected Vary Origin, got %q", got)
+		}
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPut, "/items", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		rec := httptest.NewRecorder()
+		newServer(nil).Handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+			t.Errorf("Expected no CORS headers without Spec.CORS, got %q", got)
+		}
+	})
+}
+
+func TestRecoverPanics(t *testing.T) {
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer slog.SetDefault(logger)
+
+	newSpec := func(recoverPanics *bool) *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI:       "3.0.0",
+			Info:          gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:       gopenapi.Servers{{URL: "/"}},
+			RecoverPanics: recoverPanics,
+			Paths: gopenapi.Paths{
+				"/items/{id}": {
+					Get: &gopenapi.Operation{
+						OperationId: "getItem",
+						Security:    gopenapi.NoSecurity,
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							if r.PathValue("id") == "boom" {
+								panic("boom")
+							}
+							w.WriteHeader(http.StatusOK)
+						}),
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("default", func(t *testing.T) {
+		mux, err := gopenapi.NewServerMux(newSpec(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		resp, err := http.Get(server.URL + "/items/boom")
+		if err != nil {
+			t.Fatalf("Expected a response to a panicking handler, got %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("Expected Content-Type application/problem+json, got %q", got)
+		}
+		var problem gopenapi.Problem
+		if err := json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		if problem.Title != "Internal Server Error" || problem.Status != http.StatusInternalServerError {
+			t.Errorf("Expected title Internal Server Error and status 500, got %q and %d", problem.Title, problem.Status)
+		}
+		if strings.Contains(problem.Detail, "boom") {
+			t.Errorf("Expected the panic value not to be exposed, got %q", problem.Detail)
+		}
+
+		resp, err = http.Get(server.URL + "/items/42")
+		if err != nil {
+			t.Fatalf("Expected the server to keep serving, got %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected status %d after a panic, got %d", http.StatusOK, resp.StatusCode)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mux, err := gopenapi.NewServerMux(newSpec(gopenapi.Ptr(false)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer func() {
+			if recovered := recover(); recovered != "boom" {
+				t.Errorf("Expected the panic to propagate, got %v", recovered)
+			}
+		}()
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/boom", nil))
+	})
+}
+
+func TestNewServerOptions(t *testing.T) {
+	server, err := gopenapi.NewServer(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/users": {
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: UserSchema},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var user User
+						if err := gopenapi.ValidateRequestBody(r, &user); err != nil {
+							http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+							return
+						}
+						w.WriteHeader(http.StatusCreated)
+					}),
+				},
+			},
+		},
+	}, "0",
+		gopenapi.WithReadTimeout(5*time.Second),
+		gopenapi.WithWriteTimeout(10*time.Second),
+		gopenapi.WithIdleTimeout(time.Minute),
+		gopenapi.WithMaxBodyBytes(32),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if server.ReadTimeout != 5*time.Second {
+		t.Errorf("Expected ReadTimeout 5s, got %v", server.ReadTimeout)
+	}
+	if server.WriteTimeout != 10*time.Second {
+		t.Errorf("Expected WriteTimeout 10s, got %v", server.WriteTimeout)
+	}
+	if server.IdleTimeout != time.Minute {
+		t.Errorf("Expected IdleTimeout 1m, got %v", server.IdleTimeout)
+	}
+
+	for _, tt := range []struct {
+		body   string
+		status int
+	}{
+		{`{"name":"Ada"}`, http.StatusCreated},
+		{`{"name":"` + strings.Repeat("a", 64) + `"}`, http.StatusRequestEntityTooLarge},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("Expected status %d for a %d byte body, got %d: %s", tt.status, len(tt.body), rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestDocsPath(t *testing.T) {
+	newSpec := func(docsPath string) *gopenapi.Spec {
+		return &gopenapi.Spec{
+			OpenAPI:  "3.0.0",
+			Info:     gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:  gopenapi.Servers{{URL: "/"}},
+			DocsPath: docsPath,
+			Paths: gopenapi.Paths{
+				"/docs": {
+					Get: &gopenapi.Operation{
+						OperationId: "getDocs",
+						Security:    gopenapi.NoSecurity,
+						Handler:     http.HandlerFunc(getDocsHandler),
+					},
+				},
+			},
+		}
+	}
+
+	server, err := gopenapi.NewServer(newSpec("/api-docs"), "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api-docs", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Expected an HTML page, got Content-Type %q", got)
+	}
+	body := rec.Body.String()
+	for _, expected := range []string{"<title>Test API</title>", `src="/api-docs/docs.js" data-spec-url="/openapi.json"`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the docs page to contain %q, got:\n%s", expected, body)
+		}
+	}
+
+	// The page script and the Swagger UI files are served below DocsPath
+	assets := []string{"/api-docs/docs.js"}
+	if strings.Contains(body, `src="/api-docs/swagger-ui-bundle.js"`) {
+		assets = append(assets, "/api-docs/swagger-ui-bundle.js", "/api-docs/swagger-ui.css")
+	} else if !strings.Contains(body, gopenapi.DefaultDocsAssetsURL+"/swagger-ui-bundle.js") {
+		t.Errorf("Expected the docs page to load Swagger UI from DocsPath or DefaultDocsAssetsURL, got:\n%s", body)
+	}
+	for _, asset := range assets {
+		rec = httptest.NewRecorder()
+		server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, asset, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusOK, asset, rec.Code)
+		}
+		if rec.Body.Len() == 0 {
+			t.Errorf("Expected %s to have a body", asset)
+		}
+	}
+	rec = httptest.NewRecorder()
+	server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api-docs/docs.js", nil))
+	if got := rec.Header().Get("Content-Type"); !strings.Contains(got, "javascript") {
+		t.Errorf("Expected a JavaScript Content-Type for docs.js, got %q", got)
+	}
+	if !strings.Contains(rec.Body.String(), "SwaggerUIBundle") {
+		t.Errorf("Expected docs.js to start Swagger UI, got:\n%s", rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api-docs/missing.js", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a missing asset, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	// DocsAssetsURL overrides the embedded Swagger UI files
+	spec := newSpec("/api-docs")
+	spec.DocsAssetsURL = "/swagger-ui/"
+	server, err = gopenapi.NewServer(spec, "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api-docs", nil))
+	body = rec.Body.String()
+	for _, expected := range []string{`src="/swagger-ui/swagger-ui-bundle.js"`, `href="/swagger-ui/swagger-ui.css"`, `src="/api-docs/docs.js"`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the docs page to contain %q, got:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(body, "cdn.jsdelivr.net") {
+		t.Errorf("Expected no CDN assets with DocsAssetsURL set, got:\n%s", body)
+	}
+
+	if _, err := gopenapi.NewServer(newSpec("/docs"), "0"); err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Expected an error for a DocsPath used by an operation, got %v", err)
+	}
+}
+
+func TestOpenAPIDocumentExclusiveBounds(t *testing.T) {
+	ratio := gopenapi.Schema{
+		Type:             gopenapi.Number,
+		Minimum:          gopenapi.Ptr(0.0),
+		ExclusiveMinimum: true,
+		Maximum:          gopenapi.Ptr(1.0),
+	}
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"3.0.3", `{"exclusiveMinimum":true,"maximum":1,"minimum":0,"type":"number"}`},
+		{"3.1.0", `{"exclusiveMinimum":0,"maximum":1,"type":"number"}`},
+	}
+	for _, tt := range tests {
+		spec := &gopenapi.Spec{
+			OpenAPI: tt.version,
+			Components: gopenapi.Components{
+				Schemas: gopenapi.Schemas{"Ratio": ratio},
+			},
+		}
+		document, err := gopenapi.MarshalOpenAPIJSON(spec, gopenapi.OpenAPIOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var result struct {
+			Components struct {
+				Schemas map[string]json.RawMessage `json:"schemas"`
+			} `json:"components"`
+		}
+		if err := json.Unmarshal(document, &result); err != nil {
+			t.Fatal(err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, result.Components.Schemas["Ratio"]); err != nil {
+			t.Fatal(err)
+		}
+		if compact.String() != tt.expected {
+			t.Errorf("OpenAPI %s: expected schema %s, got %s", tt.version, tt.expected, compact.String())
+		}
+	}
+}
+
+func TestOpenAPIJSONHandler(t *testing.T) {
+	type Node struct {
+		Name     string            `json:"name"`
+		Parent   *Node             `json:"parent,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Created  time.Time         `json:"created"`
+		Metadata any               `json:"metadata,omitempty"`
+	}
+	spec := &gopenapi.Spec{
+		OpenAPI:         "3.1.0",
+		Info:            gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:         gopenapi.Servers{{URL: "/"}},
+		OpenAPIJSONPath: "/spec.json",
+		DocsPath:        "/docs",
+		Paths: gopenapi.Paths{
+			"/nodes/{id}": {
+				Get: &gopenapi.Operation{
+					OperationId: "getNode",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+					},
+					Responses: gopenapi.Responses{
+						200: {
+							Description: "The node",
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Node]()}},
+							},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+				},
+			},
+		},
+	}
+	mux, err := gopenapi.NewServerMux(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/spec.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", got)
+	}
+
+	var doc struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			Parameters []struct {
+				Schema map[string]any `json:"schema"`
+			} `json:"parameters"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v\n%s", err, rec.Body)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected openapi 3.1.0, got %q", doc.OpenAPI)
+	}
+	get, ok := doc.Paths["/nodes/{id}"]["get"]
+	if !ok {
+		t.Fatalf("Expected GET /nodes/{id} in paths, got %v", doc.Paths)
+	}
+	if got := get.Parameters[0].Schema["type"]; got != "integer" {
+		t.Errorf("Expected the id parameter to be an integer, got %v", got)
+	}
+
+	schema := get.Responses["200"].Content["application/json"].Schema
+	expected := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":     map[string]any{"type": "string"},
+			"parent":   map[string]any{"type": "object"},
+			"labels":   map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+			"created":  map[string]any{"type": "string", "format": "date-time"},
+			"metadata": map[string]any{"type": "object"},
+		},
+		"required": []any{"name", "created"},
+	}
+	if !reflect.DeepEqual(schema, expected) {
+		t.Errorf("Expected resolved schema\n%v\ngot\n%v", expected, schema)
+	}
+
+	// Values that may hold any JSON value are described by the empty schema on request
+	spec.OpenAPIOptions.EmptyAnySchema = true
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/spec.json", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v\n%s", err, rec.Body)
+	}
+	schema = doc.Paths["/nodes/{id}"]["get"].Responses["200"].Content["application/json"].Schema
+	if got := schema["properties"].(map[string]any)["metadata"]; !reflect.DeepEqual(got, map[string]any{}) {
+		t.Errorf("Expected the empty schema for metadata with EmptyAnySchema, got %v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if !strings.Contains(rec.Body.String(), `data-spec-url="/spec.json"`) {
+		t.Errorf("Expected the docs page to load /spec.json, got:\n%s", rec.Body)
+	}
+}
+
+func TestValidateRequestBodyContentTypeParameters(t *testing.T) {
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/users": {
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: UserSchema},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var user User
+						if err := gopenapi.ValidateRequestBody(r, &user); err != nil {
+							http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusCreated, user)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		contentType string
+		status      int
+	}{
+		{"application/json", http.StatusCreated},
+		{"application/json; charset=utf-8", http.StatusCreated},
+		{"Application/JSON;charset=UTF-8", http.StatusCreated},
+		{"text/plain; charset=utf-8", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Ada"}`))
+		req.Header.Set("Content-Type", tt.contentType)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("Expected status %d for Content-Type %q, got %d: %s", tt.status, tt.contentType, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestMaxRequestBodySize(t *testing.T) {
+	newMux := func(limit *int64) http.Handler {
+		mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+			OpenAPI:            "3.0.0",
+			Info:               gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:            gopenapi.Servers{{URL: "/"}},
+			MaxRequestBodySize: limit,
+			Paths: gopenapi.Paths{
+				"/users": {
+					Post: &gopenapi.Operation{
+						OperationId: "createUser",
+						Security:    gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: UserSchema},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							var user User
+							if err := gopenapi.ValidateRequestBody(r, &user); err != nil {
+								var tooLarge *gopenapi.BodyTooLargeError
+								if errors.As(err, &tooLarge) {
+									gopenapi.WriteError(w, r, http.StatusRequestEntityTooLarge, err)
+									return
+								}
+								gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+								return
+							}
+							w.WriteHeader(http.StatusCreated)
+						}),
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mux
+	}
+	body := func(size int) string {
+		return `{"name":"` + strings.Repeat("a", size-len(`{"name":""}`)) + `"}`
+	}
+
+	tests := []struct {
+		name   string
+		limit  *int64
+		body   string
+		status int
+	}{
+		{"under limit", gopenapi.Ptr[int64](64), body(64), http.StatusCreated},
+		{"over limit", gopenapi.Ptr[int64](64), body(65), http.StatusRequestEntityTooLarge},
+		{"under default limit", nil, body(1 << 20), http.StatusCreated},
+		{"over default limit", nil, body(1<<20 + 1), http.StatusRequestEntityTooLarge},
+		{"unlimited", gopenapi.Ptr[int64](0), body(2 << 20), http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			newMux(tt.limit).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %.200s", tt.status, rec.Code, rec.Body)
+			}
+			if tt.status == http.StatusRequestEntityTooLarge && !strings.Contains(rec.Body.String(), "request body exceeds") {
+				t.Errorf("Expected the problem to describe the limit, got %s", rec.Body)
+			}
+		})
+	}
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r    io.Reader
+	read int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+func TestMaxRequestBodySizeLogging(t *testing.T) {
+	var logs bytes.Buffer
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI:            "3.0.0",
+		Info:               gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:            gopenapi.Servers{{URL: "/"}},
+		ValidateRequests:   true,
+		MaxRequestBodySize: gopenapi.Ptr[int64](64),
+		LoggingMiddleware: &gopenapi.DefaultLoggingMiddleware{
+			Logger: slog.New(slog.NewTextHandler(&logs, nil)),
+		},
+		Paths: gopenapi.Paths{
+			"/users": {
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: UserSchema},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var user User
+						if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+							http.Error(w, err.Error(), http.StatusBadRequest)
+							return
+						}
+						w.WriteHeader(http.StatusCreated)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		body   io.Reader
+		status int
+	}{
+		{"under limit", strings.NewReader(`{"name":"Ada"}`), http.StatusCreated},
+		{"over limit", io.MultiReader(strings.NewReader(`{"name":"`), io.LimitReader(neverEnding('a'), 50<<20)), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &countingReader{r: tt.body}
+			req := httptest.NewRequest(http.MethodPost, "/users", body)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %.200s", tt.status, rec.Code, rec.Body)
+			}
+			// The logging middleware reads the body through the limit, which
+			// may read one byte past it to detect longer bodies
+			if body.read > 65 {
+				t.Errorf("Expected at most 65 bytes read, got %d", body.read)
+			}
+		})
+	}
+	if !strings.Contains(logs.String(), `body="{\"name\":\"Ada\"}"`) {
+		t.Errorf("Expected the body under the limit to be logged, got:\n%s", logs.String())
+	}
+}
+
+// neverEnding is an endless reader of its byte
+type neverEnding byte
+
+func (b neverEnding) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(b)
+	}
+	return len(p), nil
+}
+
+func TestWithMaxBodyBytes(t *testing.T) {
+	newServer := func(opts ...gopenapi.ServerOption) *gopenapi.Server {
+		server, err := gopenapi.NewServer(&gopenapi.Spec{
+			OpenAPI:          "3.0.0",
+			Info:             gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:          gopenapi.Servers{{URL: "/"}},
+			ValidateRequests: true,
+			Paths: gopenapi.Paths{
+				"/users": {
+					Post: &gopenapi.Operation{
+						OperationId: "createUser",
+						Security:    gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: UserSchema},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							w.WriteHeader(http.StatusCreated)
+						}),
+					},
+				},
+			},
+		}, "0", opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return server
+	}
+	body := func(size int) string {
+		return `{"name":"` + strings.Repeat("a", size-len(`{"name":""}`)) + `"}`
+	}
+
+	tests := []struct {
+		name   string
+		opts   []gopenapi.ServerOption
+		body   string
+		status int
+	}{
+		{"over default limit", nil, body(2 << 20), http.StatusRequestEntityTooLarge},
+		{"raised limit", []gopenapi.ServerOption{gopenapi.WithMaxBodyBytes(8 << 20)}, body(2 << 20), http.StatusCreated},
+		{"over raised limit", []gopenapi.ServerOption{gopenapi.WithMaxBodyBytes(8 << 20)}, body(8<<20 + 1), http.StatusRequestEntityTooLarge},
+		{"lowered limit", []gopenapi.ServerOption{gopenapi.WithMaxBodyBytes(32)}, body(64), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			newServer(tt.opts...).Handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %.200s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestValidateRequestBodySchema(t *testing.T) {
+	// Member embeds User, whose fields are promoted to the member's JSON
+	type Member struct {
+		User
+		Role string `json:"role"`
+	}
+	newMux := func(validateRequests, rejectUnknown bool, handled *bool) http.Handler {
+		mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+			OpenAPI:             "3.0.0",
+			Info:                gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:             gopenapi.Servers{{URL: "/"}},
+			ValidateRequests:    validateRequests,
+			RejectUnknownFields: rejectUnknown,
+			Paths: gopenapi.Paths{
+				"/users": {
+					Post: &gopenapi.Operation{
+						OperationId: "createUser",
+						Security:    gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: UserSchema},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							*handled = true
+							var user User
+							if err := gopenapi.ValidateRequestBody(r, &user); err != nil {
+								gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+								return
+							}
+							gopenapi.WriteResponse(w, http.StatusCreated, user)
+						}),
+					},
+				},
+				"/members": {
+					Post: &gopenapi.Operation{
+						OperationId: "createMember",
+						Security:    gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[Member]()}},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							*handled = true
+							var member Member
+							if err := gopenapi.ValidateRequestBody(r, &member); err != nil {
+								gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+								return
+							}
+							gopenapi.WriteResponse(w, http.StatusCreated, member)
+						}),
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mux
+	}
+
+	tests := []struct {
+		name             string
+		validateRequests bool
+		rejectUnknown    bool
+		body             string
+		status           int
+		detail           string
+	}{
+		{"valid body", false, false, `{"name":"Ada"}`, http.StatusCreated, ""},
+		{"missing required field", false, false, `{}`, http.StatusBadRequest, "missing required property"},
+		{"wrong field type", false, false, `{"name":1}`, http.StatusBadRequest, ""},
+		{"unknown field", false, false, `{"name":"Ada","age":36}`, http.StatusCreated, ""},
+		{"unknown field rejected", false, true, `{"name":"Ada","age":36}`, http.StatusBadRequest, "unknown property"},
+		{"missing required field before handler", true, false, `{}`, http.StatusBadRequest, "missing required property"},
+		{"unknown field rejected before handler", true, true, `{"name":"Ada","age":36}`, http.StatusBadRequest, "unknown property"},
+		{"valid body after middleware", true, true, `{"name":"Ada"}`, http.StatusCreated, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled bool
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			newMux(tt.validateRequests, tt.rejectUnknown, &handled).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.detail) {
+				t.Errorf("Expected the response to contain %q, got %s", tt.detail, rec.Body)
+			}
+			if tt.validateRequests && tt.status == http.StatusBadRequest && handled {
+				t.Error("Expected the request to be rejected before the handler ran")
+			}
+		})
+	}
+
+	embeddedTests := []struct {
+		name          string
+		rejectUnknown bool
+		body          string
+		status        int
+		detail        string
+	}{
+		{"embedded fields", true, `{"name":"Ada","role":"admin"}`, http.StatusCreated, ""},
+		{"missing embedded field", false, `{"role":"admin"}`, http.StatusBadRequest, "missing required property"},
+		{"unknown field next to embedded fields", true, `{"name":"Ada","role":"admin","age":36}`, http.StatusBadRequest, "unknown property"},
+	}
+	for _, tt := range embeddedTests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled bool
+			req := httptest.NewRequest(http.MethodPost, "/members", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			newMux(true, tt.rejectUnknown, &handled).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.detail) {
+				t.Errorf("Expected the response to contain %q, got %s", tt.detail, rec.Body)
+			}
+		})
+	}
+}
+
+func TestSchemaHelpers(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema gopenapi.Schema
+		want   gopenapi.Schema
+		json   string
+	}{
+		{
+			name:   "StringEnum",
+			schema: gopenapi.StringEnum("asc", "desc"),
+			want:   gopenapi.Schema{Type: gopenapi.String, Enum: []any{"asc", "desc"}},
+			json:   `{"enum":["asc","desc"],"type":"string"}`,
+		},
+		{
+			name:   "IntRange",
+			schema: gopenapi.IntRange(1, 100),
+			want:   gopenapi.Schema{Type: gopenapi.Integer, Minimum: gopenapi.Ptr(1.0), Maximum: gopenapi.Ptr(100.0)},
+			json:   `{"maximum":100,"minimum":1,"type":"integer"}`,
+		},
+		{
+			name:   "ArrayOf",
+			schema: gopenapi.ArrayOf(gopenapi.StringEnum("a", "b")),
+			want: gopenapi.Schema{
+				Type:  gopenapi.Array,
+				Items: &gopenapi.Schema{Type: gopenapi.String, Enum: []any{"a", "b"}},
+			},
+			json: `{"items":{"enum":["a","b"],"type":"string"},"type":"array"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.schema, tt.want) {
+				t.Errorf("Expected %+v, got %+v", tt.want, tt.schema)
+			}
+			data, err := json.Marshal(tt.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Expected %s, got %s", tt.json, data)
+			}
+		})
+	}
+}
+
+func TestSchemaHelpersValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  gopenapi.Schema
+		values  []string
+		wantErr string
+	}{
+		{name: "enum value", schema: gopenapi.StringEnum("asc", "desc"), values: []string{"asc"}},
+		{name: "value outside enum", schema: gopenapi.StringEnum("asc", "desc"), values: []string{"up"}, wantErr: `value "up" is not one of [asc desc]`},
+		{name: "lower bound", schema: gopenapi.IntRange(1, 100), values: []string{"1"}},
+		{name: "upper bound", schema: gopenapi.IntRange(1, 100), values: []string{"100"}},
+		{name: "out of range", schema: gopenapi.IntRange(1, 100), values: []string{"101"}, wantErr: "greater than maximum"},
+		{name: "array items", schema: gopenapi.ArrayOf(gopenapi.IntRange(1, 10)), values: []string{"1", "10"}},
+		{name: "invalid array item", schema: gopenapi.ArrayOf(gopenapi.IntRange(1, 10)), values: []string{"1", "11"}, wantErr: "item 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.schema.ValidateValues(tt.values)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected %v to validate, got %v", tt.values, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+type Cat struct {
+	Meows bool `json:"meows"`
+}
+
+type Dog struct {
+	Barks bool `json:"barks"`
+}
+
+func TestSchemaComposition(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema gopenapi.Schema
+		json   string
+	}{
+		{
+			name:   "allOf",
+			schema: gopenapi.Schema{AllOf: []gopenapi.Schema{{Ref: "#/components/schemas/Cat"}, {Type: gopenapi.Object[Dog]()}}},
+			json:   `{"allOf":[{"$ref":"#/components/schemas/Cat"},{"properties":{"barks":{"type":"boolean"}},"required":["barks"],"type":"object"}]}`,
+		},
+		{
+			name:   "oneOf",
+			schema: gopenapi.Schema{OneOf: []gopenapi.Schema{{Ref: "#/components/schemas/Cat"}, {Ref: "#/components/schemas/Dog"}}},
+			json:   `{"oneOf":[{"$ref":"#/components/schemas/Cat"},{"$ref":"#/components/schemas/Dog"}]}`,
+		},
+		{
+			name:   "anyOf",
+			schema: gopenapi.Schema{AnyOf: []gopenapi.Schema{{Type: gopenapi.String}, {Type: gopenapi.Integer}}},
+			json:   `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.schema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Expected %s, got %s", tt.json, data)
+			}
+		})
+	}
+}
+
+func TestSchemaCompositionValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  gopenapi.Schema
+		value   string
+		wantErr string
+	}{
+		{
+			name:   "oneOf matching one",
+			schema: gopenapi.Schema{OneOf: []gopenapi.Schema{{Type: gopenapi.Object[Cat]()}, {Type: gopenapi.Object[Dog]()}}},
+			value:  `{"meows": true}`,
+		},
+		{
+			name:    "oneOf matching none",
+			schema:  gopenapi.Schema{OneOf: []gopenapi.Schema{{Type: gopenapi.Object[Cat]()}, {Type: gopenapi.Object[Dog]()}}},
+			value:   `{"purrs": true}`,
+			wantErr: "matches 0 of the oneOf schemas",
+		},
+		{
+			name:    "oneOf matching both",
+			schema:  gopenapi.Schema{OneOf: []gopenapi.Schema{{Type: gopenapi.Object[Cat]()}, {Type: gopenapi.Object[Dog]()}}},
+			value:   `{"meows": true, "barks": true}`,
+			wantErr: "matches 2 of the oneOf schemas",
+		},
+		{
+			name:   "allOf matching all",
+			schema: gopenapi.Schema{AllOf: []gopenapi.Schema{{Type: gopenapi.Object[Cat]()}, {Type: gopenapi.Object[Dog]()}}},
+			value:  `{"meows": true, "barks": false}`,
+		},
+		{
+			name:    "allOf missing a member's property",
+			schema:  gopenapi.Schema{AllOf: []gopenapi.Schema{{Type: gopenapi.Object[Cat]()}, {Type: gopenapi.Object[Dog]()}}},
+			value:   `{"meows": true}`,
+			wantErr: `allOf member 1: gopenapi: $: missing required property "barks"`,
+		},
+		{
+			name:   "anyOf matching one",
+			schema: gopenapi.Schema{AnyOf: []gopenapi.Schema{{Type: gopenapi.String}, {Type: gopenapi.Integer}}},
+			value:  `42`,
+		},
+		{
+			name:    "anyOf matching none",
+			schema:  gopenapi.Schema{AnyOf: []gopenapi.Schema{{Type: gopenapi.String}, {Type: gopenapi.Integer}}},
+			value:   `true`,
+			wantErr: "matches none of the anyOf schemas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.schema.Validate(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected %s to validate, got %v", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSchemaCompositionRefs(t *testing.T) {
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI:          "3.0.0",
+		Info:             gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:          gopenapi.Servers{{URL: "/"}},
+		ValidateRequests: true,
+		Components: gopenapi.Components{
+			Schemas: gopenapi.Schemas{
+				"Cat": {Type: gopenapi.Object[Cat]()},
+				"Dog": {Type: gopenapi.Object[Dog]()},
+				"Pet": {OneOf: []gopenapi.Schema{{Ref: "#/components/schemas/Cat"}, {Ref: "#/components/schemas/Dog"}}},
+			},
+		},
+		Paths: gopenapi.Paths{
+			"/pets": {
+				Post: &gopenapi.Operation{
+					OperationId: "createPet",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: "#/components/schemas/Pet"}},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusCreated)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"meows": true}`, http.StatusCreated},
+		{`{"barks": true}`, http.StatusCreated},
+		{`{"purrs": true}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/pets", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("Expected status %d for %s, got %d: %s", tt.status, tt.body, rec.Code, rec.Body)
+		}
+	}
+}
+
+type UserCreated struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type UserDeleted struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+}
+
+func TestSchemaDiscriminator(t *testing.T) {
+	event := gopenapi.Schema{
+		OneOf: []gopenapi.Schema{
+			{Ref: "#/components/schemas/UserCreated"},
+			{Ref: "#/components/schemas/UserDeleted"},
+		},
+		Discriminator: &gopenapi.Discriminator{
+			PropertyName: "type",
+			Mapping: map[string]string{
+				"user.created": "#/components/schemas/UserCreated",
+				"user.deleted": "UserDeleted",
+			},
+		},
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"discriminator":{"mapping":{"user.created":"#/components/schemas/UserCreated","user.deleted":"UserDeleted"},"propertyName":"type"},"oneOf":[{"$ref":"#/components/schemas/UserCreated"},{"$ref":"#/components/schemas/UserDeleted"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var received []string
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Components: gopenapi.Components{
+			Schemas: gopenapi.Schemas{
+				"UserCreated": {Type: gopenapi.Object[UserCreated]()},
+				"UserDeleted": {Type: gopenapi.Object[UserDeleted]()},
+				"Event":       event,
+			},
+		},
+		Paths: gopenapi.Paths{
+			"/events": {
+				Post: &gopenapi.Operation{
+					OperationId: "receiveEvent",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Ref: "#/components/schemas/Event"}},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body map[string]any
+						if err := gopenapi.ValidateRequestBody(r, &body); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						received = append(received, body["type"].(string))
+						w.WriteHeader(http.StatusNoContent)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		detail string
+	}{
+		{"created", `{"type":"user.created","name":"Ada"}`, http.StatusNoContent, ""},
+		{"deleted", `{"type":"user.deleted","id":1}`, http.StatusNoContent, ""},
+		// Valid for UserCreated, but routed to UserDeleted by its type
+		{"wrong variant", `{"type":"user.deleted","name":"Ada"}`, http.StatusBadRequest, "missing required property"},
+		{"unknown type", `{"type":"user.renamed","name":"Ada"}`, http.StatusBadRequest, "unknown type"},
+		{"missing type", `{"name":"Ada"}`, http.StatusBadRequest, "missing discriminator property"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.detail) {
+				t.Errorf("Expected the response to contain %q, got %s", tt.detail, rec.Body)
+			}
+		})
+	}
+	if !reflect.DeepEqual(received, []string{"user.created", "user.deleted"}) {
+		t.Errorf("Expected the valid events to reach the handler, got %v", received)
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	var decoded User
+	var decodeErr error
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/users": {
+				Post: &gopenapi.Operation{
+					OperationId: "createUser",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{
+							gopenapi.ApplicationJSON: {Schema: UserSchema},
+						},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						decoded, decodeErr = gopenapi.DecodeBody[User](r)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := func(body string) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		mux.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	post(`{"name":"Ada"}`)
+	if decodeErr != nil {
+		t.Fatalf("DecodeBody() error = %v", decodeErr)
+	}
+	if decoded != (User{Name: "Ada"}) {
+		t.Errorf("Expected %+v, got %+v", User{Name: "Ada"}, decoded)
+	}
+
+	post(`{"name":`)
+	var syntaxErr *json.SyntaxError
+	if !errors.As(decodeErr, &syntaxErr) {
+		t.Errorf("Expected a *json.SyntaxError, got %v", decodeErr)
+	}
+	if decoded != (User{}) {
+		t.Errorf("Expected the zero value for a malformed body, got %+v", decoded)
+	}
+
+	post(`{}`)
+	if decodeErr == nil || !strings.Contains(decodeErr.Error(), `missing required property "name"`) {
+		t.Errorf("Expected a missing property error, got %v", decodeErr)
+	}
+}
+
+func TestWriteResponseContentNegotiation(t *testing.T) {
+	newMux := func(validateResponses bool) http.Handler {
+		mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+			OpenAPI:           "3.0.0",
+			Info:              gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:           gopenapi.Servers{{URL: "/"}},
+			ValidateResponses: validateResponses,
+			Encoders: map[gopenapi.MediaType]gopenapi.Encoder{
+				"application/xml": func(w io.Writer, v any) error {
+					return xml.NewEncoder(w).Encode(v)
+				},
+			},
+			Paths: gopenapi.Paths{
+				"/user": {
+					Get: &gopenapi.Operation{
+						OperationId: "getUser",
+						Security:    gopenapi.NoSecurity,
+						Responses: gopenapi.Responses{
+							200: {
+								Description: "User",
+								Content: gopenapi.Content{
+									gopenapi.ApplicationJSON: {Schema: UserSchema},
+									"application/xml":        {Schema: UserSchema},
+								},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							gopenapi.WriteResponse(w, http.StatusOK, User{Name: "Ada"})
+						}),
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mux
+	}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json", "application/json", `{"name":"Ada"}` + "\n"},
+		{"xml", "application/xml", "application/xml", `<User><Name>Ada</Name></User>`},
+		{"no accept header", "", "application/json", `{"name":"Ada"}` + "\n"},
+		{"unsupported", "text/csv", "application/json", `{"name":"Ada"}` + "\n"},
+		{"exact match over wildcard", "*/*, application/xml", "application/xml", `<User><Name>Ada</Name></User>`},
+		{"quality", "application/xml;q=0.5, application/json", "application/json", `{"name":"Ada"}` + "\n"},
+	}
+	for _, validateResponses := range []bool{false, true} {
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%s/validate=%v", tt.name, validateResponses), func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/user", nil)
+				if tt.accept != "" {
+					req.Header.Set("Accept", tt.accept)
+				}
+				rec := httptest.NewRecorder()
+				newMux(validateResponses).ServeHTTP(rec, req)
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+				}
+				if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+					t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+				}
+				if rec.Body.String() != tt.body {
+					t.Errorf("Expected body %q, got %q", tt.body, rec.Body)
+				}
+			})
+		}
+	}
+}
+
+func TestRequiredRequestBody(t *testing.T) {
+	newMux := func(validateRequests, required bool, handled *bool) http.Handler {
+		mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+			OpenAPI:          "3.0.0",
+			Info:             gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+			Servers:          gopenapi.Servers{{URL: "/"}},
+			ValidateRequests: validateRequests,
+			Paths: gopenapi.Paths{
+				"/users": {
+					Post: &gopenapi.Operation{
+						OperationId: "createUser",
+						Security:    gopenapi.NoSecurity,
+						RequestBody: gopenapi.RequestBody{
+							Required: required,
+							Content: gopenapi.Content{
+								gopenapi.ApplicationJSON: {Schema: UserSchema},
+							},
+						},
+						Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+							*handled = true
+							if required {
+								if _, err := gopenapi.DecodeBody[User](r); err != nil {
+									gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+									return
+								}
+							}
+							w.WriteHeader(http.StatusCreated)
+						}),
+					},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mux
+	}
+
+	tests := []struct {
+		name             string
+		validateRequests bool
+		required         bool
+		status           int
+		handled          bool
+	}{
+		{"rejected by middleware", true, true, http.StatusBadRequest, false},
+		{"rejected by handler", false, true, http.StatusBadRequest, true},
+		{"optional", true, false, http.StatusCreated, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled bool
+			req := httptest.NewRequest(http.MethodPost, "/users", nil)
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			newMux(tt.validateRequests, tt.required, &handled).ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if tt.status == http.StatusBadRequest && !strings.Contains(rec.Body.String(), "missing required request body") {
+				t.Errorf("Expected a missing request body error, got %s", rec.Body)
+			}
+			if handled != tt.handled {
+				t.Errorf("Expected handled = %v, got %v", tt.handled, handled)
+			}
+		})
+	}
+
+	data, err := json.Marshal(gopenapi.RequestBody{Required: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"required":true`) {
+		t.Errorf("Expected required to be serialized, got %s", data)
+	}
+}
+
+// flushRecorder records the body written before each flush
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+func TestWriteStream(t *testing.T) {
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	body := io.MultiReader(strings.NewReader("line 1\n"), strings.NewReader("line 2\n"), strings.NewReader("line 3\n"))
+	if err := gopenapi.WriteStream(rec, http.StatusOK, body); err != nil {
+		t.Fatalf("WriteStream() error = %v", err)
+	}
+	expected := []string{"", "line 1\n", "line 1\nline 2\n", "line 1\nline 2\nline 3\n"}
+	if !reflect.DeepEqual(rec.flushes, expected) {
+		t.Errorf("Expected flushes %q, got %q", expected, rec.flushes)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Expected Content-Type application/octet-stream, got %s", got)
+	}
+
+	// Over HTTP, each piece reaches the client before the next is written
+	pr, pw := io.Pipe()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_ = gopenapi.WriteStream(w, http.StatusOK, pr)
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if !reflect.DeepEqual(resp.TransferEncoding, []string{"chunked"}) {
+		t.Errorf("Expected a chunked response, got %v", resp.TransferEncoding)
+	}
+	buf := make([]byte, 64)
+	for _, piece := range []string{"first\n", "second\n"} {
+		if _, err := io.WriteString(pw, piece); err != nil {
+			t.Fatal(err)
+		}
+		n, err := io.ReadAtLeast(resp.Body, buf, len(piece))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != piece {
+			t.Errorf("Expected %q, got %q", piece, buf[:n])
+		}
+	}
+	pw.Close()
+}
+
+func TestWriteSSE(t *testing.T) {
+	events := make(chan gopenapi.ServerSentEvent, 2)
+	events <- gopenapi.ServerSentEvent{ID: "1", Event: "log", Data: "started"}
+	events <- gopenapi.ServerSentEvent{Data: "line 1\nline 2", Retry: 3 * time.Second}
+	close(events)
+
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	req := httptest.NewRequest(http.MethodGet, "/logs", nil)
+	if err := gopenapi.WriteSSE(rec, req, events); err != nil {
+		t.Fatalf("WriteSSE() error = %v", err)
+	}
+	if got := rec.Header().Get("Content-Type"); got != string(gopenapi.TextEventStream) {
+		t.Errorf("Expected Content-Type %s, got %s", gopenapi.TextEventStream, got)
+	}
+	first := "id: 1\nevent: log\ndata: started\n\n"
+	expected := []string{"", first, first + "retry: 3000\ndata: line 1\ndata: line 2\n\n"}
+	if !reflect.DeepEqual(rec.flushes, expected) {
+		t.Errorf("Expected flushes %q, got %q", expected, rec.flushes)
+	}
+
+	// A disconnected client stops the stream
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req = httptest.NewRequest(http.MethodGet, "/logs", nil).WithContext(ctx)
+	if err := gopenapi.WriteSSE(httptest.NewRecorder(), req, make(chan gopenapi.ServerSentEvent)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSchemaContentEncoding(t *testing.T) {
+	schema := gopenapi.Schema{Type: gopenapi.String, ContentMediaType: "application/json", ContentEncoding: "base64"}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"contentEncoding":"base64","contentMediaType":"application/json","type":"string"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestSchemaSkipsIgnoredFields(t *testing.T) {
+	type account struct {
+		ID     int    `json:"id"`
+		Secret string `json:"-"`
+		Dash   string `json:"-,"`
+	}
+	data, err := json.Marshal(gopenapi.Schema{Type: gopenapi.Object[account]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"properties":{"-":{"type":"string"},"id":{"type":"integer"}},"required":["id","-"],"type":"object"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestSchemaEmbeddedFields(t *testing.T) {
+	type base struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type resource struct {
+		base
+		Name string `json:"name,omitempty"`
+	}
+	data, err := json.Marshal(gopenapi.Schema{Type: gopenapi.Object[resource]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id"],"type":"object"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestSchemaTagOptions(t *testing.T) {
+	type order struct {
+		ID   int64  `json:"id,string"`
+		Note string `json:"note,omitempty"`
+	}
+	data, err := json.Marshal(gopenapi.Schema{Type: gopenapi.Object[order]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"properties":{"id":{"type":"string"},"note":{"type":"string"}},"required":["id"],"type":"object"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestValidateStringOptionFields(t *testing.T) {
+	type order struct {
+		Count int `json:"count,string"`
+	}
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI:           "3.0.0",
+		Info:              gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:           gopenapi.Servers{{URL: "/"}},
+		ValidateRequests:  true,
+		ValidateResponses: true,
+		Paths: gopenapi.Paths{
+			"/orders": {
+				Post: &gopenapi.Operation{
+					OperationId: "createOrder",
+					Security:    gopenapi.NoSecurity,
+					RequestBody: gopenapi.RequestBody{
+						Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[order]()}}},
+					},
+					Responses: gopenapi.Responses{
+						201: {Content: gopenapi.Content{gopenapi.ApplicationJSON: {Schema: gopenapi.Schema{Type: gopenapi.Object[order]()}}}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var body order
+						if err := gopenapi.ValidateRequestBody(r, &body); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						gopenapi.WriteResponse(w, http.StatusCreated, body)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		body   string
+		status int
+	}{
+		{`{"count":"3"}`, http.StatusCreated},
+		{`{"count":3}`, http.StatusBadRequest},
+		{`{"count":"three"}`, http.StatusBadRequest},
+		{`{"count":"3.5"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.body, tt.status, rec.Code, rec.Body)
+		}
+		if tt.status == http.StatusCreated && rec.Body.String() != `{"count":"3"}`+"\n" {
+			t.Errorf("%s: expected the order to be echoed, got %s", tt.body, rec.Body)
+		}
+	}
+}
+
+func TestSecurityPrecedence(t *testing.T) {
+	tokenHandler := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-TOKEN") != "secret" {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Components: gopenapi.Components{
+			SecuritySchemes: gopenapi.SecuritySchemes{
+				"apiKey": {Type: gopenapi.APIKey, Handler: apiKeyHandler},
+				"token":  {Type: gopenapi.APIKey, Handler: tokenHandler},
+			},
+		},
+		Security: []gopenapi.Security{{"apiKey": []string{}}},
+		Paths: gopenapi.Paths{
+			"/inherited": {
+				Get: &gopenapi.Operation{OperationId: "inherited", Handler: ok},
+			},
+			"/public": {
+				Get: &gopenapi.Operation{OperationId: "public", Security: gopenapi.NoSecurity, Handler: ok},
+			},
+			"/overridden": {
+				Get: &gopenapi.Operation{
+					OperationId: "overridden",
+					Security:    []gopenapi.Security{{"token": []string{}}},
+					Handler:     ok,
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{"inherited without key", "/inherited", nil, http.StatusUnauthorized},
+		{"inherited with key", "/inherited", map[string]string{"X-API-KEY": "1234567890"}, http.StatusOK},
+		{"opted out", "/public", nil, http.StatusOK},
+		{"overridden with global key", "/overridden", map[string]string{"X-API-KEY": "1234567890"}, http.StatusUnauthorized},
+		{"overridden with own scheme", "/overridden", map[string]string{"X-TOKEN": "secret"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+		})
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	var id int
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI:          "3.0.0",
+		Info:             gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers:          gopenapi.Servers{{URL: "/"}},
+		ValidateRequests: true,
+		Paths: gopenapi.Paths{
+			"/items/{id}": {
+				Parameters: gopenapi.Parameters{
+					{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+				},
+				Get: &gopenapi.Operation{
+					OperationId: "getItem",
+					Security:    gopenapi.NoSecurity,
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						if err := gopenapi.ValidateRequestPathValue(r, "id", &id); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/abc", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for a non-integer id, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items/42", nil))
+	if rec.Code != http.StatusOK || id != 42 {
+		t.Errorf("Expected status %d and id 42, got %d and %d", http.StatusOK, rec.Code, id)
+	}
+}
+
+type ItemID int
+
+func TestValidateRequestPathValuesTypes(t *testing.T) {
+	type itemPath struct {
+		Org    *string  `json:"org"`
+		ID     ItemID   `json:"id"`
+		Price  *float64 `json:"price"`
+		Active bool     `json:"active"`
+	}
+	var got itemPath
+	mux, err := gopenapi.NewServerMux(&gopenapi.Spec{
+		OpenAPI: "3.0.0",
+		Info:    gopenapi.Info{Title: "Test API", Version: "1.0.0"},
+		Servers: gopenapi.Servers{{URL: "/"}},
+		Paths: gopenapi.Paths{
+			"/orgs/{org}/items/{id}/{price}/{active}": {
+				Get: &gopenapi.Operation{
+					OperationId: "getItem",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "org", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Integer}},
+						{Name: "price", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Number}},
+						{Name: "active", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.Boolean}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						got = itemPath{}
+						if err := gopenapi.ValidateRequestPathValues(r, &got); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+			"/users/{id}": {
+				Get: &gopenapi.Operation{
+					OperationId: "getUser",
+					Security:    gopenapi.NoSecurity,
+					Parameters: gopenapi.Parameters{
+						{Name: "id", In: gopenapi.InPath, Required: true, Schema: gopenapi.Schema{Type: gopenapi.String}},
+					},
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						var path struct {
+							ID int `json:"id"`
+						}
+						if err := gopenapi.ValidateRequestPathValues(r, &path); err != nil {
+							gopenapi.WriteError(w, r, http.StatusBadRequest, err)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orgs/acme/items/7/9.5/true", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+	if got.Org == nil || *got.Org != "acme" || got.ID != 7 || got.Price == nil || *got.Price != 9.5 || !got.Active {
+		t.Errorf("Unexpected path values %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orgs/acme/items/seven/9.5/true", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "path parameter validation failed for 'id'") {
+		t.Errorf("Expected a 400 naming the id parameter, got %d: %s", rec.Code, rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/alice", nil))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "expected int, got string") {
+		t.Errorf("Expected a 400 for a field of the wrong type, got %d: %s", rec.Code, rec.Body)
+	}
+}
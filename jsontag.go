@@ -0,0 +1,135 @@
+package gopenapi
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// HasJSONOption reports whether the json tag of a struct field sets option,
+// e.g. "omitempty" or "string"
+func HasJSONOption(tag reflect.StructTag, option string) bool {
+	_, options, _ := strings.Cut(tag.Get("json"), ",")
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// StringEncodable reports whether encoding/json applies the string option to
+// values of t: strings, numbers and booleans, or pointers to them. Such fields
+// tagged `json:",string"` are encoded as JSON strings, e.g. "42" for an int.
+func StringEncodable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// JSONField is a field of a struct, or of a struct embedded in it, under the
+// property name encoding/json serializes it with. Index is the index sequence
+// of the field for reflect.Value.FieldByIndex.
+type JSONField struct {
+	reflect.StructField
+	// JSONName is the name set by the json tag, falling back to the field name
+	JSONName string
+}
+
+// JSONFields lists the fields of the struct type t as encoding/json serializes
+// them: exported fields not tagged json:"-", with the fields of embedded
+// structs without a json name promoted to t. Among fields sharing a name the
+// least nested one wins, then the only tagged one among the least nested, and
+// otherwise the name is dropped. Fields keep the order their names first appear in.
+func JSONFields(t reflect.Type) []JSONField {
+	var fields []jsonField
+	collectJSONFields(t, nil, map[reflect.Type]bool{}, &fields)
+
+	byName := make(map[string][]jsonField)
+	var names []string
+	for _, field := range fields {
+		if _, ok := byName[field.JSONName]; !ok {
+			names = append(names, field.JSONName)
+		}
+		byName[field.JSONName] = append(byName[field.JSONName], field)
+	}
+
+	var dominant []JSONField
+	for _, name := range names {
+		candidates := byName[name]
+		depth := len(candidates[0].Index)
+		for _, field := range candidates {
+			depth = min(depth, len(field.Index))
+		}
+		var shallowest, tagged []jsonField
+		for _, field := range candidates {
+			if len(field.Index) != depth {
+				continue
+			}
+			shallowest = append(shallowest, field)
+			if field.tagged {
+				tagged = append(tagged, field)
+			}
+		}
+		switch {
+		case len(shallowest) == 1:
+			dominant = append(dominant, shallowest[0].JSONField)
+		case len(tagged) == 1:
+			dominant = append(dominant, tagged[0].JSONField)
+		}
+	}
+	return dominant
+}
+
+// jsonField is a JSONField candidate, tagged when its json tag sets its name
+type jsonField struct {
+	JSONField
+	tagged bool
+}
+
+// collectJSONFields appends the serialized fields of the struct type t, found
+// at index, to fields, recursing into embedded structs. Embedded structs being
+// collected by visiting are skipped, so that recursive embedding terminates.
+func collectJSONFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, fields *[]jsonField) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		field.Index = append(slices.Clone(index), i)
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				collectJSONFields(embedded, field.Index, visiting, fields)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = field.Name
+		}
+		*fields = append(*fields, jsonField{JSONField: JSONField{StructField: field, JSONName: name}, tagged: tagged})
+	}
+}
@@ -0,0 +1,163 @@
+package gopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// RedactedValue replaces the values of password-format fields in logs
+const RedactedValue = "[REDACTED]"
+
+// DefaultLoggingMiddleware logs one record per request with the operation,
+// status and duration. JSON request bodies are included with the values of
+// password-format fields replaced by RedactedValue.
+type DefaultLoggingMiddleware struct {
+	// Logger receives the records; slog.Default() is used when nil
+	Logger *slog.Logger
+}
+
+func (m *DefaultLoggingMiddleware) Apply(spec *Spec, operation *Operation) (MiddlewareHandler, error) {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := m.Logger
+			if logger == nil {
+				logger = slog.Default()
+			}
+
+			start := time.Now()
+			attrs := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"operationId", operation.OperationId,
+			}
+
+			// The body is read through Spec.MaxRequestBodySize. Reading errors,
+			// such as a body over the limit, are left for the handler to read.
+			if r.Body != nil && operation.RequestBody.Content != nil {
+				body, err := io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errorReader{err}))
+				} else {
+					r.Body = io.NopCloser(bytes.NewReader(body))
+					if len(body) > 0 {
+						attrs = append(attrs, "body", redactBody(operation, r.Header.Get("Content-Type"), body))
+					}
+				}
+			}
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			attrs = append(attrs, "status", recorder.status, "duration", time.Since(start))
+			logger.InfoContext(r.Context(), "gopenapi: request", attrs...)
+		})
+	}, nil
+}
+
+// errorReader fails every read with err
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// redactBody renders a request body for logging. Bodies that cannot be decoded
+// as JSON are summarized by size so that sensitive values are never logged raw.
+func redactBody(operation *Operation, contentType string, body []byte) string {
+	mediaType, ok := requestMediaType(operation, contentType)
+	if !ok {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	content := operation.RequestBody.Content[mediaType]
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(content.Schema, decoded))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return string(redacted)
+}
+
+// redactValue replaces the password-format parts of a decoded JSON value described by schema
+func redactValue(schema Schema, value any) any {
+	if schema.Format == FormatPassword {
+		return RedactedValue
+	}
+	if schema.Type == nil {
+		return value
+	}
+	return redactTypedValue(schema.Type, "", value)
+}
+
+// redactTypedValue walks a decoded JSON value alongside the Go type it was
+// declared with, redacting struct fields tagged `format:"password"`
+func redactTypedValue(t reflect.Type, format string, value any) any {
+	if format == FormatPassword {
+		return RedactedValue
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		// Fields of embedded structs are promoted, as in the decoded JSON
+		for _, field := range JSONFields(t) {
+			if fieldValue, ok := obj[field.JSONName]; ok {
+				obj[field.JSONName] = redactTypedValue(field.Type, field.Tag.Get("format"), fieldValue)
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i, item := range items {
+			items[i] = redactTypedValue(t.Elem(), "", item)
+		}
+		return items
+	case reflect.Map:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, item := range obj {
+			obj[key] = redactTypedValue(t.Elem(), "", item)
+		}
+		return obj
+	default:
+		return value
+	}
+}
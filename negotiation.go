@@ -0,0 +1,124 @@
+package gopenapi
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Encoder writes v to w in the format of a media type, e.g. for XML:
+//
+//	func(w io.Writer, v any) error { return xml.NewEncoder(w).Encode(v) }
+type Encoder func(w io.Writer, v any) error
+
+func encodeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// negotiate wraps next so that WriteResponse encodes bodies with the encoder
+// best matching the request's Accept header, or JSON when none matches
+func negotiate(encoders map[MediaType]Encoder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mediaType, encode := negotiateEncoder(encoders, r.Header.Get("Accept"))
+		next.ServeHTTP(&encodingWriter{ResponseWriter: w, mediaType: mediaType, encode: encode}, r)
+	})
+}
+
+// negotiateEncoder returns the media type and encoder accepted by accept with
+// the highest quality, preferring the most specific media range and then JSON
+func negotiateEncoder(encoders map[MediaType]Encoder, accept string) (MediaType, Encoder) {
+	candidates := []MediaType{ApplicationJSON}
+	for mediaType := range encoders {
+		if mediaType != ApplicationJSON {
+			candidates = append(candidates, mediaType)
+		}
+	}
+	slices.Sort(candidates[1:])
+
+	best := ApplicationJSON
+	bestQuality, bestSpecificity := 0.0, -1
+	for _, candidate := range candidates {
+		quality, specificity := acceptQuality(accept, candidate)
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && specificity > bestSpecificity) {
+			best, bestQuality, bestSpecificity = candidate, quality, specificity
+		}
+	}
+
+	if encode, ok := encoders[best]; ok {
+		return best, encode
+	}
+	return ApplicationJSON, encodeJSON
+}
+
+// acceptQuality returns the quality accept gives mediaType, taken from its most
+// specific matching media range, and the specificity of that range: 0 for */*,
+// 1 for type/* and 2 for an exact match. An empty accept accepts anything.
+func acceptQuality(accept string, mediaType MediaType) (float64, int) {
+	if strings.TrimSpace(accept) == "" {
+		return 1, 0
+	}
+	typ, _, _ := strings.Cut(string(mediaType), "/")
+
+	quality, specificity := 0.0, -1
+	for _, mediaRange := range strings.Split(accept, ",") {
+		rangeType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		rangeSpecificity := -1
+		switch {
+		case rangeType == "*/*":
+			rangeSpecificity = 0
+		case strings.HasSuffix(rangeType, "/*") && strings.TrimSuffix(rangeType, "/*") == typ:
+			rangeSpecificity = 1
+		case strings.EqualFold(rangeType, string(mediaType)):
+			rangeSpecificity = 2
+		}
+		if rangeSpecificity <= specificity {
+			continue
+		}
+		rangeQuality := 1.0
+		if q, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				rangeQuality = parsed
+			}
+		}
+		quality, specificity = rangeQuality, rangeSpecificity
+	}
+	return quality, specificity
+}
+
+// encodingWriter carries the encoder negotiated for a request to WriteResponse
+type encodingWriter struct {
+	http.ResponseWriter
+	mediaType MediaType
+	encode    Encoder
+}
+
+func (w *encodingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writeEncoded writes body with status using the encoder negotiated for the
+// request, setting its Content-Type, or as JSON when there is none
+func writeEncoded(w http.ResponseWriter, status int, body any) {
+	for writer := w; writer != nil; {
+		if encoding, ok := writer.(*encodingWriter); ok {
+			w.Header().Set("Content-Type", string(encoding.mediaType))
+			w.WriteHeader(status)
+			_ = encoding.encode(w, body)
+			return
+		}
+		unwrapper, ok := writer.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		writer = unwrapper.Unwrap()
+	}
+	w.WriteHeader(status)
+	_ = encodeJSON(w, body)
+}
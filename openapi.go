@@ -0,0 +1,601 @@
+package gopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIOptions controls how a Spec is rendered as an OpenAPI document
+type OpenAPIOptions struct {
+	// EmptyAnySchema describes interface{} and any values, which may hold any
+	// JSON value, with the empty schema {} instead of "type": "object"
+	EmptyAnySchema bool
+	// OperationId, when set, rewrites every emitted operationId, e.g. to camelCase
+	OperationId func(operationId string) string
+
+	// jsonSchema2020 renders schemas for OpenAPI 3.1, set by OpenAPIDocument
+	jsonSchema2020 bool
+}
+
+// MarshalOpenAPIJSON renders spec as an indented OpenAPI JSON document. HTML is
+// not escaped so that markdown descriptions read as written.
+func MarshalOpenAPIJSON(spec *Spec, opts OpenAPIOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(OpenAPIDocument(spec, opts)); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// OpenAPIDocument builds the OpenAPI document for spec as a generic map that
+// can be encoded as either JSON or YAML. Both OpenAPIJSONHandler and the
+// gopenapi CLI render specs with it, so that served and generated documents
+// match. Parameters shared by a path are listed by each of its operations.
+func OpenAPIDocument(spec *Spec, opts OpenAPIOptions) map[string]any {
+	// OpenAPI 3.1 schemas are JSON Schema 2020-12
+	opts.jsonSchema2020 = strings.HasPrefix(spec.OpenAPI, "3.1")
+
+	document := map[string]any{
+		"openapi": spec.OpenAPI,
+		"info": map[string]any{
+			"title":       spec.Info.Title,
+			"description": spec.Info.Description,
+			"version":     spec.Info.Version,
+		},
+	}
+
+	if len(spec.Servers) > 0 {
+		document["servers"] = serversToOpenAPI(spec.Servers)
+	}
+
+	if len(spec.Paths) > 0 {
+		paths := make(map[string]any, len(spec.Paths))
+		for pattern, path := range spec.Paths {
+			paths[pattern] = pathToOpenAPI(path, opts)
+		}
+		document["paths"] = paths
+	}
+
+	if components := componentsToOpenAPI(spec.Components, opts); len(components) > 0 {
+		document["components"] = components
+	}
+
+	if spec.Security != nil {
+		document["security"] = spec.Security
+	}
+
+	return document
+}
+
+func serversToOpenAPI(servers Servers) []map[string]any {
+	serversObj := make([]map[string]any, len(servers))
+	for i, server := range servers {
+		serversObj[i] = map[string]any{
+			"url":         server.URL,
+			"description": server.Description,
+		}
+	}
+	return serversObj
+}
+
+// componentsToOpenAPI renders the schemas, security schemes, examples and path
+// items of components, leaving out empty sections
+func componentsToOpenAPI(components Components, opts OpenAPIOptions) map[string]any {
+	componentsObj := map[string]any{}
+
+	if len(components.Schemas) > 0 {
+		schemas := make(map[string]any, len(components.Schemas))
+		for name, schema := range components.Schemas {
+			schemas[name] = OpenAPISchema(schema, opts)
+		}
+		componentsObj["schemas"] = schemas
+	}
+
+	if len(components.SecuritySchemes) > 0 {
+		schemes := make(map[string]any, len(components.SecuritySchemes))
+		for name, scheme := range components.SecuritySchemes {
+			schemes[name] = securitySchemeToOpenAPI(scheme)
+		}
+		componentsObj["securitySchemes"] = schemes
+	}
+
+	if len(components.Examples) > 0 {
+		componentsObj["examples"] = examplesToOpenAPI(components.Examples)
+	}
+
+	if len(components.PathItems) > 0 {
+		pathItems := make(map[string]any, len(components.PathItems))
+		for name, path := range components.PathItems {
+			pathItems[name] = pathToOpenAPI(path, opts)
+		}
+		componentsObj["pathItems"] = pathItems
+	}
+
+	return componentsObj
+}
+
+func securitySchemeToOpenAPI(scheme SecurityScheme) map[string]any {
+	schemeObj := map[string]any{
+		"type": string(scheme.Type),
+	}
+	if scheme.Scheme != "" {
+		schemeObj["scheme"] = string(scheme.Scheme)
+	}
+	if scheme.Name != "" {
+		schemeObj["name"] = scheme.Name
+	}
+	if scheme.In != "" {
+		schemeObj["in"] = string(scheme.In)
+	}
+	if scheme.Flows != nil {
+		flows := map[string]any{}
+		for name, flow := range map[string]*OAuthFlow{
+			"implicit":          scheme.Flows.Implicit,
+			"password":          scheme.Flows.Password,
+			"clientCredentials": scheme.Flows.ClientCredentials,
+			"authorizationCode": scheme.Flows.AuthorizationCode,
+		} {
+			if flow == nil {
+				continue
+			}
+			flowObj := map[string]any{}
+			if flow.AuthorizationURL != "" {
+				flowObj["authorizationUrl"] = flow.AuthorizationURL
+			}
+			if flow.TokenURL != "" {
+				flowObj["tokenUrl"] = flow.TokenURL
+			}
+			if flow.RefreshURL != "" {
+				flowObj["refreshUrl"] = flow.RefreshURL
+			}
+			if len(flow.Scopes) > 0 {
+				flowObj["scopes"] = flow.Scopes
+			}
+			flows[name] = flowObj
+		}
+		schemeObj["flows"] = flows
+	}
+	return schemeObj
+}
+
+// pathToOpenAPI renders the operations of path. Referenced path items are
+// rendered as their reference alone.
+func pathToOpenAPI(path Path, opts OpenAPIOptions) map[string]any {
+	if path.Ref != "" {
+		return map[string]any{"$ref": path.Ref}
+	}
+
+	pathObj := map[string]any{}
+	if path.Summary != "" {
+		pathObj["summary"] = path.Summary
+	}
+	if path.Description != "" {
+		pathObj["description"] = path.Description
+	}
+	if len(path.Servers) > 0 {
+		pathObj["servers"] = serversToOpenAPI(path.Servers)
+	}
+	for method, operation := range map[string]*Operation{
+		"get":     path.Get,
+		"post":    path.Post,
+		"put":     path.Put,
+		"delete":  path.Delete,
+		"patch":   path.Patch,
+		"head":    path.Head,
+		"options": path.Options,
+		"trace":   path.Trace,
+	} {
+		if operation != nil {
+			pathObj[method] = operationToOpenAPI(path, operation, opts)
+		}
+	}
+	return pathObj
+}
+
+// operationToOpenAPI renders an operation of path, listing the parameters
+// shared by the path with its own
+func operationToOpenAPI(path Path, op *Operation, opts OpenAPIOptions) map[string]any {
+	operation := map[string]any{}
+	parameters := path.OperationParameters(op)
+
+	if op.OperationId != "" {
+		operationId := op.OperationId
+		if opts.OperationId != nil {
+			operationId = opts.OperationId(operationId)
+		}
+		operation["operationId"] = operationId
+	}
+	if op.Summary != "" {
+		operation["summary"] = op.Summary
+	}
+	if op.Description != "" {
+		operation["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		operation["tags"] = op.Tags
+	}
+	if op.Deprecated {
+		operation["deprecated"] = true
+	}
+	// An empty list opts the operation out of the spec's security
+	if op.Security != nil {
+		operation["security"] = op.Security
+	}
+
+	// Parameters keep their declared order
+	if len(parameters) > 0 {
+		params := make([]map[string]any, len(parameters))
+		for i, param := range parameters {
+			params[i] = parameterToOpenAPI(param, opts)
+		}
+		operation["parameters"] = params
+	}
+
+	if op.RequestBody.Content != nil {
+		requestBody := map[string]any{
+			"required": op.RequestBody.Required,
+			"content":  contentToOpenAPI(op.RequestBody.Content, opts),
+		}
+		if op.RequestBody.Description != "" {
+			requestBody["description"] = op.RequestBody.Description
+		}
+		operation["requestBody"] = requestBody
+	}
+
+	if len(op.Responses) > 0 {
+		responses := make(map[string]any, len(op.Responses))
+		for statusCode, response := range op.Responses {
+			responseObj := map[string]any{
+				"description": response.Description,
+			}
+			if len(response.Headers) > 0 {
+				responseObj["headers"] = headersToOpenAPI(response.Headers, opts)
+			}
+			if response.Content != nil {
+				responseObj["content"] = contentToOpenAPI(response.Content, opts)
+			}
+			responses[strconv.Itoa(statusCode)] = responseObj
+		}
+		operation["responses"] = responses
+	}
+
+	if len(op.CodeSamples) > 0 {
+		samples := make([]map[string]any, len(op.CodeSamples))
+		for i, sample := range op.CodeSamples {
+			sampleObj := map[string]any{
+				"lang":   sample.Lang,
+				"source": sample.Source,
+			}
+			if sample.Label != "" {
+				sampleObj["label"] = sample.Label
+			}
+			samples[i] = sampleObj
+		}
+		operation["x-codeSamples"] = samples
+	}
+
+	// Default client timeout
+	if op.Timeout > 0 {
+		operation["x-timeout"] = op.Timeout.String()
+	}
+
+	// Link header pagination
+	if op.Paginated {
+		operation["x-pagination"] = true
+	}
+
+	return operation
+}
+
+func parameterToOpenAPI(param Parameter, opts OpenAPIOptions) map[string]any {
+	paramObj := map[string]any{
+		"name":     param.Name,
+		"in":       parameterLocation(param.In),
+		"required": param.Required,
+		"schema":   OpenAPISchema(param.Schema, opts),
+	}
+	if param.Description != "" {
+		paramObj["description"] = param.Description
+	}
+	if param.Deprecated {
+		paramObj["deprecated"] = true
+	}
+	if param.Explode != nil {
+		paramObj["explode"] = *param.Explode
+	}
+	if len(param.Examples) > 0 {
+		paramObj["examples"] = examplesToOpenAPI(param.Examples)
+	}
+	return paramObj
+}
+
+// parameterLocation returns the location of a parameter, defaulting to query
+func parameterLocation(in In) string {
+	switch in {
+	case InPath, InQuery, InHeader, InCookie:
+		return string(in)
+	default:
+		return string(InQuery)
+	}
+}
+
+func headersToOpenAPI(headers Headers, opts OpenAPIOptions) map[string]any {
+	headersObj := make(map[string]any, len(headers))
+	for name, header := range headers {
+		headerObj := map[string]any{
+			"schema": OpenAPISchema(header.Schema, opts),
+		}
+		if header.Description != "" {
+			headerObj["description"] = header.Description
+		}
+		if header.Required {
+			headerObj["required"] = true
+		}
+		if header.Deprecated {
+			headerObj["deprecated"] = true
+		}
+		headersObj[name] = headerObj
+	}
+	return headersObj
+}
+
+func contentToOpenAPI(content Content, opts OpenAPIOptions) map[string]any {
+	contentObj := make(map[string]any, len(content))
+	for mediaType, mediaTypeObj := range content {
+		mediaObj := map[string]any{
+			"schema": OpenAPISchema(mediaTypeObj.Schema, opts),
+		}
+		if len(mediaTypeObj.Examples) > 0 {
+			mediaObj["examples"] = examplesToOpenAPI(mediaTypeObj.Examples)
+		}
+		contentObj[string(mediaType)] = mediaObj
+	}
+	return contentObj
+}
+
+// examplesToOpenAPI renders examples, referenced ones as their reference alone
+func examplesToOpenAPI(examples Examples) map[string]any {
+	examplesObj := make(map[string]any, len(examples))
+	for name, example := range examples {
+		if example.Ref != "" {
+			examplesObj[name] = map[string]any{"$ref": example.Ref}
+			continue
+		}
+		exampleObj := map[string]any{}
+		if example.Summary != "" {
+			exampleObj["summary"] = example.Summary
+		}
+		if example.Description != "" {
+			exampleObj["description"] = example.Description
+		}
+		if example.Value != nil {
+			exampleObj["value"] = example.Value
+		}
+		if example.ExternalValue != "" {
+			exampleObj["externalValue"] = example.ExternalValue
+		}
+		examplesObj[name] = exampleObj
+	}
+	return examplesObj
+}
+
+// OpenAPISchema renders schema as an OpenAPI schema object, describing its Go
+// type by the OpenAPI type, properties and items of its values. Referenced
+// schemas are rendered as their reference alone, as NewServerMux copies the
+// referenced fields in for validation only. Exclusive bounds take the OpenAPI
+// 3.0 form outside of a 3.1 document rendered by OpenAPIDocument.
+func OpenAPISchema(schema Schema, opts OpenAPIOptions) map[string]any {
+	schemaObj := map[string]any{}
+
+	if schema.Ref != "" {
+		schemaObj["$ref"] = schema.Ref
+		return schemaObj
+	}
+
+	switch schema.Type {
+	case nil:
+	case Array:
+		// Elements are described by Items, when set
+		schemaObj["type"] = "array"
+	default:
+		schemaObj = typeSchema(schema.Type, map[reflect.Type]bool{}, opts)
+	}
+
+	if schema.Format != "" {
+		schemaObj["format"] = schema.Format
+	}
+	if len(schema.Enum) > 0 {
+		schemaObj["enum"] = schema.Enum
+	}
+	if schema.Default != nil {
+		schemaObj["default"] = schema.Default
+	}
+	if schema.Example != nil {
+		schemaObj["example"] = schema.Example
+	}
+	if len(schema.Examples) > 0 {
+		schemaObj["examples"] = schema.Examples
+	}
+	// Exclusive bounds are numbers replacing minimum and maximum in JSON Schema
+	// 2020-12, and booleans qualifying them in OpenAPI 3.0
+	if schema.Minimum != nil {
+		switch {
+		case schema.ExclusiveMinimum && opts.jsonSchema2020:
+			schemaObj["exclusiveMinimum"] = *schema.Minimum
+		case schema.ExclusiveMinimum:
+			schemaObj["minimum"] = *schema.Minimum
+			schemaObj["exclusiveMinimum"] = true
+		default:
+			schemaObj["minimum"] = *schema.Minimum
+		}
+	}
+	if schema.Maximum != nil {
+		switch {
+		case schema.ExclusiveMaximum && opts.jsonSchema2020:
+			schemaObj["exclusiveMaximum"] = *schema.Maximum
+		case schema.ExclusiveMaximum:
+			schemaObj["maximum"] = *schema.Maximum
+			schemaObj["exclusiveMaximum"] = true
+		default:
+			schemaObj["maximum"] = *schema.Maximum
+		}
+	}
+	if schema.MinLength != nil {
+		schemaObj["minLength"] = *schema.MinLength
+	}
+	if schema.MaxLength != nil {
+		schemaObj["maxLength"] = *schema.MaxLength
+	}
+	if schema.Pattern != "" {
+		schemaObj["pattern"] = schema.Pattern
+	}
+	if schema.ContentMediaType != "" {
+		schemaObj["contentMediaType"] = schema.ContentMediaType
+	}
+	if schema.ContentEncoding != "" {
+		schemaObj["contentEncoding"] = schema.ContentEncoding
+	}
+	if len(schema.PrefixItems) > 0 {
+		schemaObj["prefixItems"] = schemasToOpenAPI(schema.PrefixItems, opts)
+	}
+	if schema.Items != nil {
+		schemaObj["items"] = OpenAPISchema(*schema.Items, opts)
+	}
+	if len(schema.AllOf) > 0 {
+		schemaObj["allOf"] = schemasToOpenAPI(schema.AllOf, opts)
+	}
+	if len(schema.OneOf) > 0 {
+		schemaObj["oneOf"] = schemasToOpenAPI(schema.OneOf, opts)
+	}
+	if len(schema.AnyOf) > 0 {
+		schemaObj["anyOf"] = schemasToOpenAPI(schema.AnyOf, opts)
+	}
+	if schema.Discriminator != nil {
+		discriminator := map[string]any{
+			"propertyName": schema.Discriminator.PropertyName,
+		}
+		if len(schema.Discriminator.Mapping) > 0 {
+			discriminator["mapping"] = schema.Discriminator.Mapping
+		}
+		schemaObj["discriminator"] = discriminator
+	}
+
+	return schemaObj
+}
+
+func schemasToOpenAPI(schemas []Schema, opts OpenAPIOptions) []map[string]any {
+	schemasObj := make([]map[string]any, len(schemas))
+	for i, schema := range schemas {
+		schemasObj[i] = OpenAPISchema(schema, opts)
+	}
+	return schemasObj
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isStringInterface reports whether t is an interface whose values are
+// described as strings: errors by their message and text marshalers by their text
+func isStringInterface(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && (t.Implements(errorType) || t.Implements(textMarshalerType))
+}
+
+// typeSchema renders the Go type t. Pointers are described by their element
+// and struct types already being rendered by visiting as plain objects, so
+// that recursive types such as a Children []Node field of Node terminate.
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool, opts OpenAPIOptions) map[string]any {
+	schema := map[string]any{}
+
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		schema["type"] = "string"
+		schema["format"] = "date-time"
+		return schema
+	case t == reflect.TypeOf((*io.Reader)(nil)).Elem():
+		schema["type"] = "string"
+		schema["format"] = "binary"
+		return schema
+	case isStringInterface(t):
+		schema["type"] = "string"
+		return schema
+	case t.Kind() == reflect.Interface && opts.EmptyAnySchema:
+		// Any value is allowed, which an empty schema expresses
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = typeSchema(t.Elem(), visiting, opts)
+	case reflect.Struct:
+		schema["type"] = "object"
+		properties, required := structProperties(t, visiting, opts)
+		if len(properties) > 0 {
+			schema["properties"] = properties
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case reflect.Ptr:
+		return typeSchema(t.Elem(), visiting, opts)
+	case reflect.Map:
+		schema["type"] = "object"
+		schema["additionalProperties"] = typeSchema(t.Elem(), visiting, opts)
+	default:
+		schema["type"] = "object"
+	}
+
+	return schema
+}
+
+// structProperties renders the properties of the struct type t, and lists the
+// required ones: fields with a json tag without omitempty. Struct types
+// already being rendered by visiting get no properties.
+func structProperties(t reflect.Type, visiting map[reflect.Type]bool, opts OpenAPIOptions) (map[string]any, []string) {
+	properties := make(map[string]any)
+	var required []string
+	if visiting[t] {
+		return properties, nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	// Fields of embedded structs are promoted to t's properties
+	for _, field := range JSONFields(t) {
+		// Values of fields with the string option are encoded as JSON
+		// strings, e.g. "42" for an int
+		fieldSchema := typeSchema(field.Type, visiting, opts)
+		if HasJSONOption(field.Tag, "string") && StringEncodable(field.Type) {
+			fieldSchema = map[string]any{"type": "string"}
+		}
+		if format := field.Tag.Get("format"); format != "" {
+			fieldSchema["format"] = format
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			fieldSchema["enum"] = strings.Split(enum, ",")
+		}
+		properties[field.JSONName] = fieldSchema
+
+		if field.Tag.Get("json") != "" && !HasJSONOption(field.Tag, "omitempty") {
+			required = append(required, field.JSONName)
+		}
+	}
+
+	return properties, required
+}
@@ -0,0 +1,113 @@
+package gopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ApplicationProblemJSON is the media type of RFC 9457 problem details
+const ApplicationProblemJSON MediaType = "application/problem+json"
+
+// ErrorResponder writes the response to a request rejected with status because
+// of err. Set Spec.ErrorResponder to replace DefaultErrorResponder.
+type ErrorResponder func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+// Problem is an RFC 9457 problem details object, extended with the parameters
+// that caused a validation failure
+type Problem struct {
+	Type   string       `json:"type,omitempty"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one missing or invalid parameter of a rejected request
+type FieldError struct {
+	In     In     `json:"in"`
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+}
+
+// ParameterError reports a parameter that is required but absent, or whose
+// value fails validation with Err
+type ParameterError struct {
+	In      In
+	Name    string
+	Missing bool
+	Err     error
+}
+
+func (e *ParameterError) Error() string {
+	if e.Missing {
+		switch e.In {
+		case InHeader, InCookie:
+			return fmt.Sprintf("gopenapi: missing required %s %s", e.In, e.Name)
+		default:
+			return fmt.Sprintf("gopenapi: missing required %s parameter %s", e.In, e.Name)
+		}
+	}
+	return fmt.Sprintf("%s parameter validation failed for '%s': %v", e.In, e.Name, e.Err)
+}
+
+func (e *ParameterError) Unwrap() error {
+	return e.Err
+}
+
+// BodyTooLargeError reports a request body longer than the limit set by
+// Spec.MaxRequestBodySize or WithMaxBodyBytes. Handlers answer it with 413
+// Request Entity Too Large.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("gopenapi: request body exceeds %d bytes", e.Limit)
+}
+
+// DefaultErrorResponder writes err as application/problem+json, listing each
+// *ParameterError joined in err as a field error
+func DefaultErrorResponder(w http.ResponseWriter, r *http.Request, status int, err error) {
+	problem := Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+		Errors: fieldErrors(err),
+	}
+	w.Header().Set("Content-Type", string(ApplicationProblemJSON))
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// WriteError answers r with status because of err, using the ErrorResponder of
+// the request's spec or DefaultErrorResponder. Handlers use it in place of
+// http.Error to reject requests in the same format as the built-in validation.
+func WriteError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	respond := DefaultErrorResponder
+	if spec, ok := SpecFromRequest(r); ok && spec.ErrorResponder != nil {
+		respond = spec.ErrorResponder
+	}
+	respond(w, r, status, err)
+}
+
+// fieldErrors collects the parameter errors of err, following errors joined
+// with errors.Join
+func fieldErrors(err error) []FieldError {
+	var fields []FieldError
+	switch err := err.(type) {
+	case *ParameterError:
+		detail := "missing required parameter"
+		if err.Err != nil {
+			detail = err.Err.Error()
+		}
+		fields = append(fields, FieldError{In: err.In, Name: err.Name, Detail: detail})
+	case interface{ Unwrap() []error }:
+		for _, err := range err.Unwrap() {
+			fields = append(fields, fieldErrors(err)...)
+		}
+	case interface{ Unwrap() error }:
+		fields = fieldErrors(err.Unwrap())
+	}
+	return fields
+}
@@ -0,0 +1,52 @@
+package gopenapi
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// recoverPanics wraps the handler of operation to answer requests whose handler
+// panics with a 500 written by WriteError, after logging the panic and its stack.
+// http.ErrAbortHandler is re-panicked so that net/http aborts the response.
+func recoverPanics(operation *Operation, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer := &panicResponseWriter{ResponseWriter: w}
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			slog.Default().ErrorContext(r.Context(), "gopenapi: handler panicked", "operationId", operation.OperationId, "panic", recovered, "stack", string(debug.Stack()))
+			if !writer.written {
+				WriteError(w, r, http.StatusInternalServerError, fmt.Errorf("gopenapi: operation %s failed", operation.OperationId))
+			}
+		}()
+		next.ServeHTTP(writer, r)
+	})
+}
+
+// panicResponseWriter records whether a response has been started, since the
+// status of a started response can no longer be replaced by a 500
+type panicResponseWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *panicResponseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *panicResponseWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *panicResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,118 @@
+package gopenapi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WriteStream copies body to w with the given status as it is read, flushing
+// after every write so that clients receive long-lived or large bodies, such as
+// logs, without the whole body being buffered. Responses without a
+// Content-Length are sent chunked. The Content-Type defaults to
+// application/octet-stream when the handler has not set one.
+func WriteStream(w http.ResponseWriter, status int, body io.Reader) error {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+	w.WriteHeader(status)
+
+	// The headers are sent before the first read, which may block
+	controller := http.NewResponseController(w)
+	if err := flush(controller); err != nil {
+		return err
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			if err := flush(controller); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ServerSentEvent is an event written by WriteSSE. Data may span several
+// lines; ID, Event and Retry are omitted when empty.
+type ServerSentEvent struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry tells the client how long to wait before reconnecting
+	Retry time.Duration
+}
+
+// WriteSSE answers r with a text/event-stream response and writes each event
+// received from events as it arrives, flushing after each one. It returns when
+// events is closed, or with the context's error once the client disconnects.
+func WriteSSE(w http.ResponseWriter, r *http.Request, events <-chan ServerSentEvent) error {
+	header := w.Header()
+	header.Set("Content-Type", string(TextEventStream))
+	header.Set("Cache-Control", "no-cache")
+	// Stops proxies such as nginx from buffering the stream
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if err := flush(controller); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return r.Context().Err()
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if _, err := io.WriteString(w, formatEvent(event)); err != nil {
+				return err
+			}
+			if err := flush(controller); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// formatEvent renders event in the text/event-stream format, ending with the
+// blank line that dispatches it
+func formatEvent(event ServerSentEvent) string {
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Event)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// flush sends buffered data to the client. Writers that do not support
+// flushing are written to without it.
+func flush(controller *http.ResponseController) error {
+	if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
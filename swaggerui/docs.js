@@ -0,0 +1,13 @@
+// Starts Swagger UI for the document named by the data-spec-url attribute of
+// this script. It is served as a file rather than inlined in the docs page so
+// the page runs under a script-src 'self' Content-Security-Policy.
+(function () {
+  var specURL = document.currentScript.dataset.specUrl;
+  window.addEventListener("load", function () {
+    window.ui = SwaggerUIBundle({
+      url: specURL,
+      dom_id: "#swagger-ui",
+      deepLinking: true,
+    });
+  });
+})();
@@ -0,0 +1,16 @@
+#!/bin/bash
+# Vendors the pinned swagger-ui-dist release into swaggerui/, where it is
+# embedded in the package and served under Spec.DocsPath. Run through
+# `go generate` after changing the version, which must match
+# DefaultDocsAssetsURL in docs.go.
+
+set -euo pipefail
+
+VERSION="5.17.14"
+BASE_URL="https://cdn.jsdelivr.net/npm/swagger-ui-dist@${VERSION}"
+
+cd "$(dirname "$0")/swaggerui"
+for file in swagger-ui-bundle.js swagger-ui.css LICENSE; do
+    echo "Fetching swagger-ui-dist@${VERSION}/${file}"
+    curl -fsSL -o "${file}" "${BASE_URL}/${file}"
+done
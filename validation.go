@@ -1,11 +1,22 @@
 package gopenapi
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
 	"reflect"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 )
 
 type ValidationMiddleware interface {
@@ -19,12 +30,49 @@ type ValidationMiddleware interface {
 	ValidateRequest(operation *Operation, r *http.Request) (any, error)
 }
 
+// QueryValuesValidator is implemented by validation middlewares that validate
+// all values of a repeated query parameter at once, as needed for array
+// parameters. DefaultValidationMiddleware implements it.
+type QueryValuesValidator interface {
+	ValidateQueryValues(operation *Operation, name string, values []string) (any, error)
+}
+
+// DefaultMaxRequestBodySize is the request body limit in bytes applied when
+// Spec.MaxRequestBodySize is nil
+const DefaultMaxRequestBodySize int64 = 1 << 20
+
+// maxRequestBodySize returns the request body limit of spec in bytes, 0 for none
+func (s *Spec) maxRequestBodySize() int64 {
+	if s.MaxRequestBodySize == nil {
+		return DefaultMaxRequestBodySize
+	}
+	return *s.MaxRequestBodySize
+}
+
 type DefaultValidationMiddleware struct {
 }
 
 func (v *DefaultValidationMiddleware) Apply(spec *Spec, operation *Operation) (MiddlewareHandler, error) {
 	return func(next http.Handler) http.Handler {
-		return next
+		if !spec.ValidateRequests {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := v.ValidateRequest(operation, r); err != nil {
+				WriteError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			if err := v.validateRequestBody(operation, r); err != nil {
+				status := http.StatusBadRequest
+				var tooLarge *BodyTooLargeError
+				if errors.As(err, &tooLarge) {
+					status = http.StatusRequestEntityTooLarge
+				}
+				WriteError(w, r, status, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
 	}, nil
 }
 
@@ -43,8 +91,15 @@ func (v *DefaultValidationMiddleware) ValidatePathValue(operation *Operation, na
 func (v *DefaultValidationMiddleware) ValidateBody(operation *Operation, request *http.Request) (any, error) {
 	body, err := io.ReadAll(request.Body)
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, &BodyTooLargeError{Limit: maxBytesErr.Limit}
+		}
 		return nil, err
 	}
+	if len(body) == 0 && operation.RequestBody.Required {
+		return nil, errMissingRequestBody
+	}
 	contentType := request.Header.Get("Content-Type")
 	if contentType == "" {
 		if operation.RequestBody.Content != nil {
@@ -55,18 +110,118 @@ func (v *DefaultValidationMiddleware) ValidateBody(operation *Operation, request
 		}
 		return string(body), nil
 	}
-	content, ok := operation.RequestBody.Content[MediaType(contentType)]
+	mediaType, ok := requestMediaType(operation, contentType)
 	if !ok {
 		return nil, fmt.Errorf("gopenapi: missing schema for content type %s", contentType)
 	}
 
-	return content.Schema.Validate(string(body))
+	schema := operation.RequestBody.Content[mediaType].Schema
+	value, err := schema.Validate(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if isJSONMediaType(string(mediaType)) && isCompositeType(schema.Type) {
+		var decoded any
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			return nil, err
+		}
+		rejectUnknown := false
+		if spec, ok := specFromContext(request.Context()); ok {
+			rejectUnknown = spec.RejectUnknownFields
+		}
+		if err := validateTypeValue(schema.Type, "$", decoded, rejectUnknown); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// isCompositeType reports whether t is decoded from a JSON object or array
+func isCompositeType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	case reflect.Ptr:
+		return isCompositeType(t.Elem())
+	}
+	return false
+}
+
+// errMissingRequestBody rejects requests without a body to operations whose
+// RequestBody is Required
+var errMissingRequestBody = errors.New("gopenapi: missing required request body")
+
+// validateRequestBody validates the body of r with ValidateBody, leaving the
+// body to be read again by the handler. Empty bodies are only rejected when the
+// request body is Required.
+func (v *DefaultValidationMiddleware) validateRequestBody(operation *Operation, r *http.Request) error {
+	if operation.RequestBody.Content == nil {
+		return nil
+	}
+	if r.Body == nil || r.Body == http.NoBody {
+		if operation.RequestBody.Required {
+			return errMissingRequestBody
+		}
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &BodyTooLargeError{Limit: maxBytesErr.Limit}
+		}
+		return err
+	}
+	defer func() {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}()
+	if len(body) == 0 {
+		if operation.RequestBody.Required {
+			return errMissingRequestBody
+		}
+		return nil
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	_, err = v.ValidateBody(operation, r)
+	return err
+}
+
+// requestMediaType returns the media type declared by the request body of
+// operation that matches a Content-Type header value. Parameters such as
+// charset=utf-8 are ignored and media types compare case-insensitively.
+func requestMediaType(operation *Operation, contentType string) (MediaType, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	if _, ok := operation.RequestBody.Content[MediaType(mediaType)]; ok {
+		return MediaType(mediaType), true
+	}
+	for declared := range operation.RequestBody.Content {
+		if strings.EqualFold(string(declared), mediaType) {
+			return declared, true
+		}
+	}
+	return "", false
 }
 
 func (v *DefaultValidationMiddleware) ValidateQueryValue(operation *Operation, name string, value string) (any, error) {
 	return validate(operation.Parameters.Group().Query, name, value)
 }
 
+func (v *DefaultValidationMiddleware) ValidateQueryValues(operation *Operation, name string, values []string) (any, error) {
+	schema, ok := operation.Parameters.Group().Query[name]
+	if !ok {
+		return nil, fmt.Errorf("gopenapi: missing query parameter %s", name)
+	}
+	return schema.ValidateValues(values)
+}
+
 func (v *DefaultValidationMiddleware) ValidateHeaderValue(operation *Operation, name string, value string) (any, error) {
 	return validate(operation.Parameters.Group().Header, name, value)
 }
@@ -92,42 +247,88 @@ func (v *DefaultValidationMiddleware) ValidateFormValue(operation *Operation, na
 	return nil, fmt.Errorf("gopenapi: form field %s schema not found or complex form validation not implemented", name)
 }
 
-func (v *DefaultValidationMiddleware) ValidateRequest(operation *Operation, r *http.Request) (any, error) {
-	groupedParams := operation.Parameters.Group()
-	if groupedParams.Query != nil {
-		for name := range groupedParams.Query {
-			queryValue := r.URL.Query().Get(name)
-			_, err := v.ValidateQueryValue(operation, name, queryValue)
-			if err != nil {
-				return nil, fmt.Errorf("query parameter validation failed for '%s': %w", name, err)
+// applyParameterDefaults populates the absent optional query and header
+// parameters of r whose schema declares a default with that default, so that
+// validation and handlers observe it as if the client had sent it
+func applyParameterDefaults(operation *Operation, r *http.Request) {
+	var query url.Values
+	for _, param := range operation.Parameters {
+		if param.Required || param.Schema.Default == nil {
+			continue
+		}
+		switch param.In {
+		case InQuery:
+			if query == nil {
+				query = r.URL.Query()
+			}
+			if _, ok := query[param.Name]; !ok {
+				query[param.Name] = defaultValues(param.Schema.Default)
+				r.URL.RawQuery = query.Encode()
+			}
+		case InHeader:
+			if len(r.Header.Values(param.Name)) == 0 {
+				for _, value := range defaultValues(param.Schema.Default) {
+					r.Header.Add(param.Name, value)
+				}
 			}
 		}
 	}
+}
 
-	if groupedParams.Header != nil {
-		for name := range groupedParams.Header {
-			headerValue := r.Header.Get(name)
-			_, err := v.ValidateHeaderValue(operation, name, headerValue)
-			if err != nil {
-				return nil, fmt.Errorf("header parameter validation failed for '%s': %w", name, err)
-			}
-		}
+// defaultValues formats a schema default as parameter values, one per element
+// for array defaults
+func defaultValues(value any) []string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []string{fmt.Sprint(value)}
 	}
+	values := make([]string, v.Len())
+	for i := range values {
+		values[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return values
+}
 
-	if groupedParams.Cookie != nil {
-		for name := range groupedParams.Cookie {
-			cookie, err := r.Cookie(name)
-			cookieValue := ""
-			if err == nil {
-				cookieValue = cookie.Value
-			} else if err != http.ErrNoCookie {
-				return nil, fmt.Errorf("could not retrieve cookie '%s': %w", name, err)
+func (v *DefaultValidationMiddleware) ValidateRequest(operation *Operation, r *http.Request) (any, error) {
+	applyParameterDefaults(operation, r)
+
+	// Every parameter is checked, in declared order, so that all problems are
+	// reported at once. Absent parameters fail when required and are otherwise
+	// not validated.
+	var errs []error
+	query := r.URL.Query()
+	for _, param := range operation.Parameters {
+		var err error
+		present := true
+		switch param.In {
+		case InPath:
+			_, err = v.ValidatePathValue(operation, param.Name, r.PathValue(param.Name))
+		case InQuery:
+			var values []string
+			values, present = query[param.Name]
+			if present {
+				_, err = v.ValidateQueryValues(operation, param.Name, values)
+			}
+		case InHeader:
+			present = len(r.Header.Values(param.Name)) > 0
+			if present {
+				_, err = v.ValidateHeaderValue(operation, param.Name, r.Header.Get(param.Name))
 			}
-			_, errVal := v.ValidateCookieValue(operation, name, cookieValue)
-			if errVal != nil {
-				return nil, fmt.Errorf("cookie parameter validation failed for '%s': %w", name, errVal)
+		case InCookie:
+			cookie, cookieErr := r.Cookie(param.Name)
+			present = cookieErr == nil
+			if present {
+				_, err = v.ValidateCookieValue(operation, param.Name, cookie.Value)
 			}
 		}
+		if !present && param.Required {
+			errs = append(errs, &ParameterError{In: param.In, Name: param.Name, Missing: true})
+		} else if err != nil {
+			errs = append(errs, &ParameterError{In: param.In, Name: param.Name, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
 	if operation.RequestBody.Content != nil {
@@ -162,14 +363,146 @@ func ValidateRequestPathValue[T any](r *http.Request, name string, into *T) erro
 	if err != nil {
 		return err
 	}
-	value, ok := maybeValue.(T)
+	if value, ok := maybeValue.(T); ok {
+		*into = value
+		return nil
+	}
+	return setValidatedValue(reflect.ValueOf(into).Elem(), maybeValue)
+}
+
+// ValidateRequestQueryValue validates the query parameter name of the request
+// and stores it in into. Array parameters are validated element by element
+// against their Items schema, so into may be a typed slice such as *[]int.
+func ValidateRequestQueryValue[T any](r *http.Request, name string, into *T) error {
+	spec, ok := SpecFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no spec for request")
+	}
+	operation, ok := OperationFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no operation for request")
+	}
+	applyParameterDefaults(operation, r)
+	var maybeValue any
+	var err error
+	if validator, ok := spec.ValidationMiddleware.(QueryValuesValidator); ok {
+		maybeValue, err = validator.ValidateQueryValues(operation, name, r.URL.Query()[name])
+	} else {
+		maybeValue, err = spec.ValidationMiddleware.ValidateQueryValue(operation, name, r.URL.Query().Get(name))
+	}
+	if err != nil {
+		return err
+	}
+	if value, ok := maybeValue.(T); ok {
+		*into = value
+		return nil
+	}
+	return setValidatedValue(reflect.ValueOf(into).Elem(), maybeValue)
+}
+
+// setValidatedValue stores a validated parameter value in target, converting it
+// to named types of the same kind and validated array items to the element type
+// of a typed slice. Pointer targets are set to a new value.
+func setValidatedValue(target reflect.Value, value any) error {
+	if target.Kind() == reflect.Pointer && reflect.TypeOf(value) != target.Type() {
+		elem := reflect.New(target.Type().Elem())
+		if err := setValidatedValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	}
+	if v := reflect.ValueOf(value); v.IsValid() && v.Kind() == target.Kind() && v.Type().ConvertibleTo(target.Type()) {
+		// Covers named types such as type Order string
+		target.Set(v.Convert(target.Type()))
+		return nil
+	}
+
+	items, isItems := value.([]any)
+	if !isItems || target.Kind() != reflect.Slice {
+		return fmt.Errorf("gopenapi: invalid validated value type expected %s, got %T", target.Type(), value)
+	}
+	slice := reflect.MakeSlice(target.Type(), len(items), len(items))
+	for i, item := range items {
+		itemValue := reflect.ValueOf(item)
+		if !itemValue.IsValid() || !itemValue.Type().ConvertibleTo(target.Type().Elem()) {
+			return fmt.Errorf("gopenapi: invalid validated value type expected %s, got %T at index %d", target.Type(), item, i)
+		}
+		slice.Index(i).Set(itemValue.Convert(target.Type().Elem()))
+	}
+	target.Set(slice)
+	return nil
+}
+
+// ValidateRequestQueryValues validates the query parameters of the request
+// named by the json tags of the fields of into, a struct, and stores them in
+// those fields. Parameters absent from the request take their schema default,
+// if any, and otherwise leave their field unset, unless the operation declares
+// them required.
+func ValidateRequestQueryValues[T any](r *http.Request, into *T) error {
+	valueType := reflect.TypeOf(*into)
+	valuesValue := reflect.ValueOf(into).Elem()
+	if valueType.Kind() != reflect.Struct {
+		return fmt.Errorf("gopenapi: invalid validated query value type %T", into)
+	}
+	spec, ok := SpecFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no spec for request")
+	}
+	operation, ok := OperationFromRequest(r)
 	if !ok {
-		return fmt.Errorf("gopenapi: invalid validated path value type expected %T, got %T", into, maybeValue)
+		return fmt.Errorf("gopenapi: no operation for request")
+	}
+
+	applyParameterDefaults(operation, r)
+	required := map[string]bool{}
+	for _, param := range operation.Parameters {
+		if param.In == InQuery {
+			required[param.Name] = param.Required
+		}
+	}
+	query := r.URL.Query()
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if fieldName == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
+		values, present := query[fieldName]
+		if !present {
+			if required[fieldName] {
+				return &ParameterError{In: InQuery, Name: fieldName, Missing: true}
+			}
+			continue
+		}
+
+		var anyValue any
+		var err error
+		if validator, ok := spec.ValidationMiddleware.(QueryValuesValidator); ok {
+			anyValue, err = validator.ValidateQueryValues(operation, fieldName, values)
+		} else {
+			anyValue, err = spec.ValidationMiddleware.ValidateQueryValue(operation, fieldName, values[0])
+		}
+		if err != nil {
+			return &ParameterError{In: InQuery, Name: fieldName, Err: err}
+		}
+		if err := setValidatedValue(valuesValue.Field(i), anyValue); err != nil {
+			return &ParameterError{In: InQuery, Name: fieldName, Err: err}
+		}
 	}
-	*into = value
 	return nil
 }
 
+// ValidateRequestPathValues validates the path parameters of the request named
+// by the json tags of the fields of into, a struct, and stores them in those
+// fields. Fields may be of named types such as type ID int, or pointers.
 func ValidateRequestPathValues[T any](r *http.Request, into *T) error {
 	valueType := reflect.TypeOf(*into)
 	valuesValue := reflect.ValueOf(into).Elem()
@@ -186,23 +519,151 @@ func ValidateRequestPathValues[T any](r *http.Request, into *T) error {
 	}
 	for i := range valueType.NumField() {
 		field := valueType.Field(i)
-		tag := strings.Split(field.Tag.Get("json"), ",")
-		fieldName := field.Name
-		if len(tag) > 0 && tag[0] != "-" {
-			fieldName = tag[0]
+		if !field.IsExported() {
+			continue
+		}
+		fieldName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if fieldName == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
 		}
 		anyValue, err := spec.ValidationMiddleware.ValidatePathValue(operation, fieldName, r.PathValue(fieldName))
 		if err != nil {
-			return err
+			return &ParameterError{In: InPath, Name: fieldName, Err: err}
+		}
+		if err := setValidatedValue(valuesValue.Field(i), anyValue); err != nil {
+			return &ParameterError{In: InPath, Name: fieldName, Err: err}
+		}
+	}
+	return nil
+}
+
+// ValidateRequestHeaderValues validates the request headers named by the
+// header tags of the fields of into, a struct, falling back to their json tags,
+// and stores them in those fields. Names are matched case-insensitively against
+// the request and the operation's header parameters. Headers absent from the
+// request take their schema default, if any, and otherwise leave their field
+// unset, unless the operation declares them required.
+func ValidateRequestHeaderValues[T any](r *http.Request, into *T) error {
+	valueType := reflect.TypeOf(*into)
+	valuesValue := reflect.ValueOf(into).Elem()
+	if valueType.Kind() != reflect.Struct {
+		return fmt.Errorf("gopenapi: invalid validated header value type %T", into)
+	}
+	spec, ok := SpecFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no spec for request")
+	}
+	operation, ok := OperationFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no operation for request")
+	}
+
+	applyParameterDefaults(operation, r)
+	// Declared header parameters by canonical name
+	params := map[string]Parameter{}
+	for _, param := range operation.Parameters {
+		if param.In == InHeader {
+			params[http.CanonicalHeaderKey(param.Name)] = param
+		}
+	}
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldName := field.Tag.Get("header")
+		if fieldName == "" {
+			fieldName, _, _ = strings.Cut(field.Tag.Get("json"), ",")
+		}
+		if fieldName == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+		param, declared := params[http.CanonicalHeaderKey(fieldName)]
+		if declared {
+			fieldName = param.Name
+		}
+
+		values := r.Header.Values(fieldName)
+		if len(values) == 0 {
+			if param.Required {
+				return &ParameterError{In: InHeader, Name: fieldName, Missing: true}
+			}
+			continue
+		}
+		anyValue, err := spec.ValidationMiddleware.ValidateHeaderValue(operation, fieldName, values[0])
+		if err != nil {
+			return &ParameterError{In: InHeader, Name: fieldName, Err: err}
+		}
+		if err := setValidatedValue(valuesValue.Field(i), anyValue); err != nil {
+			return &ParameterError{In: InHeader, Name: fieldName, Err: err}
+		}
+	}
+	return nil
+}
+
+// ValidateRequestCookieValues validates the request cookies named by the
+// cookie tags of the fields of into, a struct, falling back to their json tags,
+// and stores them in those fields. Cookies absent from the request leave their
+// field unset, unless the operation declares them required.
+func ValidateRequestCookieValues[T any](r *http.Request, into *T) error {
+	valueType := reflect.TypeOf(*into)
+	valuesValue := reflect.ValueOf(into).Elem()
+	if valueType.Kind() != reflect.Struct {
+		return fmt.Errorf("gopenapi: invalid validated cookie value type %T", into)
+	}
+	spec, ok := SpecFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no spec for request")
+	}
+	operation, ok := OperationFromRequest(r)
+	if !ok {
+		return fmt.Errorf("gopenapi: no operation for request")
+	}
+
+	required := map[string]bool{}
+	for _, param := range operation.Parameters {
+		if param.In == InCookie {
+			required[param.Name] = param.Required
+		}
+	}
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
 		}
-		canSet := valuesValue.Field(i).CanSet()
-		if !canSet {
-			return fmt.Errorf("gopenapi: field %s is not settable", fieldName)
+		fieldName := field.Tag.Get("cookie")
+		if fieldName == "" {
+			fieldName, _, _ = strings.Cut(field.Tag.Get("json"), ",")
 		}
-		if !field.Type.AssignableTo(reflect.TypeOf(anyValue)) {
-			return fmt.Errorf("gopenapi: field %s is not assignable to %T", fieldName, anyValue)
+		if fieldName == "-" {
+			continue
+		}
+		if fieldName == "" {
+			fieldName = field.Name
+		}
+
+		cookie, err := r.Cookie(fieldName)
+		if err == http.ErrNoCookie {
+			if required[fieldName] {
+				return &ParameterError{In: InCookie, Name: fieldName, Missing: true}
+			}
+			continue
+		} else if err != nil {
+			return fmt.Errorf("could not retrieve cookie '%s': %w", fieldName, err)
+		}
+		anyValue, err := spec.ValidationMiddleware.ValidateCookieValue(operation, fieldName, cookie.Value)
+		if err != nil {
+			return &ParameterError{In: InCookie, Name: fieldName, Err: err}
+		}
+		if err := setValidatedValue(valuesValue.Field(i), anyValue); err != nil {
+			return &ParameterError{In: InCookie, Name: fieldName, Err: err}
 		}
-		valuesValue.Field(i).Set(reflect.ValueOf(anyValue))
 	}
 	return nil
 }
@@ -222,8 +683,459 @@ func ValidateRequestBody[T any](r *http.Request, into *T) error {
 	}
 	value, ok := maybeValue.(*T)
 	if !ok {
+		// Composed schemas without a Type validate to decoded JSON, such as a
+		// map[string]any, which is converted to T
+		if maybeValue != nil && reflect.TypeOf(maybeValue).Kind() != reflect.Ptr {
+			data, err := json.Marshal(maybeValue)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(data, into)
+		}
 		return fmt.Errorf("gopenapi: invalid validated body type expected %T, got %T", into, maybeValue)
 	}
 	*into = *value
 	return nil
 }
+
+// DecodeBody validates the body of r like ValidateRequestBody and returns it
+// as a T, e.g. user, err := gopenapi.DecodeBody[User](r)
+func DecodeBody[T any](r *http.Request) (T, error) {
+	var value T
+	if err := ValidateRequestBody(r, &value); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// emailPattern is a pragmatic check of the local@domain.tld shape of an email
+// address rather than a full RFC 5322 parser
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// formatValidators check string values of the formats they are registered for
+var formatValidators = map[string]func(value string) error{
+	"email": func(value string) error {
+		if !emailPattern.MatchString(value) {
+			return fmt.Errorf("expected local@domain")
+		}
+		return nil
+	},
+	"uuid": parseUUID,
+	"date-time": func(value string) error {
+		_, err := time.Parse(time.RFC3339, value)
+		return err
+	},
+	"date": func(value string) error {
+		_, err := time.Parse(time.DateOnly, value)
+		return err
+	},
+	"uri": func(value string) error {
+		u, err := url.Parse(value)
+		if err != nil {
+			return err
+		}
+		if !u.IsAbs() {
+			return fmt.Errorf("expected an absolute URI")
+		}
+		return nil
+	},
+}
+
+// parseUUID checks that value is a UUID in its canonical 8-4-4-4-12 hex form
+func parseUUID(value string) error {
+	if len(value) != 36 {
+		return fmt.Errorf("expected 36 characters, got %d", len(value))
+	}
+	for i, c := range value {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return fmt.Errorf("expected '-' at position %d", i)
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+				return fmt.Errorf("invalid hex character %q at position %d", c, i)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSchemaValue checks a decoded JSON value against the schema, using path
+// to describe where in the document a mismatch was found
+func validateSchemaValue(schema Schema, path string, value any) error {
+	if schema.Type != nil && value != nil {
+		if err := validateJSONKind(schema.Type, value); err != nil {
+			return fmt.Errorf("gopenapi: %s: %w", path, err)
+		}
+	}
+	switch value := value.(type) {
+	case float64:
+		if err := schema.validateRange(value); err != nil {
+			return fmt.Errorf("gopenapi: %s: %w", path, err)
+		}
+	case string:
+		if err := schema.validateString(value); err != nil {
+			return fmt.Errorf("gopenapi: %s: %w", path, err)
+		}
+	}
+
+	if schema.Items != nil {
+		if items, ok := value.([]any); ok {
+			for i, item := range items {
+				if err := validateSchemaValue(*schema.Items, fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(schema.PrefixItems) > 0 {
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("gopenapi: %s: expected array, got %s", path, jsonKindName(value))
+		}
+		for i, itemSchema := range schema.PrefixItems {
+			if i >= len(items) {
+				break
+			}
+			if err := validateSchemaValue(itemSchema, fmt.Sprintf("%s[%d]", path, i), items[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return validateComposition(schema, path, value)
+}
+
+// validateComposition checks a decoded JSON value against the AllOf, OneOf and
+// AnyOf members of the schema. Members are matched with validateMember, so a
+// struct member requires its required properties.
+func validateComposition(schema Schema, path string, value any) error {
+	for i, member := range schema.AllOf {
+		if err := validateMember(member, path, value); err != nil {
+			return fmt.Errorf("gopenapi: allOf member %d: %w", i, err)
+		}
+	}
+	if schema.Discriminator != nil && (len(schema.OneOf) > 0 || len(schema.AnyOf) > 0) {
+		member, err := discriminatedMember(schema, path, value)
+		if err != nil {
+			return err
+		}
+		return validateMember(member, path, value)
+	}
+	if len(schema.OneOf) > 0 {
+		matches := 0
+		for _, member := range schema.OneOf {
+			if validateMember(member, path, value) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("gopenapi: %s: value matches %d of the oneOf schemas, expected exactly one", path, matches)
+		}
+	}
+	if len(schema.AnyOf) > 0 && !slices.ContainsFunc(schema.AnyOf, func(member Schema) bool {
+		return validateMember(member, path, value) == nil
+	}) {
+		return fmt.Errorf("gopenapi: %s: value matches none of the anyOf schemas", path)
+	}
+	return nil
+}
+
+// discriminatedMember returns the OneOf or AnyOf member of schema selected by
+// the discriminator property of a decoded JSON object
+func discriminatedMember(schema Schema, path string, value any) (Schema, error) {
+	name := schema.Discriminator.PropertyName
+	object, ok := value.(map[string]any)
+	if !ok {
+		return Schema{}, fmt.Errorf("gopenapi: %s: expected object, got %s", path, jsonKindName(value))
+	}
+	property, ok := object[name].(string)
+	if !ok {
+		return Schema{}, fmt.Errorf("gopenapi: %s: missing discriminator property %q", path, name)
+	}
+
+	ref, mapped := schema.Discriminator.Mapping[property]
+	for _, member := range append(slices.Clone(schema.OneOf), schema.AnyOf...) {
+		if member.Ref == "" {
+			continue
+		}
+		// Mapping values are either references or component schema names
+		if mapped && (member.Ref == ref || member.Ref == "#/components/schemas/"+ref) {
+			return member, nil
+		}
+		if !mapped && strings.HasSuffix(member.Ref, "/"+property) {
+			return member, nil
+		}
+	}
+	return Schema{}, fmt.Errorf("gopenapi: %s: unknown %s %q", path, name, property)
+}
+
+// validateMember checks a decoded JSON value against a composed schema member,
+// including the required properties and field types of its Go type
+func validateMember(member Schema, path string, value any) error {
+	if member.Ref != "" && member.Type == nil && !member.composed() {
+		return fmt.Errorf("gopenapi: unresolved schema reference %s", member.Ref)
+	}
+	if err := validateSchemaValue(member, path, value); err != nil {
+		return err
+	}
+	if member.Type != nil && value != nil {
+		return validateTypeValue(member.Type, path, value, false)
+	}
+	return nil
+}
+
+// validateJSONKind reports whether a decoded JSON value has the shape expected
+// for t. Types with custom JSON or text marshaling, such as time.Time, may
+// take any shape and are not checked.
+func validateJSONKind(t reflect.Type, value any) error {
+	if customMarshaling(t) {
+		return nil
+	}
+	expected := ""
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			expected = "string"
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			expected = "integer"
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			expected = "number"
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			expected = "boolean"
+		}
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]any); !ok {
+			expected = "array"
+		}
+	case reflect.Struct, reflect.Map:
+		if _, ok := value.(map[string]any); !ok {
+			expected = "object"
+		}
+	case reflect.Ptr:
+		return validateJSONKind(t.Elem(), value)
+	}
+
+	if expected != "" {
+		return fmt.Errorf("expected %s, got %s", expected, jsonKindName(value))
+	}
+	return nil
+}
+
+// jsonKindName names the JSON type of a decoded value for error messages
+func jsonKindName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// ValidateResponse checks a JSON response body against the schema declared for
+// status by the operation. Responses without a JSON schema are not checked.
+func (o *Operation) ValidateResponse(status int, body []byte) error {
+	response, ok := o.Responses[status]
+	if !ok {
+		return fmt.Errorf("gopenapi: no response declared for status %d", status)
+	}
+	content, ok := response.Content[ApplicationJSON]
+	if !ok || (content.Schema.Type == nil && !content.Schema.composed()) {
+		return nil
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Errorf("gopenapi: invalid JSON response: %w", err)
+	}
+	if err := validateSchemaValue(content.Schema, "$", decoded); err != nil {
+		return err
+	}
+	if content.Schema.Type == nil {
+		return nil
+	}
+	return validateTypeValue(content.Schema.Type, "$", decoded, false)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// customMarshaling reports whether values of t, or pointers to them, marshal
+// themselves as JSON or text
+func customMarshaling(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PointerTo(t).Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// validateTypeValue checks a decoded JSON value against the Go type t: its JSON
+// kind, the presence of required struct fields (tagged without omitempty, as in
+// the emitted schema), and recursively the fields, elements and map values.
+// With rejectUnknown, objects may not have properties other than the fields of
+// their struct type. Types with custom JSON or text marshaling, such as
+// time.Time, are not checked.
+func validateTypeValue(t reflect.Type, path string, value any, rejectUnknown bool) error {
+	if customMarshaling(t) {
+		return nil
+	}
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			return nil
+		}
+	}
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	if err := validateJSONKind(t, value); err != nil {
+		return fmt.Errorf("gopenapi: %s: %w", path, err)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return validateTypeValue(t.Elem(), path, value, rejectUnknown)
+	case reflect.Slice, reflect.Array:
+		for i, item := range value.([]any) {
+			if err := validateTypeValue(t.Elem(), fmt.Sprintf("%s[%d]", path, i), item, rejectUnknown); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		object := value.(map[string]any)
+		for key, item := range object {
+			if err := validateTypeValue(t.Elem(), path+"."+key, item, rejectUnknown); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		object := value.(map[string]any)
+		// Fields of embedded structs are promoted, as encoding/json decodes them
+		fields := JSONFields(t)
+		known := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			name := field.JSONName
+			known[name] = true
+
+			fieldValue, present := object[name]
+			if !present {
+				if field.Tag.Get("json") != "" && !HasJSONOption(field.Tag, "omitempty") {
+					return fmt.Errorf("gopenapi: %s: missing required property %q", path, name)
+				}
+				continue
+			}
+			if HasJSONOption(field.Tag, "string") && StringEncodable(field.Type) {
+				unquoted, err := unquoteJSONValue(fieldValue)
+				if err != nil {
+					return fmt.Errorf("gopenapi: %s: %w", path+"."+name, err)
+				}
+				fieldValue = unquoted
+			}
+			if err := validateTypeValue(field.Type, path+"."+name, fieldValue, rejectUnknown); err != nil {
+				return err
+			}
+		}
+		if rejectUnknown {
+			names := make([]string, 0, len(object))
+			for name := range object {
+				if !known[name] {
+					names = append(names, name)
+				}
+			}
+			if len(names) > 0 {
+				slices.Sort(names)
+				return fmt.Errorf("gopenapi: %s: unknown property %q", path, names[0])
+			}
+		}
+	}
+	return nil
+}
+
+// unquoteJSONValue decodes the value of a field tagged with the string option,
+// which encoding/json writes as a JSON string holding the encoded value, e.g.
+// "42" for an int. null is left as is.
+func unquoteJSONValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	quoted, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %s", jsonKindName(value))
+	}
+	var unquoted any
+	if err := json.Unmarshal([]byte(quoted), &unquoted); err != nil {
+		return nil, fmt.Errorf("invalid value %q in string: %w", quoted, err)
+	}
+	return unquoted, nil
+}
+
+// responseValidator is the ResponseWriter handed to operation handlers when
+// Spec.ValidateResponses is set, so that WriteResponse can find the operation
+type responseValidator struct {
+	http.ResponseWriter
+	operation *Operation
+	request   *http.Request
+}
+
+func (v *responseValidator) Unwrap() http.ResponseWriter {
+	return v.ResponseWriter
+}
+
+// writeResponse marshals body and writes it if it matches the response schema
+// for status, otherwise rejects it. Bodies are validated as JSON whichever
+// encoder is negotiated for the response.
+func (v *responseValidator) writeResponse(status int, body any) {
+	encoded, err := json.Marshal(body)
+	if err == nil {
+		err = v.operation.ValidateResponse(status, encoded)
+	}
+	if err != nil {
+		v.reject(status, err)
+		return
+	}
+	writeEncoded(v.ResponseWriter, status, body)
+}
+
+// reject logs a response that does not match the spec and answers with a 500
+// written by WriteError instead. The mismatch is only logged, as it describes
+// the server rather than the request.
+func (v *responseValidator) reject(status int, err error) {
+	slog.Default().Error("gopenapi: invalid response", "operationId", v.operation.OperationId, "status", status, "error", err)
+	WriteError(v.ResponseWriter, v.request, http.StatusInternalServerError, fmt.Errorf("gopenapi: operation %s returned an invalid response", v.operation.OperationId))
+}
+
+// isJSONMediaType reports whether a Content-Type header value is application/json
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && MediaType(mediaType) == ApplicationJSON
+}
+
+// validateResponses wraps an operation handler so that WriteResponse validates its bodies
+func validateResponses(operation *Operation, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&responseValidator{ResponseWriter: w, operation: operation, request: r}, r)
+	})
+}